@@ -0,0 +1,19 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// -----------------------------------------------------------------------------
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the Postgres database schema",
+}
+
+// -----------------------------------------------------------------------------
+
+func init() {
+	dbMigrateCmd.Flags().StringVar(&cfgFile, "config", "config_flighttracker.toml", "config file")
+	dbStatusCmd.Flags().StringVar(&cfgFile, "config", "config_flighttracker.toml", "config file")
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbStatusCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/db"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// -----------------------------------------------------------------------------
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply the pending schema migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Initialize config
+		initConfig()
+
+		database, errOpen := db.Open(conf.Flighttracker.Postgres)
+		if errOpen != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errOpen,
+			}).Fatal("Unable to connect to the database")
+		}
+		defer database.Close()
+
+		applied, errMigrate := db.Migrate(ctx, database, conf.Flighttracker.Postgres)
+		for _, version := range applied {
+			fmt.Printf("applied migration %d\n", version)
+		}
+		if errMigrate != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errMigrate,
+			}).Fatal("Unable to migrate the schema")
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema up to date")
+		}
+
+		if conf.Flighttracker.Postgres.Partitioning != "" {
+			errPartition := db.Partition(ctx, log, database, conf.Flighttracker.Postgres)
+			if errPartition != nil {
+				log.WithContext(ctx).WithFields(logrus.Fields{
+					"Error": errPartition,
+				}).Fatal("Unable to partition the flight table")
+			}
+			fmt.Printf("current %s flight partitions created\n", conf.Flighttracker.Postgres.Partitioning)
+		}
+	},
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/db"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// -----------------------------------------------------------------------------
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List the schema migrations and their state",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Initialize config
+		initConfig()
+
+		database, errOpen := db.Open(conf.Flighttracker.Postgres)
+		if errOpen != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errOpen,
+			}).Fatal("Unable to connect to the database")
+		}
+		defer database.Close()
+
+		status, errStatus := db.Status(ctx, database, conf.Flighttracker.Postgres)
+		if errStatus != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errStatus,
+			}).Fatal("Unable to read the schema version")
+		}
+
+		for _, migration := range status {
+			state := "pending"
+			if !migration.Enabled {
+				state = "disabled (" + migration.Option + ")"
+			}
+			if !migration.AppliedAt.IsZero() {
+				state = "applied " + migration.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%3d  %-30s  %s\n", migration.Version, state, migration.Description)
+		}
+	},
+}
@@ -0,0 +1,208 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/density"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// default values of the density parameters
+const (
+	densityCell            = 500
+	densityLowAltitudeFeet = 1000
+	densityWidth           = 1024
+)
+
+var (
+	densityOutput string
+	densityQuery  = map[string]*string{}
+)
+
+//densityParameters - the grid and output of a density request
+type densityParameters struct {
+	options density.Options
+	format  string
+	metric  string
+	width   int
+}
+
+// densityCmd represents the density command
+var densityCmd = &cobra.Command{
+	Use:   "density",
+	Short: "Allow to aggregate the stored positions in a grid as GeoJSON or PNG",
+	Long: `Search the positions stored in the database like the /search endpoint and count
+	the positions, flights and low flights by grid cell, i.e. the overflights of a house.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Initialize config
+		initConfig()
+
+		openSearchService()
+		defer closeSearchService()
+
+		query := url.Values{}
+		for name, value := range densityQuery {
+			if *value != "" {
+				query.Set(name, *value)
+			}
+		}
+		params, errParams := parseDensityParameters(query)
+		if errParams != nil {
+			log.WithContext(ctx).Fatal(errParams)
+		}
+		grid, errDensity := densityGrid(ctx, query, params)
+		if errDensity != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errDensity,
+			}).Fatal("Unable to aggregate the positions")
+		}
+
+		var w io.Writer = os.Stdout
+		if densityOutput != "" {
+			file, errCreate := os.Create(densityOutput)
+			if errCreate != nil {
+				log.WithContext(ctx).Fatal(errCreate)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if errWrite := writeDensity(w, grid, params); errWrite != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errWrite,
+			}).Fatal("Unable to write the density")
+		}
+
+		log.WithContext(ctx).WithFields(logrus.Fields{
+			"cells":  len(grid.Cells),
+			"format": params.format,
+		}).Info("Density done")
+	},
+}
+
+//parseDensityParameters - read the cell, shape, lowAltitudeFeet, format, metric and width parameters
+func parseDensityParameters(query url.Values) (densityParameters, error) {
+	params := densityParameters{
+		options: density.Options{Cell: densityCell, Shape: density.ShapeSquare, LowAltitudeFeet: densityLowAltitudeFeet},
+		format:  density.FormatGeoJSON,
+		metric:  density.MetricFlights,
+		width:   densityWidth,
+	}
+
+	if query.Get("cell") != "" {
+		cell, errCell := strconv.ParseFloat(query.Get("cell"), 64)
+		if errCell != nil {
+			return params, &searchError{http.StatusBadRequest, fmt.Sprintf("cell need a number of meters (%s)", errCell.Error())}
+		}
+		params.options.Cell = cell
+	}
+	if query.Get("shape") != "" {
+		params.options.Shape = query.Get("shape")
+	}
+	if query.Get("lowAltitudeFeet") != "" {
+		lowAltitude, errLowAltitude := strconv.ParseInt(query.Get("lowAltitudeFeet"), 10, 64)
+		if errLowAltitude != nil {
+			return params, &searchError{http.StatusBadRequest, fmt.Sprintf("lowAltitudeFeet need a number (%s)", errLowAltitude.Error())}
+		}
+		params.options.LowAltitudeFeet = lowAltitude
+	}
+	if query.Get("format") != "" {
+		params.format = query.Get("format")
+	}
+	if _, errFormat := density.ContentType(params.format); errFormat != nil {
+		return params, &searchError{http.StatusBadRequest, errFormat.Error()}
+	}
+	if query.Get("metric") != "" {
+		params.metric = query.Get("metric")
+	}
+	if _, errMetric := (&density.Cell{}).Value(params.metric); errMetric != nil {
+		return params, &searchError{http.StatusBadRequest, errMetric.Error()}
+	}
+	if query.Get("width") != "" {
+		width, errWidth := strconv.Atoi(query.Get("width"))
+		if errWidth != nil {
+			return params, &searchError{http.StatusBadRequest, fmt.Sprintf("width need a number of pixels (%s)", errWidth.Error())}
+		}
+		params.width = width
+	}
+	if errWidth := density.CheckWidth(params.width); errWidth != nil {
+		return params, &searchError{http.StatusBadRequest, errWidth.Error()}
+	}
+
+	return params, nil
+}
+
+//densityGrid - search the positions and aggregate them in the grid of the searched bbox or zone envelope
+func densityGrid(ctx context.Context, query url.Values, params densityParameters) (*density.Grid, error) {
+	searchQuery := url.Values{}
+	for name, values := range query {
+		searchQuery[name] = values
+	}
+	if searchQuery.Get("altThresholdFeet") == "" {
+		searchQuery.Set("altThresholdFeet", strconv.Itoa(allAltitudesFeet))
+	}
+
+	searchParams, data, errSearch := search(ctx, searchQuery)
+	if errSearch != nil {
+		return nil, errSearch
+	}
+
+	grid, errAggregate := density.Aggregate(data, searchParams.Bbox, params.options)
+	if errAggregate != nil {
+		return nil, &searchError{http.StatusBadRequest, errAggregate.Error()}
+	}
+	return grid, nil
+}
+
+//writeDensity - the grid in the requested format
+func writeDensity(w io.Writer, grid *density.Grid, params densityParameters) error {
+	if params.format == density.FormatPNG {
+		return density.WritePNG(w, grid, params.metric, params.width)
+	}
+	return density.WriteGeoJSON(w, grid)
+}
+
+func init() {
+	densityCmd.Flags().StringVar(&cfgFile, "config", "config_flighttracker.toml", "config file")
+	densityCmd.Flags().StringVar(&densityOutput, "output", "", "output file (default stdout)")
+	for name, usage := range map[string]string{
+		"bbox":             "searched bounding box (latSW,lonSW^latNE,lonNE)",
+		"zone":             "searched monitoring zone name, instead of bbox",
+		"altThresholdFeet": "altitude threshold in feet (default all the positions)",
+		"fromTimeStamp":    "start of the time window (" + searchTimeLayout + ")",
+		"toTimeStamp":      "end of the time window (" + searchTimeLayout + ")",
+		"cell":             "cell size in meter (default 500)",
+		"shape":            "cell shape, square or hexagon (default square)",
+		"lowAltitudeFeet":  "altitude in feet under which a position is low (default 1000)",
+		"format":           "output format, geojson or png (default geojson)",
+		"metric":           "PNG colored metric, flights, positions, lowFlights or lowPositions (default flights)",
+		"width":            "PNG width in pixel (default 1024)",
+	} {
+		densityQuery[name] = densityCmd.Flags().String(name, "", usage)
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/export"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	exportQuery  = map[string]*string{}
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Allow to export the stored flights as GeoJSON, KML or GPX tracks",
+	Long: `Search the flights stored in the database like the /search endpoint and export
+	one track per flight, readable by QGIS, Google Earth or any GPX viewer.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Initialize config
+		initConfig()
+
+		openSearchService()
+		defer closeSearchService()
+
+		if _, errFormat := export.ContentType(exportFormat); errFormat != nil {
+			log.WithContext(ctx).Fatal(errFormat)
+		}
+
+		query := url.Values{}
+		for name, value := range exportQuery {
+			if *value != "" {
+				query.Set(name, *value)
+			}
+		}
+		_, data, errSearch := search(ctx, query)
+		if errSearch != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errSearch,
+			}).Fatal("Unable to search flights")
+		}
+
+		var w io.Writer = os.Stdout
+		if exportOutput != "" {
+			file, errCreate := os.Create(exportOutput)
+			if errCreate != nil {
+				log.WithContext(ctx).Fatal(errCreate)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		tracks := tracking.Reconstruct(data, exportTimeout())
+		if errExport := export.Write(w, exportFormat, tracks); errExport != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errExport,
+			}).Fatal("Unable to export tracks")
+		}
+
+		log.WithContext(ctx).WithFields(logrus.Fields{
+			"positions": len(data),
+			"tracks":    len(tracks),
+			"format":    exportFormat,
+		}).Info("Export done")
+	},
+}
+
+//exportTimeout - the tracking timeout splitting the positions of a flight in several tracks
+func exportTimeout() time.Duration {
+	if conf.Flighttracker.Tracking.Timeout <= 0 {
+		return 300 * time.Second
+	}
+	return time.Duration(conf.Flighttracker.Tracking.Timeout) * time.Second
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&cfgFile, "config", "config_flighttracker.toml", "config file")
+	exportCmd.Flags().StringVar(&exportFormat, "format", export.FormatGeoJSON, "export format (geojson, kml or gpx)")
+	exportCmd.Flags().StringVar(&exportOutput, "output", "", "output file (default stdout)")
+	for name, usage := range map[string]string{
+		"bbox":             "searched bounding box (latSW,lonSW^latNE,lonNE)",
+		"zone":             "searched monitoring zone name, instead of bbox",
+		"altThresholdFeet": "altitude threshold in feet",
+		"fromTimeStamp":    "start of the time window (" + searchTimeLayout + ")",
+		"toTimeStamp":      "end of the time window (" + searchTimeLayout + ")",
+	} {
+		exportQuery[name] = exportCmd.Flags().String(name, "", usage)
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/live"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	//ticks queued by live client before dropping
+	liveBuffer = 8
+	//delay between two SSE comments or WebSocket pings keeping the connection open through the proxies
+	liveKeepAlive = 30 * time.Second
+	//delay to write a message to a WebSocket client
+	liveWriteTimeout = 10 * time.Second
+)
+
+// liveHub - broadcast the ticks of the worker started by /start to the live clients
+var liveHub *live.Hub
+
+// the live map dashboards may be served from another origin
+var liveUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+//parseLiveFilter - read the optional bbox, zone and altThresholdFeet parameters
+func parseLiveFilter(query url.Values) (live.Filter, error) {
+	var filter live.Filter
+
+	if query.Get("bbox") != "" {
+		bbox, errBBox := tools.GetBbox(query.Get("bbox"))
+		if errBBox != nil {
+			return filter, &searchError{http.StatusBadRequest, fmt.Sprintf("bbox have to be well formatted (%s)", errBBox.Error())}
+		}
+		filter.Bbox = &bbox
+	}
+
+	filter.Zone = query.Get("zone")
+	if filter.Zone != "" {
+		found := false
+		for _, zone := range conf.Flighttracker.Zones {
+			found = found || zone.Name == filter.Zone
+		}
+		if !found {
+			return filter, &searchError{http.StatusBadRequest, fmt.Sprintf("zone %s is not configured", filter.Zone)}
+		}
+	}
+
+	if query.Get("altThresholdFeet") != "" {
+		altThreshold, errAltThreshold := strconv.Atoi(query.Get("altThresholdFeet"))
+		if errAltThreshold != nil {
+			return filter, &searchError{http.StatusBadRequest, fmt.Sprintf("need a number (%s)", errAltThreshold.Error())}
+		}
+		filter.AltThresholdFeet = altThreshold
+	}
+
+	return filter, nil
+}
+
+//Live feed of the ticks
+// params : bbox, zone and altThresholdFeet, all optional
+// return : a tick event by refresh over Server-Sent Events, or a JSON message over WebSocket
+func liveService(w http.ResponseWriter, r *http.Request) {
+	filter, errFilter := parseLiveFilter(r.URL.Query())
+	if errFilter != nil {
+		writeSearchError(w, errFilter)
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		liveWebSocket(w, r, filter)
+		return
+	}
+	liveSSE(w, r, filter)
+}
+
+//liveSSE - stream the ticks as "tick" events until the client disconnects
+func liveSSE(w http.ResponseWriter, r *http.Request, filter live.Filter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeSearchError(w, &searchError{http.StatusInternalServerError, "streaming unsupported"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") //disable the nginx buffering
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	client := liveHub.Subscribe(filter)
+	defer liveHub.Unsubscribe(client)
+	keepAlive := time.NewTicker(liveKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case tick := <-client.Ticks():
+			data, errJsonMarshal := json.Marshal(tick)
+			if errJsonMarshal != nil {
+				log.WithContext(r.Context()).Error(errJsonMarshal)
+				continue
+			}
+			fmt.Fprintf(w, "event: tick\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+//liveWebSocket - send the ticks as JSON text messages until the client disconnects
+func liveWebSocket(w http.ResponseWriter, r *http.Request, filter live.Filter) {
+	conn, errUpgrade := liveUpgrader.Upgrade(w, r, nil)
+	if errUpgrade != nil {
+		//the upgrader answered the error
+		log.WithContext(r.Context()).WithFields(logrus.Fields{
+			"Warning": errUpgrade,
+		}).Warning("Unable to open the WebSocket")
+		return
+	}
+	defer conn.Close()
+
+	client := liveHub.Subscribe(filter)
+	defer liveHub.Unsubscribe(client)
+
+	//the client messages are ignored, reading handles the pongs and detects the disconnection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(liveKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case tick := <-client.Ticks():
+			conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			if err := conn.WriteJSON(tick); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(liveWriteTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
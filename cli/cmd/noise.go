@@ -0,0 +1,63 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/noise"
+)
+
+//Noise exposure of a location
+// params : lat, lon, elevation (ground altitude in meter, optional), radius (optional), fromTimeStamp, toTimeStamp
+// return : json with the Lden, Lday, Levening, Lnight, Leq and NAbove indicators and the list of the passes
+func noiseService(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	//the search is canceled when the client goes away
+	ctx := r.Context()
+
+	noiseConf := conf.Flighttracker.Noise
+	params, errParams := parseLocationParameters(r.URL.Query(), noiseConf.Radius)
+	if errParams != nil {
+		writeSearchError(w, errParams)
+		return
+	}
+
+	noiseConf.Radius = params.radius
+	estimator, errEstimator := noise.New(noiseConf)
+	if errEstimator != nil {
+		writeSearchError(w, errEstimator)
+		return
+	}
+
+	//the passes are reconstructed from the positions around the location
+	tracks, errSearch := searchTracks(ctx, params)
+	if errSearch != nil {
+		writeSearchError(w, errSearch)
+		return
+	}
+
+	location := noise.Location{Lat: params.lat, Lon: params.lon, Elevation: params.elevation}
+	exposure := estimator.Estimate(tracks, location, params.from, params.to)
+	result, errJsonMarshal := json.Marshal(exposure)
+	if errJsonMarshal != nil {
+		writeSearchError(w, errJsonMarshal)
+		return
+	}
+	w.Write(result)
+}
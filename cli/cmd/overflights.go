@@ -0,0 +1,59 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+)
+
+// radius in meter of the overflights when no radius is given
+const overflightsRadius = 1000
+
+//Overflights of a location: the closest point of approach of each flight passing nearby
+// params : lat, lon, elevation (ground altitude in meter, optional), radius (optional), fromTimeStamp, toTimeStamp
+// return : json with the number of overflights and, by flight, the time, the ground and 3D distances and the altitude of the closest point of approach
+func overflightsService(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	//the search is canceled when the client goes away
+	ctx := r.Context()
+
+	params, errParams := parseLocationParameters(r.URL.Query(), overflightsRadius)
+	if errParams != nil {
+		writeSearchError(w, errParams)
+		return
+	}
+
+	tracks, errSearch := searchTracks(ctx, params)
+	if errSearch != nil {
+		writeSearchError(w, errSearch)
+		return
+	}
+
+	overflights := tracking.Overflights(tracks, params.lat, params.lon, params.elevation, float64(params.radius), params.from, params.to)
+	result, errJsonMarshal := json.Marshal(struct {
+		NbOverflight int                   `json:"nbOverflight"`
+		Data         []tracking.Overflight `json:"data"`
+	}{len(overflights), overflights})
+	if errJsonMarshal != nil {
+		writeSearchError(w, errJsonMarshal)
+		return
+	}
+	w.Write(result)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"os"
+
+	"github.com/francois-poidevin/flighttracker/internal"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var recordOutput string
+
+// recordCmd represents the record command
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Allow to start tracking of all flights and record raw provider responses",
+	Long: `Same processing as start command, and every raw provider response is saved
+	with its fetch timestamp in a compressed archive, to be replayed with the replay command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Initialize config
+		initConfig()
+
+		errRecord := internal.Record(ctx, log, *conf, recordOutput)
+		if errRecord != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errRecord,
+			}).Error("Error in Record processing")
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	recordCmd.Flags().StringVar(&cfgFile, "config", "config_flighttracker.toml", "config file")
+	recordCmd.Flags().StringVar(&recordOutput, "output", "record.jsonl.gz", "archive file")
+}
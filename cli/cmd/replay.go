@@ -0,0 +1,59 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"os"
+
+	"github.com/francois-poidevin/flighttracker/internal"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayFrom  string
+	replaySpeed float64
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Allow to replay a recorded archive in the configured sinker",
+	Long: `Read the raw provider responses recorded by the record command, unmarshal them
+	and sink them with their original fetch timestamp.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Initialize config
+		initConfig()
+
+		errReplay := internal.Replay(ctx, log, *conf, replayFrom, replaySpeed)
+		if errReplay != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error": errReplay,
+			}).Error("Error in Replay processing")
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&cfgFile, "config", "config_flighttracker.toml", "config file")
+	replayCmd.Flags().StringVar(&replayFrom, "from", "record.jsonl.gz", "archive file to replay")
+	replayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "replay speed factor (1 = original timing, 0 = as fast as possible)")
+}
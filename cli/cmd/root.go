@@ -21,6 +21,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/fatih/structs"
 	"github.com/francois-poidevin/flighttracker/config"
 	defaults "github.com/mcuadros/go-defaults"
 	"github.com/sirupsen/logrus"
@@ -57,7 +58,12 @@ var (
 func init() {
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(startHttpCmd)
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(densityCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(dbCmd)
 }
 func initConfig() {
 	//TODO: refactor this code for better handling env variable in case of docker (env. var. pass to docker image)
@@ -70,8 +76,12 @@ func initConfig() {
 		}
 	}
 
-	switch {
-	case cfgFile != "":
+	// the default values are seeded in viper, so the keys missing from the config file take them
+	defaultConf := &config.Configuration{}
+	defaults.SetDefaults(defaultConf)
+	setViperDefaults(defaultConf, "")
+
+	if cfgFile != "" {
 		// If the config file doesn't exists, let's exit
 		if _, err := os.Stat(cfgFile); os.IsNotExist(err) {
 			log.WithFields(logrus.Fields{
@@ -89,8 +99,6 @@ func initConfig() {
 				"err": err,
 			}).Fatal("Unable to read config")
 		}
-	default:
-		defaults.SetDefaults(conf)
 	}
 
 	if err := viper.Unmarshal(conf); err != nil {
@@ -99,3 +107,19 @@ func initConfig() {
 		}).Fatal("Unable to parse config")
 	}
 }
+
+// setViperDefaults seeds viper with the values of the struct fields, keyed like the environment variables
+func setViperDefaults(o interface{}, prefix string) {
+	delim := "."
+	if prefix == "" {
+		delim = ""
+	}
+	for _, f := range structs.Fields(o) {
+		key := prefix + delim + strings.ToLower(f.Name())
+		if structs.IsStruct(f.Value()) {
+			setViperDefaults(f.Value(), key)
+		} else {
+			viper.SetDefault(key, f.Value())
+		}
+	}
+}
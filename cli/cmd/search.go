@@ -0,0 +1,221 @@
+package cmd
+
+/*
+Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/service"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
+)
+
+const (
+	// time layout of the fromTimeStamp and toTimeStamp parameters
+	searchTimeLayout = "2006-01-02T15:04:05"
+	// altThresholdFeet searching all the positions
+	allAltitudesFeet = 100000
+	// ground speed in kts of the fastest aircraft around a location, limited to 250 kts below 10000 ft
+	paddingSpeedKts = 250
+)
+
+var (
+	searchSvc    app.Service
+	searchParams interface{}
+)
+
+//searchError - a search parameter or processing error with its HTTP status
+type searchError struct {
+	status  int
+	message string
+}
+
+func (e *searchError) Error() string {
+	return e.message
+}
+
+//parseSearchParameters - read the zone or bbox, altThresholdFeet, fromTimeStamp and toTimeStamp parameters
+// return the parameters and the searched area as WKT
+func parseSearchParameters(query url.Values) (parameters, string, error) {
+	var params parameters
+	var areaWKT string
+
+	//Check zone or bbox parameter
+	params.Zone = query.Get("zone")
+	if params.Zone != "" {
+		monitoredZones, errZones := zones.Load(conf.Flighttracker.Zones)
+		if errZones != nil {
+			return params, "", &searchError{http.StatusInternalServerError, fmt.Sprintf("internal server error (%s)", errZones.Error())}
+		}
+		zone, found := zones.Find(monitoredZones, params.Zone)
+		if !found {
+			return params, "", &searchError{http.StatusBadRequest, fmt.Sprintf("zone %s is not configured", params.Zone)}
+		}
+		params.Bbox = zones.Envelope([]zones.Zone{zone})
+		areaWKT = zone.WKT()
+	} else {
+		bbox, errBBox := tools.GetBbox(query.Get("bbox"))
+		if errBBox != nil {
+			return params, "", &searchError{http.StatusBadRequest, fmt.Sprintf("bbox have to be well formatted (%s)", errBBox.Error())}
+		}
+		params.Bbox = bbox
+		areaWKT = tools.BboxToWKT(bbox)
+	}
+
+	//Check threshold parameter
+	altThreshold, errAltThreshold := strconv.Atoi(query.Get("altThresholdFeet"))
+	if errAltThreshold != nil {
+		return params, "", &searchError{http.StatusBadRequest, fmt.Sprintf("need a number (%s)", errAltThreshold.Error())}
+	}
+	params.AltThreshold = altThreshold
+
+	//Check time windows parameters
+	fromTimeStamp, errFromTimeStamp := time.Parse(searchTimeLayout, query.Get("fromTimeStamp"))
+	if errFromTimeStamp != nil {
+		return params, "", &searchError{http.StatusBadRequest, fmt.Sprintf("need a time with layout (%s) - error: %s", searchTimeLayout, errFromTimeStamp.Error())}
+	}
+	params.FromTimeStampParam = fromTimeStamp
+
+	toTimeStamp, errToTimeStamp := time.Parse(searchTimeLayout, query.Get("toTimeStamp"))
+	if errToTimeStamp != nil {
+		return params, "", &searchError{http.StatusBadRequest, fmt.Sprintf("need a time with layout (%s) - error: %s", searchTimeLayout, errToTimeStamp.Error())}
+	}
+	params.ToTimeStampParam = toTimeStamp
+
+	return params, areaWKT, nil
+}
+
+//search - parse the search parameters and call the search service on the database
+func search(ctx context.Context, query url.Values) (parameters, []app.FlightData, error) {
+	params, areaWKT, errParams := parseSearchParameters(query)
+	if errParams != nil {
+		return params, nil, errParams
+	}
+
+	data, errSearch := searchSvc.SearchArea(ctx, searchParams, areaWKT, params.AltThreshold, params.FromTimeStampParam, params.ToTimeStampParam)
+	if errSearch != nil {
+		return params, nil, &searchError{http.StatusInternalServerError, fmt.Sprintf("internal server error (%s)", errSearch.Error())}
+	}
+
+	return params, data, nil
+}
+
+//locationParameters - a location on the ground, a radius around it and a time window
+type locationParameters struct {
+	lat       float64
+	lon       float64
+	elevation float64 //ground altitude in meter
+	radius    int     //meter
+	from      time.Time
+	to        time.Time
+}
+
+//parseLocationParameters - read the lat, lon, elevation (default 0), radius (default radius), fromTimeStamp and toTimeStamp parameters
+func parseLocationParameters(query url.Values, radius int) (locationParameters, error) {
+	params := locationParameters{radius: radius}
+
+	coordinates := map[string]*float64{"lat": &params.lat, "lon": &params.lon, "elevation": &params.elevation}
+	for _, name := range []string{"lat", "lon", "elevation"} {
+		value := query.Get(name)
+		if value == "" && name == "elevation" {
+			continue
+		}
+		number, errNumber := strconv.ParseFloat(value, 64)
+		if errNumber != nil {
+			return params, &searchError{http.StatusBadRequest, fmt.Sprintf("%s need a number (%s)", name, errNumber.Error())}
+		}
+		*coordinates[name] = number
+	}
+	if params.lat < -90 || params.lat > 90 || params.lon < -180 || params.lon > 180 {
+		return params, &searchError{http.StatusBadRequest, "lat or lon out of range"}
+	}
+
+	if query.Get("radius") != "" {
+		radius, errRadius := strconv.Atoi(query.Get("radius"))
+		if errRadius != nil || radius <= 0 {
+			return params, &searchError{http.StatusBadRequest, "radius need a positive number of meters"}
+		}
+		params.radius = radius
+	}
+
+	for name, value := range map[string]*time.Time{"fromTimeStamp": &params.from, "toTimeStamp": &params.to} {
+		t, errTime := time.Parse(searchTimeLayout, query.Get(name))
+		if errTime != nil {
+			return params, &searchError{http.StatusBadRequest, fmt.Sprintf("need a time with layout (%s) - error: %s", searchTimeLayout, errTime.Error())}
+		}
+		*value = t
+	}
+	if !params.to.After(params.from) {
+		return params, &searchError{http.StatusBadRequest, "toTimeStamp must be after fromTimeStamp"}
+	}
+
+	return params, nil
+}
+
+//searchTracks - the tracks reconstructed from the positions searched around the location, at all altitudes
+// the bbox is padded by the distance between two positions and the time window by the refresh, so the segments
+// crossing the radius or the window edges keep their positions on both sides
+func searchTracks(ctx context.Context, params locationParameters) ([]app.Track, error) {
+	refresh := time.Duration(conf.Flighttracker.Refresh) * time.Second
+	spacing := paddingSpeedKts * app.KTSKMH / 3.6 * refresh.Seconds()
+
+	query := url.Values{}
+	bbox := tools.BboxAround(params.lat, params.lon, float64(params.radius)+spacing)
+	query.Set("bbox", fmt.Sprintf("%f,%f^%f,%f", bbox.LatSW, bbox.LonSW, bbox.LatNE, bbox.LonNE))
+	query.Set("altThresholdFeet", strconv.Itoa(allAltitudesFeet))
+	query.Set("fromTimeStamp", params.from.Add(-refresh).Format(searchTimeLayout))
+	query.Set("toTimeStamp", params.to.Add(refresh).Format(searchTimeLayout))
+	_, data, errSearch := search(ctx, query)
+	if errSearch != nil {
+		return nil, errSearch
+	}
+	return tracking.Reconstruct(data, exportTimeout()), nil
+}
+
+//openSearchService - the search service of the database filled by the configured sinker, shared by the searches
+func openSearchService() {
+	if !conf.HasSinkertype("DB") && conf.HasSinkertype("SQLITE") {
+		searchSvc, searchParams = service.NewSQLite(log), conf.Flighttracker.Sqlite
+		return
+	}
+	searchSvc, searchParams = service.New(log), conf.Flighttracker.Postgres
+}
+
+//closeSearchService - close the db connection of the search service
+func closeSearchService() {
+	if closer, ok := searchSvc.(app.Closer); ok {
+		closer.Close()
+	}
+}
+
+//writeSearchError - write the error as a JSON message with its HTTP status
+func writeSearchError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errSearch, ok := err.(*searchError); ok {
+		status = errSearch.status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(fmt.Sprintf(`{"message": %q}`, err.Error())))
+}
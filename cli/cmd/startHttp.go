@@ -22,13 +22,17 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/francois-poidevin/flighttracker/internal"
 	"github.com/francois-poidevin/flighttracker/internal/app"
-	"github.com/francois-poidevin/flighttracker/internal/app/service"
+	"github.com/francois-poidevin/flighttracker/internal/app/density"
+	"github.com/francois-poidevin/flighttracker/internal/app/export"
+	"github.com/francois-poidevin/flighttracker/internal/app/live"
 	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+	"github.com/francois-poidevin/flighttracker/internal/app/web"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -41,6 +45,7 @@ var (
 
 type parameters struct {
 	Bbox               tools.Bbox `json:"bbox"`
+	Zone               string     `json:"zone,omitempty"`
 	AltThreshold       int        `json:"altThreshold"`
 	FromTimeStampParam time.Time  `json:"fromTimeStampParam"`
 	ToTimeStampParam   time.Time  `json:"toTimeStampParam"`
@@ -63,16 +68,30 @@ var startHttpCmd = &cobra.Command{
 		// Initialize config
 		initConfig()
 
-		if conf.Flighttracker.Sinkertype != "DB" {
-			log.Fatal("Service can't be started without a Database sinker, please change config file")
+		if !conf.HasSinkertype("DB") && !conf.HasSinkertype("SQLITE") {
+			log.Fatal("Service can't be started without a Database sinker (DB or SQLITE), please change config file")
 		}
 
+		openSearchService()
+		defer closeSearchService()
+
+		liveHub = live.New(log, liveBuffer)
+
 		r := mux.NewRouter()
 
 		api := r.PathPrefix("/api/v1").Subrouter()
 		api.HandleFunc("/start", startService).Methods(http.MethodGet)
 		api.HandleFunc("/stop", stopService).Methods(http.MethodGet)
 		api.HandleFunc("/search", searchService).Methods(http.MethodGet)
+		api.HandleFunc("/export", exportService).Methods(http.MethodGet)
+		api.HandleFunc("/density", densityService).Methods(http.MethodGet)
+		api.HandleFunc("/noise", noiseService).Methods(http.MethodGet)
+		api.HandleFunc("/overflights", overflightsService).Methods(http.MethodGet)
+		api.HandleFunc("/live", liveService).Methods(http.MethodGet)
+		api.HandleFunc("/area", areaService).Methods(http.MethodGet)
+
+		//map UI on /
+		r.PathPrefix("/").Handler(web.Handler())
 
 		//Start http server here
 		log.Fatal(http.ListenAndServe(":8080", r))
@@ -92,7 +111,7 @@ func startService(w http.ResponseWriter, r *http.Request) {
 
 		ctx, cancel = context.WithCancel(context.Background())
 		go func() {
-			errExec := internal.Execute(ctx, log, *conf)
+			errExec := internal.Execute(ctx, log, *conf, liveHub)
 			if errExec != nil {
 				log.WithContext(ctx).WithFields(logrus.Fields{
 					"Error": errExec,
@@ -122,80 +141,119 @@ func stopService(w http.ResponseWriter, r *http.Request) {
 }
 
 //Search on collecting data
-// params : BBox, altitude threshold, time windows (from, to)
+// params : BBox or zone, altitude threshold, time windows (from, to)
 // return : json
 func searchService(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	query := r.URL.Query()
-	//Check bbox parameter
-	bboxParam := query.Get("bbox")
-	bbox, errBBox := tools.GetBbox(bboxParam)
-	if errBBox != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(fmt.Sprintf(`{"message": "bbox have to be well formatted (%s)"}`, errBBox.Error())))
+	//the search is canceled when the client goes away
+	params, data, errSearch := search(r.Context(), r.URL.Query())
+	if errSearch != nil {
+		writeSearchError(w, errSearch)
 		return
 	}
-	//Check threshold parameter
-	altThresholdParam := query.Get("altThresholdFeet")
-	altThreshold, errAltThreshold := strconv.Atoi(altThresholdParam)
-	if errAltThreshold != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(fmt.Sprintf(`{"message": "need a number (%s)"}`, errAltThreshold.Error())))
+
+	response := response{
+		Parameters: params,
+		NbFlight:   len(data),
+		Data:       data,
+	}
+
+	result, errJsonMarshal := json.Marshal(response)
+	if errJsonMarshal != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"message": "internal server error (%s)"}`, errJsonMarshal.Error())))
 		return
 	}
 
-	//Check time windows parameters
-	layout := "2006-01-02T15:04:05"
-	fromTimeStampParam := query.Get("fromTimeStamp")
-	fromTimeStamp, errFromTimeStamp := time.Parse(layout, fromTimeStampParam)
+	w.Write(result)
+}
 
-	if errFromTimeStamp != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(fmt.Sprintf(`{"message": "need a time with layout (%s) - error: %s"}`, layout, errFromTimeStamp.Error())))
+//Export collecting data as tracks
+// params : same as search, format (geojson, kml or gpx)
+// return : one feature per flight in the format
+func exportService(w http.ResponseWriter, r *http.Request) {
+	//the search is canceled when the client goes away
+	ctx := r.Context()
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatGeoJSON
+	}
+	contentType, errFormat := export.ContentType(format)
+	if errFormat != nil {
+		writeSearchError(w, &searchError{http.StatusBadRequest, errFormat.Error()})
 		return
 	}
-	toTimeStampParam := query.Get("toTimeStamp")
-	toTimeStamp, errToTimeStamp := time.Parse(layout, toTimeStampParam)
 
-	if errToTimeStamp != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(fmt.Sprintf(`{"message": "need a time with layout (%s) - error: %s"}`, layout, errToTimeStamp.Error())))
+	_, data, errSearch := search(ctx, query)
+	if errSearch != nil {
+		writeSearchError(w, errSearch)
 		return
 	}
 
-	//call logical for searching in DB
-	searchSvc := service.New(log)
-	//TODO: remove db connection at the starting of the startHttp service, and then pass to search service
-	data, errSearch := searchSvc.Search(ctx, *&conf.Flighttracker.Postgres, bbox, altThreshold, fromTimeStamp, toTimeStamp)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="flighttracker.%s"`, format))
+	errExport := export.Write(w, format, tracking.Reconstruct(data, exportTimeout()))
+	if errExport != nil {
+		log.WithContext(ctx).WithFields(logrus.Fields{
+			"Error": errExport,
+		}).Error("Unable to export tracks")
+	}
+}
 
-	if errSearch != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf(`{"message": "internal server error (%s)"}`, errAltThreshold.Error())))
+//Density of the collecting data
+// params : same as search (altThresholdFeet optional), cell, shape, lowAltitudeFeet, format (geojson or png), metric and width for png
+// return : the counts by grid cell as GeoJSON, or a PNG raster of the bbox
+func densityService(w http.ResponseWriter, r *http.Request) {
+	//the search is canceled when the client goes away
+	ctx := r.Context()
+	query := r.URL.Query()
+	params, errParams := parseDensityParameters(query)
+	if errParams != nil {
+		writeSearchError(w, errParams)
 		return
 	}
-
-	parameters := parameters{
-		Bbox:               bbox,
-		AltThreshold:       altThreshold,
-		FromTimeStampParam: fromTimeStamp,
-		ToTimeStampParam:   toTimeStamp,
+	grid, errDensity := densityGrid(ctx, query, params)
+	if errDensity != nil {
+		writeSearchError(w, errDensity)
+		return
 	}
 
-	response := response{
-		Parameters: parameters,
-		NbFlight:   len(data),
-		Data:       data,
+	contentType, _ := density.ContentType(params.format)
+	w.Header().Set("Content-Type", contentType)
+	//georeferencing of the PNG, i.e. for a Leaflet imageOverlay
+	w.Header().Set("X-Bbox", fmt.Sprintf("%f,%f,%f,%f", grid.Bbox.LonSW, grid.Bbox.LatSW, grid.Bbox.LonNE, grid.Bbox.LatNE))
+	errWrite := writeDensity(w, grid, params)
+	if errWrite != nil {
+		log.WithContext(ctx).WithFields(logrus.Fields{
+			"Error": errWrite,
+		}).Error("Unable to write the density")
 	}
+}
 
-	result, errJsonMarshal := json.Marshal(response)
-	if errJsonMarshal != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf(`{"message": "internal server error (%s)"}`, errJsonMarshal.Error())))
-		return
+//Monitored area drawn by the map UI
+// return : GeoJSON FeatureCollection of the configured bbox or zones
+func areaService(w http.ResponseWriter, r *http.Request) {
+	var area web.Area
+	if len(conf.Flighttracker.Zones) == 0 {
+		bbox, errBBox := tools.GetBbox(conf.Flighttracker.Bbox)
+		if errBBox != nil {
+			writeSearchError(w, errBBox)
+			return
+		}
+		area = web.NewArea(bbox, nil)
+	} else {
+		monitoredZones, errZones := zones.Load(conf.Flighttracker.Zones)
+		if errZones != nil {
+			writeSearchError(w, errZones)
+			return
+		}
+		area = web.NewArea(zones.Envelope(monitoredZones), monitoredZones)
 	}
 
-	w.Write(result)
+	w.Header().Set("Content-Type", "application/geo+json")
+	json.NewEncoder(w).Encode(area)
 }
 
 func init() {
@@ -1,8 +1,25 @@
 package config
 
 import (
+	"reflect"
+	"strings"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/dedup"
+	"github.com/francois-poidevin/flighttracker/internal/app/noise"
+	"github.com/francois-poidevin/flighttracker/internal/app/providers/adsbx"
+	"github.com/francois-poidevin/flighttracker/internal/app/providers/opensky"
+	"github.com/francois-poidevin/flighttracker/internal/app/providers/sbs"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/bus"
 	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/db"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/email"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/fanout"
 	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/file"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/mqtt"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/sqlite"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/webhook"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
 )
 
 // Configuration contains conectivity settings
@@ -12,10 +29,60 @@ type Configuration struct {
 	} `toml:"Log" comment:"###############################\n Logs Settings \n##############################"`
 
 	Flighttracker struct {
-		Bbox       string             `toml:"bbox" default:"43.52,1.32^43.70,1.69" comment:"tracking bbox (Lat/Lon)"`
-		Refresh    int                `toml:"refresh" default:"5" comment:"refresh timing in second"`
-		Sinkertype string             `toml:"sinkertype" default:"FILE" comment:"the sinker Type use (STDOUT|FILE|DB)"`
-		File       file.Configuration `toml:"file" comment:"###############################\n file sinker configuration \n##############################"`
-		Postgres   db.Configuration   `toml:"postgres" comment:"###############################\n postgres sinker configuration \n##############################"`
+		Bbox       string                 `toml:"bbox" default:"43.52,1.32^43.70,1.69" comment:"tracking bbox (Lat/Lon), ignored when zones are configured"`
+		Zones      []zones.Configuration  `toml:"zones" comment:"named monitoring zones ([[Flighttracker.zones]] tables), the flights outside of the zones are dropped"`
+		Refresh    int                    `toml:"refresh" default:"5" comment:"refresh timing in second"`
+		Provider   string                 `toml:"provider" default:"FR24" comment:"the flight data provider use (FR24|OPENSKY|ADSBX|SBS)"`
+		Sinkertype string                 `toml:"sinkertype" default:"FILE" comment:"the sinker Type use (STDOUT|FILE|DB|SQLITE|NATS|MQTT|WEBHOOK|EMAIL), several comma separated sinkers receive each tick concurrently (i.e. DB,STDOUT)"`
+		Dedup      dedup.Configuration    `toml:"dedup" comment:"###############################\n positions deduplication configuration \n##############################"`
+		Tracking   tracking.Configuration `toml:"tracking" comment:"###############################\n flight tracks configuration \n##############################"`
+		Rules      rules.Configuration    `toml:"rules" comment:"###############################\n illegal flight rules configuration \n##############################"`
+		Noise      noise.Configuration    `toml:"noise" comment:"###############################\n noise exposure estimation configuration \n##############################"`
+		Opensky    opensky.Configuration  `toml:"opensky" comment:"###############################\n OpenSky provider configuration \n##############################"`
+		Adsbx      adsbx.Configuration    `toml:"adsbx" comment:"###############################\n aircraft.json (readsb/ADS-B Exchange) provider configuration \n##############################"`
+		Sbs        sbs.Configuration      `toml:"sbs" comment:"###############################\n SBS-1 BaseStation (port 30003) provider configuration \n##############################"`
+		Fanout     fanout.Configuration   `toml:"fanout" comment:"###############################\n several sinkers configuration \n##############################"`
+		File       file.Configuration     `toml:"file" comment:"###############################\n file sinker configuration \n##############################"`
+		Postgres   db.Configuration       `toml:"postgres" comment:"###############################\n postgres sinker configuration \n##############################"`
+		Sqlite     sqlite.Configuration   `toml:"sqlite" comment:"###############################\n SQLite embedded sinker configuration \n##############################"`
+		Nats       bus.Configuration      `toml:"nats" comment:"###############################\n NATS message bus sinker configuration \n##############################"`
+		Mqtt       mqtt.Configuration     `toml:"mqtt" comment:"###############################\n MQTT sinker configuration \n##############################"`
+		Webhook    webhook.Configuration  `toml:"webhook" comment:"###############################\n webhook alerting sinker configuration \n##############################"`
+		Email      email.Configuration    `toml:"email" comment:"###############################\n email alerting sinker configuration \n##############################"`
+		Sections   map[string]interface{} `toml:"-" structs:"-" mapstructure:",remain"` //sections of the sinkers without field here, decoded by the sinkers registry
 	} `toml:"Flighttracker" comment:"###############################\n Flighttracker Settings \n##############################"`
 }
+
+// Sinkertypes - the configured sinker types
+func (c Configuration) Sinkertypes() []string {
+	var result []string
+	for _, sinkerType := range strings.Split(c.Flighttracker.Sinkertype, ",") {
+		if sinkerType = strings.ToUpper(strings.TrimSpace(sinkerType)); sinkerType != "" {
+			result = append(result, sinkerType)
+		}
+	}
+	return result
+}
+
+// HasSinkertype - true if the sinker type is configured
+func (c Configuration) HasSinkertype(sinkerType string) bool {
+	for _, configured := range c.Sinkertypes() {
+		if configured == sinkerType {
+			return true
+		}
+	}
+	return false
+}
+
+// Section - the configuration section of a sinker: the typed field with this toml name,
+// or the raw TOML table of a sinker registered without field in Configuration
+func (c Configuration) Section(name string) interface{} {
+	value := reflect.ValueOf(c.Flighttracker)
+	for idx := 0; idx < value.NumField(); idx++ {
+		tag := strings.Split(value.Type().Field(idx).Tag.Get("toml"), ",")[0]
+		if tag != "-" && strings.EqualFold(tag, name) {
+			return value.Field(idx).Interface()
+		}
+	}
+	return c.Flighttracker.Sections[strings.ToLower(name)]
+}
@@ -9,25 +9,46 @@ import (
 
 //FlightData - storage structure for flightRadar24 API response
 type FlightData struct {
-	FlightID         string  `json:"flightID"`
-	ICAO24BITADDRESS string  `json:"ICAO24BITADDRESS"`
-	Lat              float64 `json:"Lat"`
-	Lon              float64 `json:"Lon"`
-	Track            int64   `json:"Track"` //degree to the destination
-	Altitude         int64   `json:"Altitude"`
-	GroundSpeed      int64   `json:"GroundSpeed"` //kts 1kts => 1.852 kmh
-	Unknown1         string  `json:"Unknown1"`    //not describe yet
-	TranspondeurType string  `json:"TranspondeurType"`
-	AircraftType     string  `json:"AircraftType"`
-	Immatriculation1 string  `json:"Immatriculation1"`
-	TimeStamp        float64 `json:"TimeStamp"`
-	Origine          string  `json:"Origine"`
-	Destination      string  `json:"Destination"`
-	Unknown2         string  `json:"Unknown2"`
-	VerticalSpeed    int64   `json:"VerticalSpeed"`
-	Immatriculation2 string  `json:"Immatriculation2"`
-	Hint             string  `json:"Hint"`
-	Company          string  `json:"Company"`
+	FlightID         string   `json:"flightID"`
+	ICAO24BITADDRESS string   `json:"ICAO24BITADDRESS"`
+	Lat              float64  `json:"Lat"`
+	Lon              float64  `json:"Lon"`
+	Track            int64    `json:"Track"` //degree to the destination
+	Altitude         int64    `json:"Altitude"`
+	GroundSpeed      int64    `json:"GroundSpeed"` //kts 1kts => 1.852 kmh
+	Unknown1         string   `json:"Unknown1"`    //not describe yet
+	TranspondeurType string   `json:"TranspondeurType"`
+	AircraftType     string   `json:"AircraftType"`
+	Immatriculation1 string   `json:"Immatriculation1"`
+	TimeStamp        float64  `json:"TimeStamp"`
+	Origine          string   `json:"Origine"`
+	Destination      string   `json:"Destination"`
+	Unknown2         string   `json:"Unknown2"`
+	VerticalSpeed    int64    `json:"VerticalSpeed"`
+	Immatriculation2 string   `json:"Immatriculation2"`
+	Hint             string   `json:"Hint"`
+	Company          string   `json:"Company"`
+	Zones            []string `json:"Zones,omitempty"` //names of the monitoring zones the flight is in
+}
+
+//Track - successive positions of the same flight
+type Track struct {
+	FlightID         string       `json:"flightID"`
+	ICAO24BITADDRESS string       `json:"ICAO24BITADDRESS"`
+	Start            time.Time    `json:"start"`
+	End              time.Time    `json:"end"`
+	MinAltitude      int64        `json:"minAltitude"`    //feet
+	MaxGroundSpeed   int64        `json:"maxGroundSpeed"` //kts
+	Positions        []FlightData `json:"positions"`      //ordered by TimeStamp
+}
+
+//WKT - LINESTRING of the track positions, POINT for a single position
+func (t Track) WKT() string {
+	points := make([][2]float64, 0, len(t.Positions))
+	for _, position := range t.Positions {
+		points = append(points, [2]float64{position.Lon, position.Lat})
+	}
+	return tools.LineToWKT(points)
 }
 
 const (
@@ -36,11 +57,34 @@ const (
 	KTSKMH      = 1.852
 )
 
+//Sinker - write the flights of each tick, created and initialized with its typed configuration by the sinkers registry
 type Sinker interface {
-	Init(ctx context.Context, params interface{}) error
 	Sink(ctx context.Context, t time.Time, data []FlightData) error
 }
 
+type Provider interface {
+	Init(ctx context.Context, params interface{}) error
+	Fetch(ctx context.Context, bbox tools.Bbox) ([]FlightData, error)
+}
+
+//TrackSinker - a Sinker receiving the completed tracks
+type TrackSinker interface {
+	SinkTracks(ctx context.Context, t time.Time, tracks []Track) error
+}
+
+//Closer - a Sinker finishing its pending writes when the worker stops
+type Closer interface {
+	Close()
+}
+
+//RawProvider - a Provider exposing the raw response body, allowing to record and replay it
+type RawProvider interface {
+	Provider
+	GetRawData(ctx context.Context, bbox tools.Bbox) ([]byte, error)
+	UnMarshalByte(ctx context.Context, byt []byte, bbox tools.Bbox) ([]FlightData, error)
+}
+
 type Service interface {
 	Search(ctx context.Context, params interface{}, bbox tools.Bbox, altThresholdFeet int, fromTimeStamp, toTimeStamp time.Time) ([]FlightData, error)
+	SearchArea(ctx context.Context, params interface{}, areaWKT string, altThresholdFeet int, fromTimeStamp, toTimeStamp time.Time) ([]FlightData, error)
 }
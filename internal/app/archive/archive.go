@@ -0,0 +1,93 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+//Record - a raw provider response body with its fetch timestamp
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	Provider  string    `json:"provider"`
+	Bbox      string    `json:"bbox"`
+	Body      []byte    `json:"body"`
+}
+
+//Writer - append records to a gzip compressed archive (one JSON record per line)
+type Writer struct {
+	f  *os.File
+	gz *gzip.Writer
+}
+
+func Create(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{f: f, gz: gzip.NewWriter(f)}, nil
+}
+
+// Write flushes each record so an interrupted recording stays readable
+func (w *Writer) Write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := w.gz.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return w.gz.Flush()
+}
+
+func (w *Writer) Close() error {
+	if err := w.gz.Close(); err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
+
+//Reader - read records from an archive in the recording order
+type Reader struct {
+	f       *os.File
+	gz      *gzip.Reader
+	scanner *bufio.Scanner
+}
+
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	scanner := bufio.NewScanner(gz)
+	//a single response body can be several MB for large bbox
+	scanner.Buffer(make([]byte, 0, 64*1024), 256*1024*1024)
+	return &Reader{f: f, gz: gz, scanner: scanner}, nil
+}
+
+// Next returns io.EOF when all the records have been read
+func (r *Reader) Next() (Record, error) {
+	var record Record
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil && err != io.ErrUnexpectedEOF {
+			return record, err
+		}
+		return record, io.EOF
+	}
+	err := json.Unmarshal(r.scanner.Bytes(), &record)
+	return record, err
+}
+
+func (r *Reader) Close() error {
+	r.gz.Close()
+	return r.f.Close()
+}
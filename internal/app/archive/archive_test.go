@@ -0,0 +1,66 @@
+package archive
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "record.gz")
+	start := time.Date(2021, 7, 22, 8, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Timestamp: start, Provider: "ADSBX", Bbox: "43.520000,1.320000^43.700000,1.690000", Body: []byte(`{"aircraft":[]}`)},
+		{Timestamp: start.Add(5 * time.Second), Provider: "ADSBX", Bbox: "43.520000,1.320000^43.700000,1.690000", Body: []byte("binary\x00\xff")},
+	}
+
+	writer, errCreate := Create(path)
+	if errCreate != nil {
+		t.Fatal(errCreate)
+	}
+	for _, record := range records {
+		if errWrite := writer.Write(record); errWrite != nil {
+			t.Fatal(errWrite)
+		}
+	}
+
+	//an interrupted recording, not closed yet, is readable
+	if read := readAll(t, path); !reflect.DeepEqual(read, records) {
+		t.Errorf("unexpected records before close %+v", read)
+	}
+
+	if errClose := writer.Close(); errClose != nil {
+		t.Fatal(errClose)
+	}
+	if read := readAll(t, path); !reflect.DeepEqual(read, records) {
+		t.Errorf("unexpected records %+v", read)
+	}
+}
+
+func TestOpenNotArchive(t *testing.T) {
+	if _, errOpen := Open("archive.go"); errOpen == nil {
+		t.Error("expected an error opening a file which is not gzip compressed")
+	}
+}
+
+func readAll(t *testing.T, path string) []Record {
+	reader, errOpen := Open(path)
+	if errOpen != nil {
+		t.Fatal(errOpen)
+	}
+	defer reader.Close()
+
+	var result []Record
+	for {
+		record, errNext := reader.Next()
+		if errNext == io.EOF {
+			return result
+		}
+		if errNext != nil {
+			t.Fatal(errNext)
+		}
+		result = append(result, record)
+	}
+}
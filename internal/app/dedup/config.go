@@ -0,0 +1,7 @@
+package dedup
+
+// Configuration settings for the deduplication of positions already sunk
+type Configuration struct {
+	Enabled bool `toml:"enabled" default:"true" comment:"drop the positions (FlightID and source TimeStamp) already written by the database sinkers, a provider can return the same stale position at each tick"`
+	Size    int  `toml:"size" default:"100000" comment:"number of positions kept in memory, the oldest ones are forgotten first"`
+}
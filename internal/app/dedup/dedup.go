@@ -0,0 +1,78 @@
+package dedup
+
+import (
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+// DefaultSize - cache size used when the configured one is not positive
+const DefaultSize = 100000
+
+type key struct {
+	flightID  string
+	timeStamp float64
+}
+
+//Cache - bounded set of the positions already seen, first in first out
+type Cache struct {
+	seen map[key]struct{}
+	ring []key
+	next int
+}
+
+func New(size int) *Cache {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Cache{seen: make(map[key]struct{}, size), ring: make([]key, 0, size)}
+}
+
+// Filter returns the flights not sunk yet, a position repeated in data is returned once
+func (c *Cache) Filter(data []app.FlightData) []app.FlightData {
+	result := make([]app.FlightData, 0, len(data))
+	kept := map[key]struct{}{}
+	for _, flight := range data {
+		k := keyOf(flight)
+		if _, ok := kept[k]; ok || c.contains(k) {
+			continue
+		}
+		kept[k] = struct{}{}
+		result = append(result, flight)
+	}
+	return result
+}
+
+// Add remembers the flights once sunk, the positions of a failed sink are not remembered
+// and are written again by the next tick
+func (c *Cache) Add(data []app.FlightData) {
+	for _, flight := range data {
+		if k := keyOf(flight); !c.contains(k) {
+			c.add(k)
+		}
+	}
+}
+
+func (c *Cache) contains(k key) bool {
+	_, ok := c.seen[k]
+	return ok
+}
+
+// keyOf - the ICAO address identifies the flight when the provider gives no flight identifier
+func keyOf(flight app.FlightData) key {
+	k := key{flightID: flight.FlightID, timeStamp: flight.TimeStamp}
+	if k.flightID == "" {
+		k.flightID = flight.ICAO24BITADDRESS
+	}
+	return k
+}
+
+// add evicts the oldest key when the cache is full
+func (c *Cache) add(k key) {
+	if len(c.ring) < cap(c.ring) {
+		c.ring = append(c.ring, k)
+	} else {
+		delete(c.seen, c.ring[c.next])
+		c.ring[c.next] = k
+		c.next = (c.next + 1) % len(c.ring)
+	}
+	c.seen[k] = struct{}{}
+}
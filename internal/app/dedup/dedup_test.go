@@ -0,0 +1,80 @@
+package dedup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+func position(id string, timeStamp float64) app.FlightData {
+	return app.FlightData{FlightID: id, TimeStamp: timeStamp}
+}
+
+func ids(data []app.FlightData) []string {
+	result := []string{}
+	for _, flight := range data {
+		result = append(result, flight.FlightID)
+	}
+	return result
+}
+
+func TestRepeatedKeys(t *testing.T) {
+	cache := New(10)
+	noID := app.FlightData{ICAO24BITADDRESS: "39856C", TimeStamp: 1}
+	tick := []app.FlightData{position("A", 1), position("B", 1), position("A", 1), noID}
+
+	//a position repeated in a tick is kept once
+	filtered := cache.Filter(tick)
+	if !reflect.DeepEqual(ids(filtered), []string{"A", "B", ""}) {
+		t.Fatalf("expected A, B and the flight without identifier, got %v", ids(filtered))
+	}
+
+	//not remembered until added: the sink failed
+	if filtered := cache.Filter(tick); len(filtered) != 3 {
+		t.Fatalf("expected the positions not sunk to be kept, got %v", ids(filtered))
+	}
+
+	cache.Add(filtered)
+	next := []app.FlightData{position("A", 1), position("A", 2), position("B", 1), noID, {ICAO24BITADDRESS: "39856C", TimeStamp: 2}}
+	filtered = cache.Filter(next)
+	if !reflect.DeepEqual(ids(filtered), []string{"A", ""}) || filtered[0].TimeStamp != 2 || filtered[1].TimeStamp != 2 {
+		t.Errorf("expected the new positions of A and 39856C, got %+v", filtered)
+	}
+
+	//adding twice doesn't use more room in the cache
+	cache.Add(filtered)
+	cache.Add(filtered)
+	if len(cache.ring) != 5 || len(cache.seen) != 5 {
+		t.Errorf("expected 5 remembered positions, got %d / %d", len(cache.ring), len(cache.seen))
+	}
+}
+
+func TestRingEviction(t *testing.T) {
+	cache := New(3)
+	for timeStamp := 1.0; timeStamp <= 5; timeStamp++ {
+		cache.Add([]app.FlightData{position("A", timeStamp)})
+	}
+
+	//the 2 oldest positions are forgotten
+	filtered := cache.Filter([]app.FlightData{position("A", 1), position("A", 2), position("A", 3), position("A", 4), position("A", 5)})
+	if len(filtered) != 2 || filtered[0].TimeStamp != 1 || filtered[1].TimeStamp != 2 {
+		t.Errorf("expected the positions 1 and 2, got %+v", filtered)
+	}
+	if len(cache.seen) != 3 || len(cache.ring) != 3 {
+		t.Errorf("expected 3 remembered positions, got %d / %d", len(cache.seen), len(cache.ring))
+	}
+
+	//the eviction keeps going round
+	cache.Add([]app.FlightData{position("A", 6), position("A", 7)})
+	filtered = cache.Filter([]app.FlightData{position("A", 4), position("A", 5), position("A", 6), position("A", 7)})
+	if len(filtered) != 1 || filtered[0].TimeStamp != 4 {
+		t.Errorf("expected the position 4, got %+v", filtered)
+	}
+}
+
+func TestDefaultSize(t *testing.T) {
+	if cache := New(0); cap(cache.ring) != DefaultSize {
+		t.Errorf("expected the default size, got %d", cap(cache.ring))
+	}
+}
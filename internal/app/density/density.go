@@ -0,0 +1,198 @@
+package density
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+const (
+	ShapeSquare  = "square"
+	ShapeHexagon = "hexagon"
+
+	// meters by degree of latitude, the grid is computed in a local equirectangular projection of the bbox
+	meterByDegree = 111320.0
+	// maximum number of cells of a grid
+	maxCells = 1000000
+)
+
+//Options - the grid of the aggregation
+type Options struct {
+	Cell            float64 //cell size in meter: side of the squares, distance between the opposite sides of the hexagons
+	Shape           string  //square or hexagon
+	LowAltitudeFeet int64   //altitude under which a position is counted as low
+}
+
+//Cell - the positions aggregated in a grid cell
+type Cell struct {
+	Polygon            tools.Polygon
+	Positions          int
+	Flights            int
+	MedianAltitudeFeet int64
+	LowPositions       int
+	LowFlights         int
+	altitudes          []int64
+	flights            map[string]bool
+	lowFlights         map[string]bool
+}
+
+type key struct {
+	a, b int
+}
+
+//Grid - the positions aggregated in the cells of the bbox
+type Grid struct {
+	Bbox    tools.Bbox
+	Options Options
+	Cells   []*Cell //not empty cells
+	cells   map[key]*Cell
+	meterX  float64 //meters by degree of longitude
+	width   float64 //bbox size in meter
+	height  float64
+}
+
+// Aggregate the positions inside the bbox in a grid of cells
+func Aggregate(data []app.FlightData, bbox tools.Bbox, options Options) (*Grid, error) {
+	if bbox.LatNE <= bbox.LatSW || bbox.LonNE <= bbox.LonSW {
+		return nil, errors.New("bbox malformed - the SW corner must be at the south west of the NE corner")
+	}
+	if options.Cell <= 0 {
+		return nil, errors.New("cell size must be positive")
+	}
+	if options.Shape == "" {
+		options.Shape = ShapeSquare
+	}
+	if options.Shape != ShapeSquare && options.Shape != ShapeHexagon {
+		return nil, fmt.Errorf("shape %s unknown - need %s or %s", options.Shape, ShapeSquare, ShapeHexagon)
+	}
+
+	grid := &Grid{
+		Bbox:    bbox,
+		Options: options,
+		cells:   map[key]*Cell{},
+		meterX:  meterByDegree * math.Cos((bbox.LatSW+bbox.LatNE)/2*math.Pi/180),
+	}
+	grid.width = (bbox.LonNE - bbox.LonSW) * grid.meterX
+	grid.height = (bbox.LatNE - bbox.LatSW) * meterByDegree
+	if (grid.width/options.Cell+1)*(grid.height/options.Cell+1) > maxCells {
+		return nil, fmt.Errorf("cell size %.0fm too small for the bbox, more than %d cells", options.Cell, maxCells)
+	}
+
+	for _, flight := range data {
+		if flight.Lat < bbox.LatSW || flight.Lat > bbox.LatNE || flight.Lon < bbox.LonSW || flight.Lon > bbox.LonNE {
+			continue
+		}
+		k := grid.locate(flight.Lat, flight.Lon)
+		cell, exists := grid.cells[k]
+		if !exists {
+			cell = &Cell{Polygon: grid.polygon(k), flights: map[string]bool{}, lowFlights: map[string]bool{}}
+			grid.cells[k] = cell
+		}
+
+		id := flight.FlightID
+		if id == "" {
+			id = flight.ICAO24BITADDRESS
+		}
+		cell.Positions++
+		cell.altitudes = append(cell.altitudes, flight.Altitude)
+		cell.flights[id] = true
+		if flight.Altitude < options.LowAltitudeFeet {
+			cell.LowPositions++
+			cell.lowFlights[id] = true
+		}
+	}
+
+	keys := make([]key, 0, len(grid.cells))
+	for k, cell := range grid.cells {
+		cell.Flights = len(cell.flights)
+		cell.LowFlights = len(cell.lowFlights)
+		cell.MedianAltitudeFeet = median(cell.altitudes)
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].b < keys[j].b || (keys[i].b == keys[j].b && keys[i].a < keys[j].a)
+	})
+	for _, k := range keys {
+		grid.Cells = append(grid.Cells, grid.cells[k])
+	}
+	return grid, nil
+}
+
+// Lookup - the cell of a point, nil when no position is aggregated there
+func (g *Grid) Lookup(lat, lon float64) *Cell {
+	return g.cells[g.locate(lat, lon)]
+}
+
+// locate - the key of the cell containing the point
+func (g *Grid) locate(lat, lon float64) key {
+	x, y := g.project(lat, lon)
+	if g.Options.Shape == ShapeSquare {
+		return key{int(math.Floor(x / g.Options.Cell)), int(math.Floor(y / g.Options.Cell))}
+	}
+
+	//pointy-top hexagons in axial coordinates, see https://www.redblobgames.com/grids/hexagons/
+	size := g.Options.Cell / math.Sqrt(3)
+	q := (math.Sqrt(3)/3*x - y/3) / size
+	r := 2.0 / 3 * y / size
+	return hexRound(q, r)
+}
+
+// polygon of the cell in Lon/Lat
+func (g *Grid) polygon(k key) tools.Polygon {
+	var ring [][2]float64
+	if g.Options.Shape == ShapeSquare {
+		x, y := float64(k.a)*g.Options.Cell, float64(k.b)*g.Options.Cell
+		for _, corner := range [][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}} {
+			ring = append(ring, g.unproject(x+corner[0]*g.Options.Cell, y+corner[1]*g.Options.Cell))
+		}
+	} else {
+		size := g.Options.Cell / math.Sqrt(3)
+		cx := size * math.Sqrt(3) * (float64(k.a) + float64(k.b)/2)
+		cy := size * 3 / 2 * float64(k.b)
+		for corner := 0; corner <= 6; corner++ {
+			angle := math.Pi / 180 * float64(60*(corner%6)+30)
+			ring = append(ring, g.unproject(cx+size*math.Cos(angle), cy+size*math.Sin(angle)))
+		}
+	}
+	return tools.Polygon{Rings: [][][2]float64{ring}}
+}
+
+// project - meters from the SW corner of the bbox
+func (g *Grid) project(lat, lon float64) (float64, float64) {
+	return (lon - g.Bbox.LonSW) * g.meterX, (lat - g.Bbox.LatSW) * meterByDegree
+}
+
+// unproject - Lon/Lat of the point at meters from the SW corner of the bbox
+func (g *Grid) unproject(x, y float64) [2]float64 {
+	return [2]float64{g.Bbox.LonSW + x/g.meterX, g.Bbox.LatSW + y/meterByDegree}
+}
+
+// hexRound - the hexagon containing the fractional axial coordinates
+func hexRound(q, r float64) key {
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+	if dq > dr && dq > ds {
+		rq = -rr - rs
+	} else if dr > ds {
+		rr = -rq - rs
+	}
+	return key{int(rq), int(rr)}
+}
+
+func median(values []int64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,144 @@
+package density
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+// about 2.2km x 2.2km
+var bbox = tools.Bbox{LatSW: 43.60, LonSW: 1.40, LatNE: 43.62, LonNE: 1.4276}
+
+var positions = []app.FlightData{
+	//two flights over the SW cell
+	{FlightID: "a", Lat: 43.601, Lon: 1.401, Altitude: 500},
+	{FlightID: "a", Lat: 43.602, Lon: 1.402, Altitude: 700},
+	{FlightID: "b", Lat: 43.603, Lon: 1.401, Altitude: 3000},
+	//one flight over the NE cell
+	{FlightID: "c", Lat: 43.619, Lon: 1.427, Altitude: 12000},
+	//outside of the bbox
+	{FlightID: "d", Lat: 43.7, Lon: 1.5, Altitude: 500},
+}
+
+func TestAggregateSquare(t *testing.T) {
+	grid, err := Aggregate(positions, bbox, Options{Cell: 500, Shape: ShapeSquare, LowAltitudeFeet: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grid.Cells) != 2 {
+		t.Fatalf("expected 2 cells, got %d", len(grid.Cells))
+	}
+
+	sw := grid.Cells[0]
+	if sw.Positions != 3 || sw.Flights != 2 || sw.LowPositions != 2 || sw.LowFlights != 1 || sw.MedianAltitudeFeet != 700 {
+		t.Errorf("SW cell: expected 3 positions, 2 flights, 2 low positions, 1 low flight and median 700ft, got %+v", sw)
+	}
+	if corner := sw.Polygon.Rings[0][0]; corner != [2]float64{bbox.LonSW, bbox.LatSW} {
+		t.Errorf("SW cell: expected the bbox SW corner, got %v", corner)
+	}
+	if !sw.Polygon.Contains(43.601, 1.401) {
+		t.Error("SW cell polygon must contain its positions")
+	}
+
+	ne := grid.Cells[1]
+	if ne.Positions != 1 || ne.LowPositions != 0 || ne.MedianAltitudeFeet != 12000 {
+		t.Errorf("NE cell: expected 1 high position, got %+v", ne)
+	}
+}
+
+func TestAggregateHexagon(t *testing.T) {
+	grid, err := Aggregate(positions, bbox, Options{Cell: 500, Shape: ShapeHexagon, LowAltitudeFeet: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbPosition := 0
+	for _, cell := range grid.Cells {
+		nbPosition += cell.Positions
+		if len(cell.Polygon.Rings[0]) != 7 {
+			t.Errorf("expected a closed hexagon, got %v", cell.Polygon.Rings[0])
+		}
+	}
+	if nbPosition != 4 {
+		t.Errorf("expected the 4 positions of the bbox, got %d", nbPosition)
+	}
+
+	//each position is inside the hexagon of its cell
+	for _, position := range positions[:4] {
+		cell := grid.Lookup(position.Lat, position.Lon)
+		if cell == nil || !cell.Polygon.Contains(position.Lat, position.Lon) {
+			t.Errorf("position %v outside of its hexagon", position)
+		}
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	if _, err := Aggregate(positions, bbox, Options{Cell: 0.5}); err == nil {
+		t.Error("expected an error for a grid of too many cells")
+	}
+	if _, err := Aggregate(positions, bbox, Options{Cell: 500, Shape: "triangle"}); err == nil {
+		t.Error("expected an error for an unknown shape")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	grid, err := Aggregate(positions, bbox, Options{Cell: 500, LowAltitudeFeet: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var geojson bytes.Buffer
+	if err := WriteGeoJSON(&geojson, grid); err != nil {
+		t.Fatal(err)
+	}
+	var collection featureCollection
+	if err := json.Unmarshal(geojson.Bytes(), &collection); err != nil {
+		t.Fatal(err)
+	}
+	if len(collection.Features) != 2 || collection.Features[0].Properties["flights"] != 2.0 {
+		t.Errorf("expected 2 features, the first with 2 flights, got %s", geojson.String())
+	}
+
+	var raster bytes.Buffer
+	if err := WritePNG(&raster, grid, MetricFlights, 200); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&raster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 200 || img.Bounds().Dy() < 195 || img.Bounds().Dy() > 205 {
+		t.Errorf("expected a square raster of 200 pixels, got %v", img.Bounds())
+	}
+	//SW cell in the bottom left corner, highest value in dark red, empty cells transparent
+	if _, _, _, alpha := img.At(100, 100).RGBA(); alpha != 0 {
+		t.Error("expected a transparent pixel without position")
+	}
+	if pixel := color.NRGBAModel.Convert(img.At(2, img.Bounds().Dy()-2)).(color.NRGBA); pixel.R != 189 || pixel.G != 0 {
+		t.Errorf("expected the highest cell in dark red, got %v", pixel)
+	}
+
+	if err := WritePNG(&raster, grid, "noise", 200); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+
+	//about 2.4km x 222km, the height is capped
+	tall, err := Aggregate(positions, tools.Bbox{LatSW: 43, LonSW: 1.40, LatNE: 45, LonNE: 1.43}, Options{Cell: 5000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raster.Reset()
+	if err := WritePNG(&raster, tall, MetricFlights, maxWidth); err != nil {
+		t.Fatal(err)
+	}
+	if img, err = png.Decode(&raster); err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dy() != maxWidth || img.Bounds().Dx() > 50 {
+		t.Errorf("expected a raster %d pixels high, got %v", maxWidth, img.Bounds())
+	}
+}
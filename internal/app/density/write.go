@@ -0,0 +1,156 @@
+package density
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+const (
+	FormatGeoJSON = "geojson"
+	FormatPNG     = "png"
+
+	MetricFlights      = "flights"
+	MetricPositions    = "positions"
+	MetricLowFlights   = "lowFlights"
+	MetricLowPositions = "lowPositions"
+
+	// maximum width and height of a PNG raster in pixel
+	maxWidth = 4096
+)
+
+// ContentType - the content type of a format, error if the format is unknown
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatGeoJSON:
+		return "application/geo+json", nil
+	case FormatPNG:
+		return "image/png", nil
+	}
+	return "", fmt.Errorf("format %s unknown - need %s or %s", format, FormatGeoJSON, FormatPNG)
+}
+
+// Value - the metric of the cell, error if the metric is unknown
+func (c *Cell) Value(metric string) (int, error) {
+	switch metric {
+	case MetricFlights:
+		return c.Flights, nil
+	case MetricPositions:
+		return c.Positions, nil
+	case MetricLowFlights:
+		return c.LowFlights, nil
+	case MetricLowPositions:
+		return c.LowPositions, nil
+	}
+	return 0, fmt.Errorf("metric %s unknown - need %s, %s, %s or %s", metric, MetricFlights, MetricPositions, MetricLowFlights, MetricLowPositions)
+}
+
+type featureCollection struct {
+	Type            string    `json:"type"`
+	Bbox            []float64 `json:"bbox"`
+	Shape           string    `json:"shape"`
+	Cell            float64   `json:"cell"`
+	LowAltitudeFeet int64     `json:"lowAltitudeFeet"`
+	Features        []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string                 `json:"type"`
+	Geometry   geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// WriteGeoJSON - a Polygon feature by not empty cell with its counts and median altitude
+func WriteGeoJSON(w io.Writer, grid *Grid) error {
+	collection := featureCollection{
+		Type:            "FeatureCollection",
+		Bbox:            []float64{grid.Bbox.LonSW, grid.Bbox.LatSW, grid.Bbox.LonNE, grid.Bbox.LatNE},
+		Shape:           grid.Options.Shape,
+		Cell:            grid.Options.Cell,
+		LowAltitudeFeet: grid.Options.LowAltitudeFeet,
+		Features:        []feature{},
+	}
+	for _, cell := range grid.Cells {
+		collection.Features = append(collection.Features, feature{
+			Type:     "Feature",
+			Geometry: geometry{Type: "Polygon", Coordinates: cell.Polygon.Rings},
+			Properties: map[string]interface{}{
+				MetricPositions:      cell.Positions,
+				MetricFlights:        cell.Flights,
+				"medianAltitudeFeet": cell.MedianAltitudeFeet,
+				MetricLowPositions:   cell.LowPositions,
+				MetricLowFlights:     cell.LowFlights,
+			},
+		})
+	}
+	return json.NewEncoder(w).Encode(collection)
+}
+
+// CheckWidth - error if the width of a PNG raster is out of range
+func CheckWidth(width int) error {
+	if width <= 0 || width > maxWidth {
+		return fmt.Errorf("width must be between 1 and %d pixels", maxWidth)
+	}
+	return nil
+}
+
+// WritePNG - a raster of the bbox width pixels wide, the cells colored from yellow to red by the metric
+// (logarithmic scale up to the highest cell), transparent without position
+// a bbox taller than wide is narrowed so the height stays below the maximum width
+func WritePNG(w io.Writer, grid *Grid, metric string, width int) error {
+	if err := CheckWidth(width); err != nil {
+		return err
+	}
+	highest := 0
+	for _, cell := range grid.Cells {
+		value, err := cell.Value(metric)
+		if err != nil {
+			return err
+		}
+		if value > highest {
+			highest = value
+		}
+	}
+
+	height := int(math.Max(1, math.Round(float64(width)*grid.height/grid.width)))
+	if height > maxWidth {
+		width = int(math.Max(1, math.Round(float64(width)*maxWidth/float64(height))))
+		height = maxWidth
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for py := 0; py < height; py++ {
+		lat := grid.Bbox.LatNE - (float64(py)+0.5)/float64(height)*(grid.Bbox.LatNE-grid.Bbox.LatSW)
+		for px := 0; px < width; px++ {
+			lon := grid.Bbox.LonSW + (float64(px)+0.5)/float64(width)*(grid.Bbox.LonNE-grid.Bbox.LonSW)
+			cell := grid.Lookup(lat, lon)
+			if cell == nil {
+				continue
+			}
+			value, _ := cell.Value(metric)
+			if value > 0 {
+				img.SetNRGBA(px, py, ramp(math.Log1p(float64(value))/math.Log1p(float64(highest))))
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// ramp - color from yellow (0) to orange then dark red (1)
+func ramp(ratio float64) color.NRGBA {
+	stops := []color.NRGBA{{255, 255, 178, 180}, {253, 141, 60, 200}, {189, 0, 38, 220}}
+	position := math.Min(1, math.Max(0, ratio)) * float64(len(stops)-1)
+	idx := int(math.Min(position, float64(len(stops)-2)))
+	t := position - float64(idx)
+	from, to := stops[idx], stops[idx+1]
+	mix := func(a, b uint8) uint8 { return uint8(math.Round(float64(a) + (float64(b)-float64(a))*t)) }
+	return color.NRGBA{mix(from.R, to.R), mix(from.G, to.G), mix(from.B, to.B), mix(from.A, to.A)}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+)
+
+// export formats
+const (
+	FormatGeoJSON = "geojson"
+	FormatKML     = "kml"
+	FormatGPX     = "gpx"
+)
+
+var contentTypes = map[string]string{
+	FormatGeoJSON: "application/geo+json",
+	FormatKML:     "application/vnd.google-earth.kml+xml",
+	FormatGPX:     "application/gpx+xml",
+}
+
+// ContentType - HTTP content type of the format
+func ContentType(format string) (string, error) {
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return "", errors.New("Export format unknown - need geojson, kml or gpx")
+	}
+	return contentType, nil
+}
+
+// Write - export the tracks in the format, one feature per flight
+func Write(w io.Writer, format string, tracks []app.Track) error {
+	switch format {
+	case FormatGeoJSON:
+		return writeGeoJSON(w, tracks)
+	case FormatKML:
+		return writeKML(w, tracks)
+	case FormatGPX:
+		return writeGPX(w, tracks)
+	}
+	return errors.New("Export format unknown - need geojson, kml or gpx")
+}
+
+// name - callsign when known, flight identifier otherwise
+func name(track app.Track) string {
+	if callsign, _, _ := tracking.Identity(track); callsign != "" {
+		return callsign
+	}
+	if track.FlightID != "" {
+		return track.FlightID
+	}
+	return track.ICAO24BITADDRESS
+}
+
+// writeXML - indented XML document
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func altitudeMeter(flight app.FlightData) float64 {
+	return float64(flight.Altitude) * app.FEETTOMETER
+}
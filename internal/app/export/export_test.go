@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// tracks - a helicopter with 3 positions, the callsign known from the second one, and a single position flight
+func tracks() []app.Track {
+	start := time.Date(2021, 7, 22, 8, 0, 0, 0, time.UTC)
+	helicopter := app.Track{
+		FlightID:         "2a3b4c5d",
+		ICAO24BITADDRESS: "39856C",
+		Start:            start,
+		End:              start.Add(20 * time.Second),
+		MinAltitude:      900,
+		MaxGroundSpeed:   110,
+	}
+	for idx, altitude := range []int64{1000, 900, 950} {
+		position := app.FlightData{
+			FlightID:         helicopter.FlightID,
+			ICAO24BITADDRESS: helicopter.ICAO24BITADDRESS,
+			Lat:              []float64{43.6, 43.601, 43.602}[idx],
+			Lon:              []float64{1.44, 1.442, 1.444}[idx],
+			Altitude:         altitude,
+			GroundSpeed:      100 + int64(idx)*5,
+			AircraftType:     "EC35",
+			TimeStamp:        float64(start.Unix() + int64(idx)*10),
+		}
+		if idx > 0 {
+			position.Hint = "SAMU31"
+			position.Immatriculation1 = "F-HJAF"
+		}
+		helicopter.Positions = append(helicopter.Positions, position)
+	}
+
+	single := app.Track{
+		ICAO24BITADDRESS: "4CA7B5",
+		Start:            start.Add(time.Minute),
+		End:              start.Add(time.Minute),
+		MinAltitude:      35000,
+		MaxGroundSpeed:   450,
+		Positions:        []app.FlightData{{ICAO24BITADDRESS: "4CA7B5", Lat: 43.65, Lon: 1.5, Altitude: 35000, GroundSpeed: 450, TimeStamp: float64(start.Add(time.Minute).Unix())}},
+	}
+	return []app.Track{helicopter, single}
+}
+
+func TestWriteGolden(t *testing.T) {
+	for _, format := range []string{FormatGeoJSON, FormatKML, FormatGPX} {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, tracks()); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		golden := filepath.Join("testdata", "tracks."+format)
+		if *update {
+			if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("%s: output differs from %s (go test -update to rewrite it)\n%s", format, golden, buf.String())
+		}
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "shp", tracks()); err == nil || buf.Len() > 0 {
+		t.Errorf("expected an error without output, got %v and %q", err, buf.String())
+	}
+	if _, err := ContentType("shp"); err == nil {
+		t.Error("expected an error for the content type of an unknown format")
+	}
+	if contentType, err := ContentType(FormatGPX); err != nil || contentType != "application/gpx+xml" {
+		t.Errorf("unexpected GPX content type %s (%v)", contentType, err)
+	}
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+)
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string                 `json:"type"`
+	Geometry   geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// writeGeoJSON - a LineString feature per flight (Point for a single position), coordinates are Lon, Lat, altitude in meter
+func writeGeoJSON(w io.Writer, tracks []app.Track) error {
+	collection := featureCollection{Type: "FeatureCollection", Features: []feature{}}
+	for _, track := range tracks {
+		coordinates := make([][3]float64, 0, len(track.Positions))
+		for _, position := range track.Positions {
+			coordinates = append(coordinates, [3]float64{position.Lon, position.Lat, altitudeMeter(position)})
+		}
+
+		geom := geometry{Type: "LineString", Coordinates: coordinates}
+		if len(coordinates) == 1 {
+			geom = geometry{Type: "Point", Coordinates: coordinates[0]}
+		}
+
+		callsign, aircraftType, registration := tracking.Identity(track)
+		collection.Features = append(collection.Features, feature{
+			Type:     "Feature",
+			Geometry: geom,
+			Properties: map[string]interface{}{
+				"flightID":         track.FlightID,
+				"ICAO24BITADDRESS": track.ICAO24BITADDRESS,
+				"callsign":         callsign,
+				"aircraftType":     aircraftType,
+				"registration":     registration,
+				"start":            track.Start.Format(time.RFC3339),
+				"end":              track.End.Format(time.RFC3339),
+				"minAltitude":      track.MinAltitude,
+				"maxGroundSpeed":   track.MaxGroundSpeed,
+				"nbPosition":       len(track.Positions),
+			},
+		})
+	}
+
+	return json.NewEncoder(w).Encode(collection)
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+type gpx struct {
+	XMLName xml.Name   `xml:"gpx"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	Version string     `xml:"version,attr"`
+	Creator string     `xml:"creator,attr"`
+	Tracks  []gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name     string     `xml:"name"`
+	Desc     string     `xml:"desc"`
+	Segments []gpxPoint `xml:"trkseg>trkpt"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Ele  float64 `xml:"ele"`
+	Time string  `xml:"time"`
+}
+
+// writeGPX - a track per flight, elevation in meter
+func writeGPX(w io.Writer, tracks []app.Track) error {
+	doc := gpx{
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Version: "1.1",
+		Creator: "flighttracker",
+	}
+
+	for _, track := range tracks {
+		trk := gpxTrack{Name: name(track), Desc: track.ICAO24BITADDRESS}
+		for _, position := range track.Positions {
+			trk.Segments = append(trk.Segments, gpxPoint{
+				Lat:  position.Lat,
+				Lon:  position.Lon,
+				Ele:  altitudeMeter(position),
+				Time: time.Unix(int64(position.TimeStamp), 0).UTC().Format(time.RFC3339),
+			})
+		}
+		doc.Tracks = append(doc.Tracks, trk)
+	}
+
+	return writeXML(w, doc)
+}
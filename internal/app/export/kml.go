@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+)
+
+type kml struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string       `xml:"name"`
+	Description string       `xml:"description"`
+	TimeSpan    kmlTimeSpan  `xml:"TimeSpan"`
+	LineString  *kmlGeometry `xml:"LineString,omitempty"`
+	Point       *kmlGeometry `xml:"Point,omitempty"`
+}
+
+type kmlTimeSpan struct {
+	Begin string `xml:"begin"`
+	End   string `xml:"end"`
+}
+
+type kmlGeometry struct {
+	Extrude      int    `xml:"extrude"`
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+// writeKML - a placemark per flight, extruded to the ground with absolute altitude for Google Earth
+func writeKML(w io.Writer, tracks []app.Track) error {
+	doc := kml{
+		Xmlns:    "http://www.opengis.net/kml/2.2",
+		Document: kmlDocument{Name: "flighttracker"},
+	}
+
+	for _, track := range tracks {
+		coordinates := make([]string, 0, len(track.Positions))
+		for _, position := range track.Positions {
+			coordinates = append(coordinates, fmt.Sprintf("%f,%f,%.0f", position.Lon, position.Lat, altitudeMeter(position)))
+		}
+		geom := &kmlGeometry{Extrude: 1, AltitudeMode: "absolute", Coordinates: strings.Join(coordinates, " ")}
+
+		_, aircraftType, registration := tracking.Identity(track)
+		placemark := kmlPlacemark{
+			Name: name(track),
+			Description: fmt.Sprintf("ICAO %s - type %s - registration %s - min altitude %d ft - max speed %d kts",
+				track.ICAO24BITADDRESS, aircraftType, registration, track.MinAltitude, track.MaxGroundSpeed),
+			TimeSpan: kmlTimeSpan{Begin: track.Start.Format(time.RFC3339), End: track.End.Format(time.RFC3339)},
+		}
+		if len(coordinates) == 1 {
+			placemark.Point = geom
+		} else {
+			placemark.LineString = geom
+		}
+		doc.Document.Placemarks = append(doc.Document.Placemarks, placemark)
+	}
+
+	return writeXML(w, doc)
+}
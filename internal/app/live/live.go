@@ -0,0 +1,130 @@
+package live
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+//Tick - the flights of a tick sent to a live client
+type Tick struct {
+	Time     time.Time        `json:"time"`
+	NbFlight int              `json:"nbFlight"`
+	Data     []app.FlightData `json:"data"`
+}
+
+//Filter - the flights sent to a live client, all the flights when empty
+type Filter struct {
+	Bbox             *tools.Bbox
+	Zone             string //monitoring zone name
+	AltThresholdFeet int    //altitude under or equal, no limit if 0
+}
+
+// Apply returns the flights matching the filter
+func (f Filter) Apply(data []app.FlightData) []app.FlightData {
+	result := make([]app.FlightData, 0, len(data))
+	for _, flight := range data {
+		if f.Bbox != nil && (flight.Lat < f.Bbox.LatSW || flight.Lat > f.Bbox.LatNE || flight.Lon < f.Bbox.LonSW || flight.Lon > f.Bbox.LonNE) {
+			continue
+		}
+		if f.Zone != "" && !inZone(flight, f.Zone) {
+			continue
+		}
+		if f.AltThresholdFeet > 0 && flight.Altitude > int64(f.AltThresholdFeet) {
+			continue
+		}
+		result = append(result, flight)
+	}
+	return result
+}
+
+func inZone(flight app.FlightData, zone string) bool {
+	for _, name := range flight.Zones {
+		if name == zone {
+			return true
+		}
+	}
+	return false
+}
+
+//Client - a live client subscription
+type Client struct {
+	filter  Filter
+	ticks   chan Tick
+	dropped int64
+}
+
+// Ticks - the ticks sent to the client
+func (c *Client) Ticks() <-chan Tick {
+	return c.ticks
+}
+
+// Dropped - number of ticks dropped because the client was too slow
+func (c *Client) Dropped() int64 {
+	return atomic.LoadInt64(&c.dropped)
+}
+
+//Hub - a Sinker broadcasting each tick of the worker to the live clients, a slow client loses ticks and doesn't block the worker
+type Hub struct {
+	Log     *logrus.Logger
+	buffer  int
+	mu      sync.RWMutex
+	clients map[*Client]bool
+}
+
+func New(log *logrus.Logger, buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = 1
+	}
+	return &Hub{Log: log, buffer: buffer, clients: map[*Client]bool{}}
+}
+
+// Subscribe a client receiving the ticks matching the filter
+func (h *Hub) Subscribe(filter Filter) *Client {
+	client := &Client{filter: filter, ticks: make(chan Tick, h.buffer)}
+	h.mu.Lock()
+	h.clients[client] = true
+	h.mu.Unlock()
+	return client
+}
+
+// Unsubscribe the client, its Ticks channel is closed
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	if h.clients[client] {
+		delete(h.clients, client)
+		close(client.ticks)
+	}
+	h.mu.Unlock()
+}
+
+// Clients - number of connected clients
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Sink sends the tick to each client with its filtered flights
+func (h *Hub) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		flights := client.filter.Apply(data)
+		select {
+		case client.ticks <- Tick{Time: t, NbFlight: len(flights), Data: flights}:
+		default:
+			dropped := atomic.AddInt64(&client.dropped, 1)
+			h.Log.WithContext(ctx).WithFields(logrus.Fields{
+				"dropped": dropped,
+			}).Warning("Live client too slow, tick dropped")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package live
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+var flights = []app.FlightData{
+	{FlightID: "2b1e4c51", ICAO24BITADDRESS: "39c4b1", Lat: 43.6, Lon: 1.44, Altitude: 800, Zones: []string{"village"}},
+	{FlightID: "2b1e4c52", ICAO24BITADDRESS: "3944ef", Lat: 43.7, Lon: 1.38, Altitude: 12000},
+	{FlightID: "2b1e4c53", ICAO24BITADDRESS: "3c6444", Lat: 48.8, Lon: 2.35, Altitude: 1500},
+}
+
+func TestFilter(t *testing.T) {
+	bbox := tools.Bbox{LatSW: 43.52, LonSW: 1.32, LatNE: 43.70, LonNE: 1.69}
+	cases := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"none", Filter{}, []string{"39c4b1", "3944ef", "3c6444"}},
+		{"bbox", Filter{Bbox: &bbox}, []string{"39c4b1", "3944ef"}},
+		{"zone", Filter{Zone: "village"}, []string{"39c4b1"}},
+		{"altitude", Filter{AltThresholdFeet: 1500}, []string{"39c4b1", "3c6444"}},
+		{"bbox and altitude", Filter{Bbox: &bbox, AltThresholdFeet: 1500}, []string{"39c4b1"}},
+	}
+
+	for _, c := range cases {
+		result := c.filter.Apply(flights)
+		if len(result) != len(c.expected) {
+			t.Errorf("%s: expected %d flights, got %d", c.name, len(c.expected), len(result))
+			continue
+		}
+		for idx, flight := range result {
+			if flight.ICAO24BITADDRESS != c.expected[idx] {
+				t.Errorf("%s: expected flight %s, got %s", c.name, c.expected[idx], flight.ICAO24BITADDRESS)
+			}
+		}
+	}
+}
+
+func TestHubSlowClient(t *testing.T) {
+	hub := New(log, 2)
+	fast := hub.Subscribe(Filter{AltThresholdFeet: 1000})
+	slow := hub.Subscribe(Filter{})
+
+	ctx := context.Background()
+	now := time.Now()
+	for tick := 0; tick < 3; tick++ {
+		if err := hub.Sink(ctx, now.Add(time.Duration(tick)*time.Second), flights); err != nil {
+			t.Fatal(err)
+		}
+		received := <-fast.Ticks()
+		if received.NbFlight != 1 || received.Data[0].ICAO24BITADDRESS != "39c4b1" {
+			t.Errorf("expected the flight under 1000 feet, got %+v", received.Data)
+		}
+	}
+
+	if slow.Dropped() != 1 || fast.Dropped() != 0 {
+		t.Errorf("expected 1 tick dropped for the slow client only, got %d and %d", slow.Dropped(), fast.Dropped())
+	}
+
+	hub.Unsubscribe(slow)
+	hub.Unsubscribe(fast)
+	if hub.Clients() != 0 {
+		t.Errorf("expected no client, got %d", hub.Clients())
+	}
+	for range slow.Ticks() {
+	}
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.FatalLevel
+	log.Out = os.Stdout
+}
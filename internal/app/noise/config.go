@@ -0,0 +1,12 @@
+package noise
+
+// Configuration settings for the noise exposure estimation
+type Configuration struct {
+	Radius            int                `toml:"radius" default:"5000" comment:"distance in meter around the location of the passes taken into account"`
+	ReferenceDistance float64            `toml:"referenceDistance" default:"300" comment:"distance in meter of the reference levels"`
+	DefaultLevel      float64            `toml:"defaultLevel" default:"80" comment:"maximum level (Lmax) in dB(A) at the reference distance of the aircraft types missing in levels"`
+	Levels            map[string]float64 `toml:"levels" comment:"maximum level (Lmax) in dB(A) at the reference distance by aircraft type (ICAO code)"`
+	Absorption        float64            `toml:"absorption" default:"5" comment:"atmospheric absorption in dB by km"`
+	Threshold         float64            `toml:"threshold" default:"65" comment:"maximum level in dB(A) from which a pass is counted by the NAbove indicator"`
+	Timezone          string             `toml:"timezone" default:"Local" comment:"time zone of the day (7h-19h), evening (19h-23h) and night (23h-7h) periods (i.e. Europe/Paris)"`
+}
@@ -0,0 +1,246 @@
+package noise
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+)
+
+// periods of the day, Lden weights the evening by 5 dB and the night by 10 dB
+const (
+	PeriodDay     = "day"
+	PeriodEvening = "evening"
+	PeriodNight   = "night"
+)
+
+const (
+	// minimum speed in m/s of the pass duration, a hovering helicopter isn't infinitely loud
+	minSpeed = 10
+	// meters the distance to the location has to increase then decrease between two passes of a track,
+	// smaller variations are position noise
+	passSeparation = 300
+)
+
+//Location - the exposed location, i.e. a house
+type Location struct {
+	Lat       float64 `json:"lat"`
+	Lon       float64 `json:"lon"`
+	Elevation float64 `json:"elevation"` //ground altitude in meter
+}
+
+//Event - a pass of an aircraft near the location
+type Event struct {
+	Time             time.Time `json:"time"` //closest point of approach
+	Period           string    `json:"period"`
+	FlightID         string    `json:"flightID"`
+	ICAO24BITADDRESS string    `json:"ICAO24BITADDRESS"`
+	Callsign         string    `json:"callsign"`
+	AircraftType     string    `json:"aircraftType"`
+	Registration     string    `json:"registration"`
+	SlantDistance    float64   `json:"slantDistance"`  //meter
+	GroundDistance   float64   `json:"groundDistance"` //meter
+	Height           float64   `json:"height"`         //meter above the location
+	GroundSpeedKmh   float64   `json:"groundSpeedKmh"`
+	Lmax             float64   `json:"lmax"` //dB(A)
+	SEL              float64   `json:"sel"`  //sound exposure level, dB(A)
+}
+
+//Indicators - the cumulative exposure of the time window, 0 dB without event in the period
+type Indicators struct {
+	Events    int     `json:"events"`
+	Lden      float64 `json:"lden"`     //day-evening-night level
+	Lday      float64 `json:"lday"`     //equivalent level 7h-19h
+	Levening  float64 `json:"levening"` //equivalent level 19h-23h
+	Lnight    float64 `json:"lnight"`   //equivalent level 23h-7h
+	Leq       float64 `json:"leq"`      //equivalent level of the whole window
+	NAbove    int     `json:"nAbove"`   //passes with a Lmax above the threshold
+	Threshold float64 `json:"threshold"`
+}
+
+//Exposure - the noise exposure of a location during a time window
+type Exposure struct {
+	Location   Location   `json:"location"`
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to"`
+	Indicators Indicators `json:"indicators"`
+	Events     []Event    `json:"events"`
+}
+
+//Estimator - estimate the noise of the passes from the reference levels of the aircraft types
+type Estimator struct {
+	conf     Configuration
+	levels   map[string]float64
+	location *time.Location
+}
+
+func New(conf Configuration) (*Estimator, error) {
+	if conf.ReferenceDistance <= 0 {
+		return nil, errors.New("reference distance must be positive")
+	}
+	location, err := time.LoadLocation(conf.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	estimator := &Estimator{conf: conf, levels: map[string]float64{}, location: location}
+	for aircraftType, level := range conf.Levels {
+		estimator.levels[strings.ToUpper(aircraftType)] = level
+	}
+	return estimator, nil
+}
+
+// Estimate the exposure of the location to the tracks during the time window: an event by pass closer than the radius,
+// a track circling around the location makes a pass by loop
+func (e *Estimator) Estimate(tracks []app.Track, location Location, from, to time.Time) Exposure {
+	exposure := Exposure{Location: location, From: from, To: to, Events: []Event{}}
+	energy := map[string]float64{}
+	for _, track := range tracks {
+		for _, pass := range passes(track, location) {
+			approach := tracking.ClosestApproach(pass, location.Lat, location.Lon, location.Elevation)
+			if approach.SlantDistance > float64(e.conf.Radius) || approach.Time.Before(from) || approach.Time.After(to) {
+				continue
+			}
+
+			event := e.event(pass, approach)
+			exposure.Events = append(exposure.Events, event)
+			energy[event.Period] += math.Pow(10, event.SEL/10)
+			if event.Lmax >= e.conf.Threshold {
+				exposure.Indicators.NAbove++
+			}
+		}
+	}
+	sort.Slice(exposure.Events, func(i, j int) bool { return exposure.Events[i].Time.Before(exposure.Events[j].Time) })
+
+	durations := e.durations(from, to)
+	total := durations[PeriodDay] + durations[PeriodEvening] + durations[PeriodNight]
+	exposure.Indicators.Events = len(exposure.Events)
+	exposure.Indicators.Threshold = e.conf.Threshold
+	exposure.Indicators.Lday = level(energy[PeriodDay], durations[PeriodDay])
+	exposure.Indicators.Levening = level(energy[PeriodEvening], durations[PeriodEvening])
+	exposure.Indicators.Lnight = level(energy[PeriodNight], durations[PeriodNight])
+	exposure.Indicators.Leq = level(energy[PeriodDay]+energy[PeriodEvening]+energy[PeriodNight], total)
+	exposure.Indicators.Lden = level(energy[PeriodDay]+energy[PeriodEvening]*math.Pow(10, 0.5)+energy[PeriodNight]*10, total)
+	return exposure
+}
+
+// passes - the track split at the maxima of the distance to the location between its local minima, each pass holds
+// a single closest approach. Two successive passes share the position of the maximum
+func passes(track app.Track, location Location) []app.Track {
+	if len(track.Positions) == 0 {
+		return nil
+	}
+	distances := make([]float64, 0, len(track.Positions))
+	for _, position := range track.Positions {
+		x, y := tools.LocalXY(location.Lat, location.Lon, position.Lat, position.Lon)
+		distances = append(distances, math.Sqrt(x*x+y*y+math.Pow(float64(position.Altitude)*app.FEETTOMETER-location.Elevation, 2)))
+	}
+
+	var result []app.Track
+	start, low, high, receding := 0, 0, 0, false
+	for idx := 1; idx < len(distances); idx++ {
+		if !receding {
+			if distances[idx] < distances[low] {
+				low = idx
+			}
+			if distances[idx]-distances[low] >= passSeparation {
+				receding, high = true, idx
+			}
+			continue
+		}
+		if distances[idx] > distances[high] {
+			high = idx
+		}
+		if distances[high]-distances[idx] >= passSeparation {
+			//approaching again: a new pass starts at the maximum
+			result = append(result, subTrack(track, start, high+1))
+			start, low, receding = high, idx, false
+		}
+	}
+	return append(result, subTrack(track, start, len(track.Positions)))
+}
+
+// subTrack - the positions [from, to[ of the track
+func subTrack(track app.Track, from, to int) app.Track {
+	sub := track
+	sub.Positions = track.Positions[from:to]
+	sub.Start = time.Unix(int64(sub.Positions[0].TimeStamp), 0)
+	sub.End = time.Unix(int64(sub.Positions[len(sub.Positions)-1].TimeStamp), 0)
+	return sub
+}
+
+// event - the levels of a pass: Lmax decreases with the spherical spreading and the absorption from the reference distance,
+// the SEL adds the duration of the pass at the ground speed
+func (e *Estimator) event(track app.Track, approach tracking.Approach) Event {
+	callsign, aircraftType, registration := tracking.Identity(track)
+
+	reference, known := e.levels[strings.ToUpper(aircraftType)]
+	if !known {
+		reference = e.conf.DefaultLevel
+	}
+	distance := math.Max(approach.SlantDistance, 1)
+	lmax := reference - 20*math.Log10(distance/e.conf.ReferenceDistance) - e.conf.Absorption*(distance-e.conf.ReferenceDistance)/1000
+	speed := math.Max(approach.GroundSpeedKmh/3.6, minSpeed)
+	sel := lmax + 10*math.Log10(math.Pi*distance/(2*speed))
+
+	return Event{
+		Time:             approach.Time,
+		Period:           e.period(approach.Time),
+		FlightID:         track.FlightID,
+		ICAO24BITADDRESS: track.ICAO24BITADDRESS,
+		Callsign:         callsign,
+		AircraftType:     aircraftType,
+		Registration:     registration,
+		SlantDistance:    round(approach.SlantDistance),
+		GroundDistance:   round(approach.GroundDistance),
+		Height:           round(approach.HeightMeter),
+		GroundSpeedKmh:   round(approach.GroundSpeedKmh),
+		Lmax:             round(lmax),
+		SEL:              round(sel),
+	}
+}
+
+// period of the day of the time
+func (e *Estimator) period(t time.Time) string {
+	hour := t.In(e.location).Hour()
+	switch {
+	case hour >= 7 && hour < 19:
+		return PeriodDay
+	case hour >= 19 && hour < 23:
+		return PeriodEvening
+	}
+	return PeriodNight
+}
+
+// durations in second of each period in the time window, hour by hour
+func (e *Estimator) durations(from, to time.Time) map[string]float64 {
+	result := map[string]float64{}
+	for t := from; t.Before(to); {
+		local := t.In(e.location)
+		next := time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, e.location)
+		if next.After(to) {
+			next = to
+		}
+		result[e.period(t)] += next.Sub(t).Seconds()
+		t = next
+	}
+	return result
+}
+
+// level - equivalent continuous level of the sound energy over the duration, 0 without energy
+func level(energy float64, duration float64) float64 {
+	if energy == 0 || duration <= 0 {
+		return 0
+	}
+	return round(10 * math.Log10(energy/duration))
+}
+
+// round to 0.1
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
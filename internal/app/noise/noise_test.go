@@ -0,0 +1,162 @@
+package noise
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+var house = Location{Lat: 43.6, Lon: 1.44, Elevation: 150}
+
+// pass - a straight flight from west to east over the latitude at the altitude (feet), at 100 kts, closest to the house at t
+func pass(id string, aircraftType string, lat float64, altitude int64, t time.Time) app.Track {
+	track := app.Track{FlightID: id, ICAO24BITADDRESS: id}
+	//100 kts is about 0.0006 degree of longitude by second at 43.6N
+	for second := int64(-60); second <= 60; second += 10 {
+		track.Positions = append(track.Positions, app.FlightData{
+			FlightID:         id,
+			ICAO24BITADDRESS: id,
+			Lat:              lat,
+			Lon:              house.Lon + float64(second)*0.000639,
+			Altitude:         altitude,
+			GroundSpeed:      100,
+			AircraftType:     aircraftType,
+			Hint:             "F" + id,
+			TimeStamp:        float64(t.Unix() + second),
+		})
+	}
+	return track
+}
+
+func TestEstimate(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Paris")
+	estimator, err := New(Configuration{
+		Radius:            5000,
+		ReferenceDistance: 300,
+		DefaultLevel:      80,
+		Levels:            map[string]float64{"r44": 85},
+		Absorption:        0,
+		Threshold:         70,
+		Timezone:          "Europe/Paris",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2021, 7, 22, 0, 0, 0, 0, location)
+	to := from.AddDate(0, 0, 1)
+	tracks := []app.Track{
+		//helicopter overhead at 300m (1476ft - 150m) above the house during the day
+		pass("a", "R44", house.Lat, 1476, from.Add(10*time.Hour)),
+		//unknown type at night, 300m above the house
+		pass("b", "", house.Lat, 1476, from.Add(2*time.Hour)),
+		//too far
+		pass("c", "R44", house.Lat+0.1, 1000, from.Add(12*time.Hour)),
+		//outside of the window
+		pass("d", "R44", house.Lat, 1500, to.Add(time.Hour)),
+	}
+
+	exposure := estimator.Estimate(tracks, house, from, to)
+	if exposure.Indicators.Events != 2 || len(exposure.Events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", exposure.Events)
+	}
+
+	night, day := exposure.Events[0], exposure.Events[1]
+	if night.FlightID != "b" || night.Period != PeriodNight || day.FlightID != "a" || day.Period != PeriodDay {
+		t.Errorf("expected the night pass of b then the day pass of a, got %+v", exposure.Events)
+	}
+	if math.Abs(day.SlantDistance-300) > 1 || math.Abs(day.GroundDistance) > 1 {
+		t.Errorf("expected the helicopter 300m overhead, got %+v", day)
+	}
+	if math.Abs(day.Lmax-85) > 0.1 || math.Abs(night.Lmax-80) > 0.1 {
+		t.Errorf("expected Lmax 85 dB(A) for the R44 and 80 dB(A) by default at the reference distance, got %v and %v", day.Lmax, night.Lmax)
+	}
+	//SEL = Lmax + 10 log10(pi * 300 / (2 * 51.44))
+	if expected := 85 + 10*math.Log10(math.Pi*300/(2*100*1.852/3.6)); math.Abs(day.SEL-expected) > 0.1 {
+		t.Errorf("expected SEL %.1f, got %v", expected, day.SEL)
+	}
+	if exposure.Indicators.NAbove != 2 {
+		t.Errorf("expected 2 passes above 70 dB(A), got %d", exposure.Indicators.NAbove)
+	}
+
+	//Lday: the day SEL energy over 12 hours
+	if expected := day.SEL - 10*math.Log10(12*3600); math.Abs(exposure.Indicators.Lday-expected) > 0.15 {
+		t.Errorf("expected Lday %.1f, got %v", expected, exposure.Indicators.Lday)
+	}
+	if exposure.Indicators.Levening != 0 {
+		t.Errorf("expected no evening level, got %v", exposure.Indicators.Levening)
+	}
+	//Lden: the night pass weighted by 10 dB over 24 hours
+	energy := math.Pow(10, day.SEL/10) + 10*math.Pow(10, night.SEL/10)
+	if expected := 10 * math.Log10(energy/(24*3600)); math.Abs(exposure.Indicators.Lden-expected) > 0.15 {
+		t.Errorf("expected Lden %.1f, got %v", expected, exposure.Indicators.Lden)
+	}
+}
+
+// circle - a helicopter circling loops times at the altitude (feet) around a point 1000m east of the house,
+// 600m of radius at 80 kts (a loop in about 92s), a position every 5s from t
+func circle(id string, loops int, altitude int64, t time.Time) app.Track {
+	track := app.Track{FlightID: id, ICAO24BITADDRESS: id}
+	speed := 80 * app.KTSKMH / 3.6
+	period := 2 * math.Pi * 600 / speed
+	for second := 0.0; second <= float64(loops)*period; second += 5 {
+		angle := 2 * math.Pi * second / period
+		x, y := 1000+600*math.Cos(angle), 600*math.Sin(angle)
+		track.Positions = append(track.Positions, app.FlightData{
+			FlightID:         id,
+			ICAO24BITADDRESS: id,
+			Lat:              house.Lat + y/tools.EarthRadius*180/math.Pi,
+			Lon:              house.Lon + x/(tools.EarthRadius*math.Cos(house.Lat*math.Pi/180))*180/math.Pi,
+			Altitude:         altitude,
+			GroundSpeed:      80,
+			AircraftType:     "R44",
+			TimeStamp:        float64(t.Unix()) + second,
+		})
+	}
+	return track
+}
+
+func TestEstimateCircling(t *testing.T) {
+	estimator, err := New(Configuration{Radius: 5000, ReferenceDistance: 300, DefaultLevel: 80, Threshold: 70, Timezone: "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2021, 7, 22, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	exposure := estimator.Estimate([]app.Track{circle("h", 3, 1000, from.Add(10*time.Hour))}, house, from, to)
+	if exposure.Indicators.Events != 3 {
+		t.Fatalf("expected a pass by loop, got %+v", exposure.Events)
+	}
+	for idx, event := range exposure.Events {
+		//closest point of each loop: 400m west of the circle center
+		if math.Abs(event.GroundDistance-400) > 20 || event.FlightID != "h" {
+			t.Errorf("pass %d: expected the helicopter 400m away, got %+v", idx, event)
+		}
+		if idx > 0 {
+			if gap := event.Time.Sub(exposure.Events[idx-1].Time).Seconds(); math.Abs(gap-92) > 5 {
+				t.Errorf("pass %d: expected a loop of about 92s, got %.0fs", idx, gap)
+			}
+		}
+	}
+
+	//a straight flight is a single pass
+	if passes := passes(pass("a", "R44", house.Lat, 1476, from), house); len(passes) != 1 || len(passes[0].Positions) != 13 {
+		t.Errorf("expected the straight flight in a single pass, got %d passes", len(passes))
+	}
+}
+
+func TestDurations(t *testing.T) {
+	estimator, err := New(Configuration{ReferenceDistance: 300, Timezone: "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2021, 7, 22, 6, 30, 0, 0, time.UTC)
+	durations := estimator.durations(from, from.Add(48*time.Hour))
+	if durations[PeriodDay] != 24*3600 || durations[PeriodEvening] != 8*3600 || durations[PeriodNight] != 16*3600 {
+		t.Errorf("expected 24h of day, 8h of evening and 16h of night, got %v", durations)
+	}
+}
@@ -0,0 +1,150 @@
+package adsbx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+//aircraftResponse - aircraft.json body produced by readsb/dump1090/tar1090
+// ADS-B Exchange v2 API names the aircraft list "ac" and gives "now" in milliseconds
+type aircraftResponse struct {
+	Now      float64    `json:"now"`
+	Aircraft []aircraft `json:"aircraft"`
+	Ac       []aircraft `json:"ac"`
+}
+
+type aircraft struct {
+	Hex      string      `json:"hex"`
+	Type     string      `json:"type"`
+	Flight   string      `json:"flight"`
+	AltBaro  interface{} `json:"alt_baro"` //feet or "ground"
+	Gs       float64     `json:"gs"`       //kts
+	Track    float64     `json:"track"`
+	BaroRate float64     `json:"baro_rate"` //feet/min
+	Squawk   string      `json:"squawk"`
+	Lat      *float64    `json:"lat"`
+	Lon      *float64    `json:"lon"`
+	SeenPos  float64     `json:"seen_pos"`
+	T        string      `json:"t"` //aircraft type (ADS-B Exchange / tar1090 database)
+	R        string      `json:"r"` //registration (ADS-B Exchange / tar1090 database)
+}
+
+type ADSBXProvider struct {
+	Log  *logrus.Logger
+	conf Configuration
+}
+
+func New(log *logrus.Logger) app.Provider {
+	//init the logger here
+	return &ADSBXProvider{Log: log}
+}
+
+func (p *ADSBXProvider) Init(ctx context.Context, params interface{}) error {
+	parameters := params.(Configuration)
+	p.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Url": parameters.Url,
+	}).Info("Initialisation aircraft.json provider Parameters")
+
+	p.conf = parameters
+	return nil
+}
+
+func (p *ADSBXProvider) Fetch(ctx context.Context, bbox tools.Bbox) ([]app.FlightData, error) {
+	body, errRaw := p.GetRawData(ctx, bbox)
+	if errRaw != nil {
+		return nil, errRaw
+	}
+
+	return p.UnMarshalByte(ctx, body, bbox)
+}
+
+func (p *ADSBXProvider) GetRawData(ctx context.Context, bbox tools.Bbox) ([]byte, error) {
+	if !strings.HasPrefix(p.conf.Url, "http://") && !strings.HasPrefix(p.conf.Url, "https://") {
+		return ioutil.ReadFile(strings.TrimPrefix(p.conf.Url, "file://"))
+	}
+
+	req, errReq := http.NewRequest(http.MethodGet, p.conf.Url, nil)
+	if errReq != nil {
+		return nil, errReq
+	}
+	resp, errHTTPGet := http.DefaultClient.Do(req.WithContext(ctx))
+	if errHTTPGet != nil {
+		return nil, errHTTPGet
+	}
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.New(fmt.Sprintf("HTTP status code is : %d", resp.StatusCode))
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// UnMarshalByte keeps only the aircraft with a position inside the bbox,
+// a receiver serves everything it hears whatever the area
+func (p *ADSBXProvider) UnMarshalByte(ctx context.Context, byt []byte, bbox tools.Bbox) ([]app.FlightData, error) {
+	var data aircraftResponse
+	var result []app.FlightData
+	if err := json.Unmarshal(byt, &data); err != nil {
+		return nil, err
+	}
+
+	now := data.Now
+	if now > 1e11 {
+		now = now / 1000
+	}
+
+	for _, ac := range append(data.Aircraft, data.Ac...) {
+		if ac.Lat == nil || ac.Lon == nil {
+			continue
+		}
+		if *ac.Lat < bbox.LatSW || *ac.Lat > bbox.LatNE || *ac.Lon < bbox.LonSW || *ac.Lon > bbox.LonNE {
+			continue
+		}
+
+		var altitude float64
+		switch alt := ac.AltBaro.(type) {
+		case float64:
+			altitude = alt
+		case string:
+			if alt != "ground" {
+				p.Log.WithContext(ctx).WithFields(logrus.Fields{
+					"Error in parsing alt_baro :": alt,
+				}).Error()
+			}
+		}
+
+		icao := strings.ToUpper(ac.Hex)
+		flightData := app.FlightData{
+			FlightID:         icao,
+			ICAO24BITADDRESS: icao,
+			Lat:              *ac.Lat,
+			Lon:              *ac.Lon,
+			Track:            int64(math.Round(ac.Track)),
+			Altitude:         int64(math.Round(altitude)),
+			GroundSpeed:      int64(math.Round(ac.Gs)),
+			Unknown1:         ac.Squawk,
+			TranspondeurType: ac.Type,
+			AircraftType:     ac.T,
+			Immatriculation1: ac.R,
+			TimeStamp:        math.Round(now - ac.SeenPos),
+			VerticalSpeed:    int64(math.Round(ac.BaroRate)),
+			Hint:             strings.TrimSpace(ac.Flight),
+		}
+		result = append(result, flightData)
+	}
+
+	return result, nil
+}
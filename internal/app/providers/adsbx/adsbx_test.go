@@ -0,0 +1,68 @@
+package adsbx
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func TestFetchFixture(t *testing.T) {
+	provider := New(log)
+	ctx := context.Background()
+
+	errInit := provider.Init(ctx, Configuration{Url: "testdata/aircraft.json"})
+	if errInit != nil {
+		t.Fatal(errInit)
+	}
+
+	bbox := tools.Bbox{
+		LatSW: 43.52,
+		LonSW: 1.32,
+		LatNE: 43.70,
+		LonNE: 1.69,
+	}
+
+	data, errFetch := provider.Fetch(ctx, bbox)
+	if errFetch != nil {
+		t.Fatal(errFetch)
+	}
+
+	//the aircraft outside the bbox and the one without position are dropped
+	if len(data) != 3 {
+		t.Fatalf("expected 3 flights, got %d", len(data))
+	}
+
+	flight := data[0]
+	if flight.ICAO24BITADDRESS != "39856C" || flight.Hint != "AFR61CJ" {
+		t.Errorf("unexpected identification %s / %s", flight.ICAO24BITADDRESS, flight.Hint)
+	}
+	if flight.Altitude != 3025 || flight.GroundSpeed != 182 || flight.Track != 143 || flight.VerticalSpeed != -704 {
+		t.Errorf("unexpected kinematics %+v", flight)
+	}
+	if flight.Lat != 43.612366 || flight.Lon != 1.381531 {
+		t.Errorf("unexpected position %f,%f", flight.Lat, flight.Lon)
+	}
+	if flight.TimeStamp != 1626940800 {
+		t.Errorf("unexpected timestamp %f", flight.TimeStamp)
+	}
+
+	if data[2].Altitude != 0 {
+		t.Errorf("aircraft on ground should have a zero altitude, got %d", data[2].Altitude)
+	}
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.TraceLevel
+	log.Out = os.Stdout
+}
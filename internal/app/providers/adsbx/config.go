@@ -0,0 +1,6 @@
+package adsbx
+
+// Configuration settings for readsb aircraft.json provider
+type Configuration struct {
+	Url string `toml:"url" default:"http://127.0.0.1/tar1090/data/aircraft.json" comment:"aircraft.json location (http(s) url or local file path)"`
+}
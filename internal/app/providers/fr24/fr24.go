@@ -0,0 +1,144 @@
+package fr24
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+const feedURL = "https://data-live.flightradar24.com/zones/fcgi/feed.js"
+
+type FR24Provider struct {
+	Log *logrus.Logger
+}
+
+func New(log *logrus.Logger) app.Provider {
+	//init the logger here
+	return &FR24Provider{Log: log}
+}
+
+func (p *FR24Provider) Init(ctx context.Context, params interface{}) error {
+	//Nothing to do here
+	return nil
+}
+
+func (p *FR24Provider) Fetch(ctx context.Context, bbox tools.Bbox) ([]app.FlightData, error) {
+	body, errRaw := p.GetRawData(ctx, bbox)
+	if errRaw != nil {
+		return nil, errRaw
+	}
+
+	return p.UnMarshalByte(ctx, body, bbox)
+}
+
+func (p *FR24Provider) GetRawData(ctx context.Context, bbox tools.Bbox) ([]byte, error) {
+	// Made the HTTP request - Test area 43.663712,1.570358,43.710510,1.700735
+	// Toulouse and Airport Area - 43.515693,1.318359,43.702630,1.687775
+	bounds := fmt.Sprintf("%.2f", bbox.LatNE) + "," + fmt.Sprintf("%.2f", bbox.LatSW) + "," + fmt.Sprintf("%.2f", bbox.LonSW) + "," + fmt.Sprintf("%.2f", bbox.LonNE)
+	resp, errHTTPGet := http.Get(feedURL + "?bounds=" + bounds + "&faa=1&satellite=1&mlat=1&flarm=1&adsb=1&gnd=1&air=1&vehicles=1&estimated=1&maxage=14400&gliders=1&stats=1")
+	if errHTTPGet != nil {
+		return nil, errHTTPGet
+	}
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.New(fmt.Sprintf("HTTP status code is : %d", resp.StatusCode))
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (p *FR24Provider) UnMarshalByte(ctx context.Context, byt []byte, bbox tools.Bbox) ([]app.FlightData, error) {
+
+	var data map[string]interface{}
+	var result []app.FlightData
+	if err := json.Unmarshal(byt, &data); err != nil {
+		return nil, err
+	}
+
+	for k, v := range data {
+		if k != "full_count" && k != "version" && k != "stats" {
+			if reflect.TypeOf(v).Kind() == reflect.Slice {
+				s := reflect.ValueOf(v)
+				_lat, err := strconv.ParseFloat(fmt.Sprintf("%v", s.Index(1)), 64)
+				if err != nil {
+					p.Log.WithContext(ctx).WithFields(logrus.Fields{
+						"Error in parsing _lat :": err,
+					}).Error()
+				}
+				_lon, err := strconv.ParseFloat(fmt.Sprintf("%v", s.Index(2)), 64)
+				if err != nil {
+					p.Log.WithContext(ctx).WithFields(logrus.Fields{
+						"Error in parsing _lon :": err,
+					}).Error()
+				}
+				_track, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(3)), 10, 64)
+				if err != nil {
+					p.Log.WithContext(ctx).WithFields(logrus.Fields{
+						"Error in parsing _track :": err,
+					}).Error()
+				}
+				_altitude, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(4)), 10, 64)
+				if err != nil {
+					p.Log.WithContext(ctx).WithFields(logrus.Fields{
+						"Error in parsing _altitude :": err,
+					}).Error()
+				}
+				_groundSpeed, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(5)), 10, 64)
+				if err != nil {
+					p.Log.WithContext(ctx).WithFields(logrus.Fields{
+						"Error in parsing _groundSpeed :": err,
+					}).Error()
+				}
+				_timeStamp, err := strconv.ParseFloat(fmt.Sprintf("%v", s.Index(10)), 64)
+				if err != nil {
+					p.Log.WithContext(ctx).WithFields(logrus.Fields{
+						"Error in parsing _timeStamp :": err,
+					}).Error()
+				}
+				_verticalSpeed, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(14)), 10, 64)
+				if err != nil {
+					p.Log.WithContext(ctx).WithFields(logrus.Fields{
+						"Error in parsing _verticalSpeed :": err,
+					}).Error()
+				}
+
+				flightData := app.FlightData{
+					FlightID:         k,
+					ICAO24BITADDRESS: fmt.Sprintf("%v", s.Index(0)),
+					Lat:              _lat,
+					Lon:              _lon,
+					Track:            _track,
+					Altitude:         _altitude,
+					GroundSpeed:      _groundSpeed,
+					Unknown1:         fmt.Sprintf("%v", s.Index(6)),
+					TranspondeurType: fmt.Sprintf("%v", s.Index(7)),
+					AircraftType:     fmt.Sprintf("%v", s.Index(8)),
+					Immatriculation1: fmt.Sprintf("%v", s.Index(9)),
+					TimeStamp:        _timeStamp,
+					Origine:          fmt.Sprintf("%v", s.Index(11)),
+					Destination:      fmt.Sprintf("%v", s.Index(12)),
+					Unknown2:         fmt.Sprintf("%v", s.Index(13)),
+					VerticalSpeed:    _verticalSpeed,
+					Immatriculation2: fmt.Sprintf("%v", s.Index(15)),
+					Hint:             fmt.Sprintf("%v", s.Index(16)),
+					Company:          fmt.Sprintf("%v", s.Index(17)),
+				}
+				result = append(result, flightData)
+			}
+		}
+	}
+
+	return result, nil
+}
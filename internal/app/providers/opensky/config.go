@@ -0,0 +1,8 @@
+package opensky
+
+// Configuration settings for OpenSky Network provider
+type Configuration struct {
+	Url      string `toml:"url" default:"https://opensky-network.org/api/states/all" comment:"OpenSky states API url"`
+	Username string `toml:"username" default:"" comment:"OpenSky user (optional, anonymous access if empty)"`
+	Password string `toml:"password" default:"" comment:"OpenSky password (optional)"`
+}
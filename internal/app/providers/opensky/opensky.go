@@ -0,0 +1,159 @@
+package opensky
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// index of each field in an OpenSky state vector
+// see https://openskynetwork.github.io/opensky-api/rest.html#all-state-vectors
+const (
+	idxIcao24 = iota
+	idxCallsign
+	idxOriginCountry
+	idxTimePosition
+	idxLastContact
+	idxLongitude
+	idxLatitude
+	idxBaroAltitude
+	idxOnGround
+	idxVelocity
+	idxTrueTrack
+	idxVerticalRate
+	idxSensors
+	idxGeoAltitude
+	idxSquawk
+	idxSpi
+	idxPositionSource
+)
+
+//statesResponse - OpenSky /states/all response body
+type statesResponse struct {
+	Time   int64           `json:"time"`
+	States [][]interface{} `json:"states"`
+}
+
+type OpenSkyProvider struct {
+	Log  *logrus.Logger
+	conf Configuration
+}
+
+func New(log *logrus.Logger) app.Provider {
+	//init the logger here
+	return &OpenSkyProvider{Log: log}
+}
+
+func (p *OpenSkyProvider) Init(ctx context.Context, params interface{}) error {
+	parameters := params.(Configuration)
+	p.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Url":       parameters.Url,
+		"Anonymous": parameters.Username == "",
+	}).Info("Initialisation OpenSky provider Parameters")
+
+	p.conf = parameters
+	return nil
+}
+
+func (p *OpenSkyProvider) Fetch(ctx context.Context, bbox tools.Bbox) ([]app.FlightData, error) {
+	body, errRaw := p.GetRawData(ctx, bbox)
+	if errRaw != nil {
+		return nil, errRaw
+	}
+
+	return p.UnMarshalByte(ctx, body, bbox)
+}
+
+func (p *OpenSkyProvider) GetRawData(ctx context.Context, bbox tools.Bbox) ([]byte, error) {
+	url := fmt.Sprintf("%s?lamin=%f&lomin=%f&lamax=%f&lomax=%f", p.conf.Url, bbox.LatSW, bbox.LonSW, bbox.LatNE, bbox.LonNE)
+	req, errReq := http.NewRequest(http.MethodGet, url, nil)
+	if errReq != nil {
+		return nil, errReq
+	}
+	req = req.WithContext(ctx)
+	if p.conf.Username != "" {
+		req.SetBasicAuth(p.conf.Username, p.conf.Password)
+	}
+
+	resp, errHTTPGet := http.DefaultClient.Do(req)
+	if errHTTPGet != nil {
+		return nil, errHTTPGet
+	}
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.New(fmt.Sprintf("HTTP status code is : %d", resp.StatusCode))
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (p *OpenSkyProvider) UnMarshalByte(ctx context.Context, byt []byte, bbox tools.Bbox) ([]app.FlightData, error) {
+	var data statesResponse
+	var result []app.FlightData
+	if err := json.Unmarshal(byt, &data); err != nil {
+		return nil, err
+	}
+
+	for _, state := range data.States {
+		if len(state) <= idxPositionSource {
+			p.Log.WithContext(ctx).WithFields(logrus.Fields{
+				"length": len(state),
+			}).Warning("OpenSky state vector malformed")
+			continue
+		}
+		//without position the flight can't be tracked
+		if state[idxLatitude] == nil || state[idxLongitude] == nil {
+			continue
+		}
+
+		timeStamp := asFloat(state[idxTimePosition])
+		if timeStamp == 0 {
+			timeStamp = asFloat(state[idxLastContact])
+		}
+
+		icao := strings.ToUpper(asString(state[idxIcao24]))
+		flightData := app.FlightData{
+			FlightID:         icao,
+			ICAO24BITADDRESS: icao,
+			Lat:              asFloat(state[idxLatitude]),
+			Lon:              asFloat(state[idxLongitude]),
+			Track:            int64(math.Round(asFloat(state[idxTrueTrack]))),
+			Altitude:         int64(math.Round(asFloat(state[idxBaroAltitude]) * app.METERTOFEET)),
+			GroundSpeed:      int64(math.Round(asFloat(state[idxVelocity]) * 3.6 / app.KTSKMH)),
+			Unknown1:         asString(state[idxSquawk]),
+			TimeStamp:        timeStamp,
+			VerticalSpeed:    int64(math.Round(asFloat(state[idxVerticalRate]) * app.METERTOFEET * 60)),
+			Hint:             strings.TrimSpace(asString(state[idxCallsign])),
+			Company:          asString(state[idxOriginCountry]),
+		}
+		result = append(result, flightData)
+	}
+
+	return result, nil
+}
+
+func asFloat(v interface{}) float64 {
+	if f, ok := v.(float64); ok {
+		return f
+	}
+	return 0
+}
+
+func asString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
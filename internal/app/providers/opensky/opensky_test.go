@@ -0,0 +1,100 @@
+package opensky
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func TestFetchFixture(t *testing.T) {
+	var query string
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		user, password, _ = r.BasicAuth()
+		http.ServeFile(w, r, "testdata/states.json")
+	}))
+	defer server.Close()
+
+	provider := New(log)
+	ctx := context.Background()
+
+	errInit := provider.Init(ctx, Configuration{Url: server.URL, Username: "john", Password: "secret"})
+	if errInit != nil {
+		t.Fatal(errInit)
+	}
+
+	bbox := tools.Bbox{
+		LatSW: 43.52,
+		LonSW: 1.32,
+		LatNE: 43.70,
+		LonNE: 1.69,
+	}
+
+	data, errFetch := provider.Fetch(ctx, bbox)
+	if errFetch != nil {
+		t.Fatal(errFetch)
+	}
+
+	if query != "lamin=43.520000&lomin=1.320000&lamax=43.700000&lomax=1.690000" {
+		t.Errorf("unexpected bbox query %s", query)
+	}
+	if user != "john" || password != "secret" {
+		t.Errorf("unexpected credentials %s / %s", user, password)
+	}
+
+	//the state without position and the malformed one are dropped
+	if len(data) != 2 {
+		t.Fatalf("expected 2 flights, got %d", len(data))
+	}
+
+	flight := data[0]
+	if flight.ICAO24BITADDRESS != "3C6444" || flight.FlightID != "3C6444" || flight.Hint != "DLH9LF" || flight.Company != "Germany" {
+		t.Errorf("unexpected identification %+v", flight)
+	}
+	//meters to feet, m/s to kts, m/s to ft/min
+	if flight.Altitude != 3025 || flight.GroundSpeed != 182 || flight.Track != 143 || flight.VerticalSpeed != -705 {
+		t.Errorf("unexpected kinematics %+v", flight)
+	}
+	if flight.Lat != 43.6124 || flight.Lon != 1.3815 || flight.Unknown1 != "1000" {
+		t.Errorf("unexpected position or squawk %+v", flight)
+	}
+	if flight.TimeStamp != 1626940798 {
+		t.Errorf("unexpected timestamp %f", flight.TimeStamp)
+	}
+
+	//on ground without altitude nor position time: zero altitude and last contact time
+	if data[1].Altitude != 0 || data[1].TimeStamp != 1626940795 {
+		t.Errorf("unexpected aircraft on ground %+v", data[1])
+	}
+}
+
+func TestFetchHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	provider := New(log)
+	provider.Init(context.Background(), Configuration{Url: server.URL})
+	if _, errFetch := provider.Fetch(context.Background(), tools.Bbox{}); errFetch == nil {
+		t.Error("expected an error on HTTP 429")
+	}
+}
+
+func init() {
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.TraceLevel
+	log.Out = os.Stdout
+}
@@ -0,0 +1,7 @@
+package sbs
+
+// Configuration settings for SBS-1 (BaseStation) TCP stream provider
+type Configuration struct {
+	Address string `toml:"address" default:"127.0.0.1:30003" comment:"SBS-1 BaseStation TCP stream address (host:port)"`
+	Timeout int    `toml:"timeout" default:"60" comment:"delay in second without message before an aircraft is dropped"`
+}
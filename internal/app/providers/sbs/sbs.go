@@ -0,0 +1,219 @@
+package sbs
+
+import (
+	"bufio"
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+// index of each field in a SBS-1 BaseStation message
+// see http://woodair.net/sbs/article/barebones42_socket_data.htm
+const (
+	idxMessageType = iota
+	idxTransmissionType
+	idxSessionID
+	idxAircraftID
+	idxHexIdent
+	idxFlightID
+	idxDateGenerated
+	idxTimeGenerated
+	idxDateLogged
+	idxTimeLogged
+	idxCallsign
+	idxAltitude
+	idxGroundSpeed
+	idxTrack
+	idxLatitude
+	idxLongitude
+	idxVerticalRate
+	idxSquawk
+	idxAlert
+	idxEmergency
+	idxSPI
+	idxIsOnGround
+)
+
+const (
+	reconnectDelay = 5 * time.Second
+	//aircraft timeout in second when the configured one is not positive
+	defaultTimeout = 60
+)
+
+//aircraftState - state of an aircraft assembled from successive MSG lines
+type aircraftState struct {
+	flight      app.FlightData
+	hasPosition bool
+	lastSeen    time.Time
+}
+
+type SBSProvider struct {
+	Log    *logrus.Logger
+	conf   Configuration
+	mu     sync.Mutex
+	states map[string]*aircraftState
+}
+
+func New(log *logrus.Logger) app.Provider {
+	//init the logger here
+	return &SBSProvider{Log: log, states: map[string]*aircraftState{}}
+}
+
+// Init starts listening the TCP stream until ctx is done
+func (p *SBSProvider) Init(ctx context.Context, params interface{}) error {
+	parameters := params.(Configuration)
+	if parameters.Timeout <= 0 {
+		//a missing timeout would expire every aircraft immediately
+		parameters.Timeout = defaultTimeout
+	}
+	p.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Address": parameters.Address,
+		"Timeout": parameters.Timeout,
+	}).Info("Initialisation SBS-1 provider Parameters")
+
+	p.conf = parameters
+	go p.listen(ctx)
+
+	return nil
+}
+
+// Fetch returns a snapshot of the aircraft seen recently inside the bbox
+func (p *SBSProvider) Fetch(ctx context.Context, bbox tools.Bbox) ([]app.FlightData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var result []app.FlightData
+	expiration := time.Now().Add(-time.Duration(p.conf.Timeout) * time.Second)
+	for icao, state := range p.states {
+		if state.lastSeen.Before(expiration) {
+			delete(p.states, icao)
+			continue
+		}
+		if !state.hasPosition {
+			continue
+		}
+		flight := state.flight
+		if flight.Lat < bbox.LatSW || flight.Lat > bbox.LatNE || flight.Lon < bbox.LonSW || flight.Lon > bbox.LonNE {
+			continue
+		}
+		result = append(result, flight)
+	}
+
+	return result, nil
+}
+
+func (p *SBSProvider) listen(ctx context.Context) {
+	for {
+		errConnect := p.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		p.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"Warning": errConnect,
+			"Address": p.conf.Address,
+		}).Warning("SBS-1 stream interrupted, reconnecting")
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *SBSProvider) connect(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, errDial := dialer.DialContext(ctx, "tcp", p.conf.Address)
+	if errDial != nil {
+		return errDial
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+			conn.Close()
+		}
+	}()
+
+	p.Log.WithContext(ctx).Info("Connected to SBS-1 stream : " + p.conf.Address)
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		p.update(strings.TrimSpace(scanner.Text()), time.Now())
+	}
+
+	return scanner.Err()
+}
+
+// update merges a MSG line into the aircraft state, each transmission type
+// only fills part of the fields so empty fields keep the previous value
+func (p *SBSProvider) update(line string, now time.Time) {
+	fields := strings.Split(line, ",")
+	if len(fields) <= idxAlert || fields[idxMessageType] != "MSG" || fields[idxHexIdent] == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	icao := strings.ToUpper(fields[idxHexIdent])
+	state, ok := p.states[icao]
+	if !ok {
+		state = &aircraftState{flight: app.FlightData{FlightID: icao, ICAO24BITADDRESS: icao}}
+		p.states[icao] = state
+	}
+	state.lastSeen = now
+
+	if callsign := strings.TrimSpace(fields[idxCallsign]); callsign != "" {
+		state.flight.Hint = callsign
+	}
+	if altitude, ok := parseFloat(fields[idxAltitude]); ok {
+		state.flight.Altitude = int64(math.Round(altitude))
+	}
+	if groundSpeed, ok := parseFloat(fields[idxGroundSpeed]); ok {
+		state.flight.GroundSpeed = int64(math.Round(groundSpeed))
+	}
+	if track, ok := parseFloat(fields[idxTrack]); ok {
+		state.flight.Track = int64(math.Round(track))
+	}
+	if verticalRate, ok := parseFloat(fields[idxVerticalRate]); ok {
+		state.flight.VerticalSpeed = int64(math.Round(verticalRate))
+	}
+	if fields[idxSquawk] != "" {
+		state.flight.Unknown1 = fields[idxSquawk]
+	}
+
+	lat, okLat := parseFloat(fields[idxLatitude])
+	lon, okLon := parseFloat(fields[idxLongitude])
+	if okLat && okLon {
+		state.flight.Lat = lat
+		state.flight.Lon = lon
+		state.hasPosition = true
+
+		generated, errTime := time.ParseInLocation("2006/01/02 15:04:05.000", fields[idxDateGenerated]+" "+fields[idxTimeGenerated], time.Local)
+		if errTime != nil {
+			generated = now
+		}
+		state.flight.TimeStamp = float64(generated.Unix())
+	}
+}
+
+func parseFloat(field string) (float64, bool) {
+	if field == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(field, 64)
+	return value, err == nil
+}
@@ -0,0 +1,99 @@
+package sbs
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func TestReplayCapture(t *testing.T) {
+	capture, errRead := ioutil.ReadFile("testdata/capture.sbs")
+	if errRead != nil {
+		t.Fatal(errRead)
+	}
+
+	//local server replaying the recorded capture like dump1090 port 30003
+	listener, errListen := net.Listen("tcp", "127.0.0.1:0")
+	if errListen != nil {
+		t.Fatal(errListen)
+	}
+	defer listener.Close()
+	go func() {
+		conn, errAccept := listener.Accept()
+		if errAccept != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write(capture)
+		time.Sleep(time.Second)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//without timeout, as an existing config file without the key, the default one applies
+	provider := New(log)
+	errInit := provider.Init(ctx, Configuration{Address: listener.Addr().String()})
+	if errInit != nil {
+		t.Fatal(errInit)
+	}
+
+	bbox := tools.Bbox{
+		LatSW: 43.52,
+		LonSW: 1.32,
+		LatNE: 43.70,
+		LonNE: 1.69,
+	}
+
+	var data []app.FlightData
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		var errFetch error
+		data, errFetch = provider.Fetch(ctx, bbox)
+		if errFetch != nil {
+			t.Fatal(errFetch)
+		}
+		if len(data) == 1 && data[0].Altitude == 2975 {
+			break
+		}
+	}
+
+	//4CA7B5 is outside the bbox and 3965A2 has no position
+	if len(data) != 1 {
+		t.Fatalf("expected 1 flight, got %d", len(data))
+	}
+
+	flight := data[0]
+	if flight.ICAO24BITADDRESS != "39856C" || flight.Hint != "AFR61CJ" || flight.Unknown1 != "1000" {
+		t.Errorf("unexpected identification %+v", flight)
+	}
+	if flight.Lat != 43.61012 || flight.Lon != 1.38411 || flight.Altitude != 2975 {
+		t.Errorf("unexpected position %+v", flight)
+	}
+	if flight.GroundSpeed != 182 || flight.Track != 143 || flight.VerticalSpeed != -704 {
+		t.Errorf("unexpected velocity %+v", flight)
+	}
+	expected := time.Date(2021, 07, 22, 10, 00, 01, 0, time.Local)
+	if flight.TimeStamp != float64(expected.Unix()) {
+		t.Errorf("unexpected timestamp %f", flight.TimeStamp)
+	}
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.TraceLevel
+	log.Out = os.Stdout
+}
@@ -0,0 +1,23 @@
+package rules
+
+// Configuration settings for illegal flight detection
+type Configuration struct {
+	Profile         string              `toml:"profile" default:"" comment:"builtin regulation profile applied in addition to the rules (FR), none if empty"`
+	Areas           string              `toml:"areas" default:"" comment:"GeoJSON file of the agglomeration polygons for the profile (category or width property)"`
+	DefaultClass    string              `toml:"defaultClass" default:"single" comment:"aircraft class used by the profile when the aircraft type is unknown (single|multi|helicopter)"`
+	AircraftClasses map[string]string   `toml:"aircraftClasses" comment:"aircraft class by aircraft type (ICAO code) overriding the builtin ones"`
+	Rule            []RuleConfiguration `toml:"rule" comment:"illegal flight rules ([[Flighttracker.rules.rule]] tables), the default rule (25m < altitude < 500m and moving) is used when empty without profile"`
+}
+
+// RuleConfiguration describes the flights considered as illegal, all the criteria have to match
+type RuleConfiguration struct {
+	ID            string   `toml:"id" comment:"rule identifier reported with the violation"`
+	Reason        string   `toml:"reason" comment:"human readable reason reported with the violation"`
+	MinAltitude   float64  `toml:"minAltitude" comment:"altitude in meter above which the rule applies"`
+	MaxAltitude   float64  `toml:"maxAltitude" comment:"altitude in meter below which the rule applies (0 for no limit)"`
+	MinSpeed      float64  `toml:"minSpeed" comment:"ground speed in km/h above which the rule applies"`
+	MaxSpeed      float64  `toml:"maxSpeed" comment:"ground speed in km/h below which the rule applies (0 for no limit)"`
+	AircraftTypes []string `toml:"aircraftTypes" comment:"aircraft types (ICAO code) concerned, all if empty"`
+	Callsigns     []string `toml:"callsigns" comment:"callsign regular expressions concerned, all if empty"`
+	Zones         []string `toml:"zones" comment:"monitoring zone names or bbox (Lat/Lon) where the rule applies, everywhere if empty"`
+}
@@ -0,0 +1,180 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+// French low overflight regulation profile
+// see https://www.ecologie.gouv.fr/sites/default/files/Guide_autorisation_survol_basses_hauteurs.pdf
+// and https://www.insee.fr/fr/metadonnees/definition/c1501 for the agglomeration definition
+
+// overflown area categories, the agglomerations are classified by their average width
+const (
+	AreaOpen   = "open"   // open country, outside agglomeration
+	AreaSmall  = "small"  // agglomeration narrower than 1200 m
+	AreaMedium = "medium" // agglomeration between 1200 m and 3600 m wide
+	AreaLarge  = "large"  // agglomeration wider than 3600 m
+)
+
+// aircraft classes
+const (
+	ClassSingleEngine = "single"
+	ClassMultiEngine  = "multi"
+	ClassHelicopter   = "helicopter"
+)
+
+// franceMinimumHeights - minimum height in meter by area category and aircraft class
+var franceMinimumHeights = map[string]map[string]float64{
+	AreaOpen:   {ClassSingleEngine: 150, ClassMultiEngine: 150, ClassHelicopter: 150},
+	AreaSmall:  {ClassSingleEngine: 500, ClassMultiEngine: 1000, ClassHelicopter: 500},
+	AreaMedium: {ClassSingleEngine: 1000, ClassMultiEngine: 1500, ClassHelicopter: 1000},
+	AreaLarge:  {ClassSingleEngine: 1500, ClassMultiEngine: 3000, ClassHelicopter: 1500},
+}
+
+var areaLabels = map[string]string{
+	AreaOpen:   "open country",
+	AreaSmall:  "agglomeration narrower than 1200m",
+	AreaMedium: "agglomeration between 1200m and 3600m wide",
+	AreaLarge:  "agglomeration wider than 3600m",
+}
+
+var classLabels = map[string]string{
+	ClassSingleEngine: "single-engine aircraft",
+	ClassMultiEngine:  "multi-engine aircraft",
+	ClassHelicopter:   "helicopter",
+}
+
+// builtin aircraft classes by ICAO type designator, the other known types are considered as multi-engine
+var builtinAircraftClasses = map[string]string{}
+
+func init() {
+	for _, t := range []string{"R22", "R44", "R66", "EC20", "EC25", "EC30", "EC35", "EC45", "EC55", "EC75", "H160",
+		"AS50", "AS55", "AS65", "AS32", "AS3B", "A109", "A119", "A139", "A169", "B06", "B407", "B429", "S76", "NH90",
+		"ALO2", "ALO3", "GAZL", "CABR", "G2CA"} {
+		builtinAircraftClasses[t] = ClassHelicopter
+	}
+	for _, t := range []string{"C150", "C152", "C162", "C172", "C177", "C182", "C206", "C208", "C210", "P28A", "P28B",
+		"P28R", "PA18", "PA22", "PA24", "PA32", "PA46", "DR40", "DR10", "DR22", "DR30", "D140", "TB9", "TB10",
+		"TB20", "TB21", "SR20", "SR22", "M20P", "M20T", "BE33", "BE35", "BE36", "DA20", "DA40", "TBM7", "TBM8",
+		"TBM9", "PC6T", "PC12", "RALL", "CAP1", "CP10", "CAP2", "S22T", "WT9", "ULAC"} {
+		builtinAircraftClasses[t] = ClassSingleEngine
+	}
+}
+
+//franceProfile - minimum heights depending on overflown area and aircraft class
+type franceProfile struct {
+	areas           []tools.Feature
+	areaCategories  []string
+	aircraftClasses map[string]string
+	defaultClass    string
+}
+
+func newFranceProfile(conf Configuration) (*franceProfile, error) {
+	profile := &franceProfile{
+		aircraftClasses: map[string]string{},
+		defaultClass:    conf.DefaultClass,
+	}
+	if profile.defaultClass == "" {
+		profile.defaultClass = ClassSingleEngine
+	}
+	if _, ok := classLabels[profile.defaultClass]; !ok {
+		return nil, fmt.Errorf("profile FR - unknown aircraft class %s", profile.defaultClass)
+	}
+
+	for aircraftType, class := range conf.AircraftClasses {
+		if _, ok := classLabels[class]; !ok {
+			return nil, fmt.Errorf("profile FR - unknown aircraft class %s for type %s", class, aircraftType)
+		}
+		profile.aircraftClasses[strings.ToUpper(aircraftType)] = class
+	}
+
+	if conf.Areas != "" {
+		features, err := tools.LoadGeoJSON(conf.Areas)
+		if err != nil {
+			return nil, fmt.Errorf("profile FR - unable to load areas: %v", err)
+		}
+		for idx, feature := range features {
+			category, err := areaCategory(feature.Properties)
+			if err != nil {
+				return nil, fmt.Errorf("profile FR - area %d: %v", idx, err)
+			}
+			profile.areas = append(profile.areas, feature)
+			profile.areaCategories = append(profile.areaCategories, category)
+		}
+	}
+
+	return profile, nil
+}
+
+// areaCategory reads the "category" property, or computes it from the "width" property (meter)
+func areaCategory(properties map[string]interface{}) (string, error) {
+	if category, ok := properties["category"].(string); ok {
+		if _, known := franceMinimumHeights[category]; !known {
+			return "", fmt.Errorf("unknown category %s", category)
+		}
+		return category, nil
+	}
+	if width, ok := properties["width"].(float64); ok {
+		switch {
+		case width < 1200:
+			return AreaSmall, nil
+		case width <= 3600:
+			return AreaMedium, nil
+		default:
+			return AreaLarge, nil
+		}
+	}
+	return "", fmt.Errorf("need a category (%s|%s|%s|%s) or a width property", AreaOpen, AreaSmall, AreaMedium, AreaLarge)
+}
+
+// category of the area overflown, the most restrictive one when areas overlap
+func (p *franceProfile) category(lat, lon float64) string {
+	result := AreaOpen
+	for idx, feature := range p.areas {
+		category := p.areaCategories[idx]
+		if franceMinimumHeights[category][ClassMultiEngine] > franceMinimumHeights[result][ClassMultiEngine] &&
+			feature.Contains(lat, lon) {
+			result = category
+		}
+	}
+	return result
+}
+
+func (p *franceProfile) class(aircraftType string) string {
+	aircraftType = strings.ToUpper(aircraftType)
+	if class, ok := p.aircraftClasses[aircraftType]; ok {
+		return class
+	}
+	if class, ok := builtinAircraftClasses[aircraftType]; ok {
+		return class
+	}
+	if aircraftType == "" {
+		return p.defaultClass
+	}
+	return ClassMultiEngine
+}
+
+func (p *franceProfile) evaluate(flight app.FlightData) (Violation, bool) {
+	altitude := float64(flight.Altitude) * app.FEETTOMETER
+	//same floor as the default rule: on ground or taking off flights are not reported
+	if altitude <= DefaultRule.MinAltitude || flight.GroundSpeed <= 0 {
+		return Violation{}, false
+	}
+
+	category := p.category(flight.Lat, flight.Lon)
+	class := p.class(flight.AircraftType)
+	minimum := franceMinimumHeights[category][class]
+	if altitude >= minimum {
+		return Violation{}, false
+	}
+
+	return Violation{
+		RuleID: fmt.Sprintf("FR-%s-%s", category, class),
+		Reason: fmt.Sprintf("%s at %.0fm over %s (minimum %.0fm)", classLabels[class], altitude, areaLabels[category], minimum),
+		Flight: flight,
+	}, true
+}
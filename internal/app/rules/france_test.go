@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+// areas - a village 800m wide with a park (hole) inside a large city, and a medium town
+const areas = `{"type": "FeatureCollection", "features": [
+	{"type": "Feature", "properties": {"width": 800}, "geometry": {"type": "Polygon", "coordinates": [
+		[[1.40, 43.60], [1.42, 43.60], [1.42, 43.62], [1.40, 43.62], [1.40, 43.60]],
+		[[1.405, 43.605], [1.410, 43.605], [1.410, 43.610], [1.405, 43.610], [1.405, 43.605]]]}},
+	{"type": "Feature", "properties": {"category": "large"}, "geometry": {"type": "Polygon", "coordinates": [
+		[[1.415, 43.615], [1.45, 43.615], [1.45, 43.65], [1.415, 43.65], [1.415, 43.615]]]}},
+	{"type": "Feature", "properties": {"width": 2000}, "geometry": {"type": "MultiPolygon", "coordinates": [
+		[[[1.50, 43.50], [1.52, 43.50], [1.52, 43.52], [1.50, 43.52], [1.50, 43.50]]]]}}
+]}`
+
+func franceEngine(t *testing.T, geojson string) (*Engine, error) {
+	path := filepath.Join(t.TempDir(), "areas.geojson")
+	if err := os.WriteFile(path, []byte(geojson), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return New(Configuration{Profile: "FR", Areas: path, DefaultClass: "single", AircraftClasses: map[string]string{"zzzz": "helicopter"}})
+}
+
+func TestFranceProfile(t *testing.T) {
+	engine, err := franceEngine(t, areas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		lat, lon     float64
+		altitude     int64 //feet
+		aircraftType string
+		rule         string //empty without violation
+	}{
+		{"single-engine at 100m in open country", 43.0, 1.0, 328, "C172", "FR-open-single"},
+		{"single-engine at 200m in open country", 43.0, 1.0, 656, "C172", ""},
+		{"single-engine at 400m over the village", 43.601, 1.401, 1312, "DR40", "FR-small-single"},
+		{"single-engine at 600m over the village", 43.601, 1.401, 1968, "DR40", ""},
+		{"airliner at 600m over the village", 43.601, 1.401, 1968, "A320", "FR-small-multi"},
+		{"airliner at 1100m over the village", 43.601, 1.401, 3608, "A320", ""},
+		{"single-engine at 100m over the park of the village", 43.607, 1.407, 328, "DR40", "FR-open-single"},
+		{"single-engine at 400m over the park of the village", 43.607, 1.407, 1312, "DR40", ""},
+		{"helicopter at 400m on the west edge of the village", 43.601, 1.40, 1312, "R44", "FR-small-helicopter"},
+		{"helicopter at 1200m over the village inside the city", 43.618, 1.418, 3937, "EC35", "FR-large-helicopter"},
+		{"helicopter at 1200m over the town", 43.51, 1.51, 3937, "EC35", ""},
+		{"helicopter at 900m over the town", 43.51, 1.51, 2953, "EC35", "FR-medium-helicopter"},
+		{"configured class at 400m over the village", 43.601, 1.401, 1312, "ZZZZ", "FR-small-helicopter"},
+		{"unknown type at 400m over the village", 43.601, 1.401, 1312, "", "FR-small-single"},
+		{"single-engine on ground", 43.601, 1.401, 0, "DR40", ""},
+	}
+	for _, test := range tests {
+		flight := app.FlightData{Lat: test.lat, Lon: test.lon, Altitude: test.altitude, GroundSpeed: 90, AircraftType: test.aircraftType}
+		var rule string
+		for _, violation := range engine.Evaluate(flight) {
+			rule = violation.RuleID
+		}
+		if rule != test.rule {
+			t.Errorf("%s: expected rule %q, got %q", test.name, test.rule, rule)
+		}
+	}
+}
+
+func TestAreaCategory(t *testing.T) {
+	tests := []struct {
+		properties map[string]interface{}
+		category   string
+	}{
+		{map[string]interface{}{"category": "open"}, AreaOpen},
+		{map[string]interface{}{"category": "medium", "width": 100.0}, AreaMedium},
+		{map[string]interface{}{"width": 1199.0}, AreaSmall},
+		{map[string]interface{}{"width": 1200.0}, AreaMedium},
+		{map[string]interface{}{"width": 3600.0}, AreaMedium},
+		{map[string]interface{}{"width": 3601.0}, AreaLarge},
+		{map[string]interface{}{"category": "huge"}, ""},
+		{map[string]interface{}{"width": "wide"}, ""},
+		{map[string]interface{}{}, ""},
+	}
+	for _, test := range tests {
+		category, err := areaCategory(test.properties)
+		if category != test.category || (err == nil) != (test.category != "") {
+			t.Errorf("%v: expected category %q, got %q (%v)", test.properties, test.category, category, err)
+		}
+	}
+}
+
+func TestFranceProfileMalformed(t *testing.T) {
+	for name, geojson := range map[string]string{
+		"not GeoJSON":        `POLYGON((0 0, 1 0, 1 1, 0 0))`,
+		"without polygon":    `{"type": "FeatureCollection", "features": []}`,
+		"without category":   `{"type": "Feature", "properties": {}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`,
+		"coordinates broken": `{"type": "Polygon", "coordinates": [0, 0]}`,
+	} {
+		if _, err := franceEngine(t, geojson); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := New(Configuration{Profile: "FR", DefaultClass: "glider"}); err == nil {
+		t.Error("expected an error for an unknown default class")
+	}
+	if _, err := New(Configuration{Profile: "FR", AircraftClasses: map[string]string{"DR40": "glider"}}); err == nil {
+		t.Error("expected an error for an unknown aircraft class")
+	}
+}
@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+// DefaultRule is applied when no rule is configured: flight between 25 and 500 meters that moving
+var DefaultRule = RuleConfiguration{
+	ID:          "low-altitude",
+	Reason:      "flight under 500 meters",
+	MinAltitude: 25,
+	MaxAltitude: 500,
+}
+
+//Violation - a flight matching an illegal flight rule
+type Violation struct {
+	RuleID string         `json:"ruleID"`
+	Reason string         `json:"reason"`
+	Flight app.FlightData `json:"flight"`
+}
+
+type rule struct {
+	conf          RuleConfiguration
+	aircraftTypes map[string]bool
+	callsigns     []*regexp.Regexp
+	zones         []tools.Bbox
+	zoneNames     map[string]bool
+}
+
+//Engine - evaluate the configured rules and regulation profile on flights
+type Engine struct {
+	rules   []rule
+	profile *franceProfile
+}
+
+func New(conf Configuration) (*Engine, error) {
+	engine := &Engine{}
+
+	switch conf.Profile {
+	case "":
+	case "FR":
+		profile, err := newFranceProfile(conf)
+		if err != nil {
+			return nil, err
+		}
+		engine.profile = profile
+	default:
+		return nil, fmt.Errorf("unknown regulation profile %s", conf.Profile)
+	}
+
+	rulesConf := conf.Rule
+	if len(rulesConf) == 0 && engine.profile == nil {
+		rulesConf = []RuleConfiguration{DefaultRule}
+	}
+
+	for idx, ruleConf := range rulesConf {
+		if ruleConf.ID == "" {
+			ruleConf.ID = fmt.Sprintf("rule-%d", idx+1)
+		}
+		r := rule{conf: ruleConf, aircraftTypes: map[string]bool{}, zoneNames: map[string]bool{}}
+
+		for _, aircraftType := range ruleConf.AircraftTypes {
+			r.aircraftTypes[strings.ToUpper(aircraftType)] = true
+		}
+		for _, callsign := range ruleConf.Callsigns {
+			re, err := regexp.Compile(callsign)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s - callsign pattern malformed: %v", ruleConf.ID, err)
+			}
+			r.callsigns = append(r.callsigns, re)
+		}
+		for _, zone := range ruleConf.Zones {
+			//a monitoring zone name, the flights are tagged with their zones
+			if !strings.Contains(zone, "^") {
+				r.zoneNames[zone] = true
+				continue
+			}
+			bbox, err := tools.GetBbox(zone)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s - zone malformed: %v", ruleConf.ID, err)
+			}
+			r.zones = append(r.zones, bbox)
+		}
+
+		engine.rules = append(engine.rules, r)
+	}
+
+	return engine, nil
+}
+
+// Evaluate returns a violation for each rule the flight matches
+func (e *Engine) Evaluate(flight app.FlightData) []Violation {
+	var result []Violation
+	if e.profile != nil {
+		if violation, ok := e.profile.evaluate(flight); ok {
+			result = append(result, violation)
+		}
+	}
+	for _, r := range e.rules {
+		if r.match(flight) {
+			result = append(result, Violation{
+				RuleID: r.conf.ID,
+				Reason: r.conf.Reason,
+				Flight: flight,
+			})
+		}
+	}
+	return result
+}
+
+// Violations evaluates all the flights of a tick
+func (e *Engine) Violations(data []app.FlightData) []Violation {
+	var result []Violation
+	for _, flight := range data {
+		result = append(result, e.Evaluate(flight)...)
+	}
+	return result
+}
+
+func (r rule) match(flight app.FlightData) bool {
+	altitude := float64(flight.Altitude) * app.FEETTOMETER
+	speed := float64(flight.GroundSpeed) * app.KTSKMH
+
+	if altitude <= r.conf.MinAltitude || (r.conf.MaxAltitude > 0 && altitude >= r.conf.MaxAltitude) {
+		return false
+	}
+	if speed <= r.conf.MinSpeed || (r.conf.MaxSpeed > 0 && speed >= r.conf.MaxSpeed) {
+		return false
+	}
+	if len(r.aircraftTypes) > 0 && !r.aircraftTypes[strings.ToUpper(flight.AircraftType)] {
+		return false
+	}
+	if len(r.callsigns) > 0 && !matchAny(r.callsigns, flight.Hint) {
+		return false
+	}
+	if (len(r.zones) > 0 || len(r.zoneNames) > 0) && !inAny(r.zones, flight.Lat, flight.Lon) && !taggedAny(r.zoneNames, flight.Zones) {
+		return false
+	}
+	return true
+}
+
+func matchAny(patterns []*regexp.Regexp, value string) bool {
+	for _, re := range patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func taggedAny(names map[string]bool, flightZones []string) bool {
+	for _, zone := range flightZones {
+		if names[zone] {
+			return true
+		}
+	}
+	return false
+}
+
+func inAny(zones []tools.Bbox, lat, lon float64) bool {
+	for _, bbox := range zones {
+		if lat >= bbox.LatSW && lat <= bbox.LatNE && lon >= bbox.LonSW && lon <= bbox.LonNE {
+			return true
+		}
+	}
+	return false
+}
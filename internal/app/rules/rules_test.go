@@ -0,0 +1,133 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+// flight - a position with its altitude in feet and ground speed in kts
+func flight(altitude, speed int64) app.FlightData {
+	return app.FlightData{FlightID: "F1", Lat: 43.6, Lon: 1.44, Altitude: altitude, GroundSpeed: speed, AircraftType: "R44", Hint: "FGABC"}
+}
+
+func TestDefaultRule(t *testing.T) {
+	engine, err := New(Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		flight   app.FlightData
+		violates bool
+	}{
+		{"300m moving", flight(1000, 100), true},
+		{"under 25m", flight(50, 100), false},
+		{"over 500m", flight(2000, 100), false},
+		{"not moving", flight(1000, 0), false},
+	}
+	for _, test := range tests {
+		violations := engine.Evaluate(test.flight)
+		if got := len(violations) == 1; got != test.violates {
+			t.Errorf("%s: expected violation %v, got %+v", test.name, test.violates, violations)
+		}
+		if test.violates && (violations[0].RuleID != DefaultRule.ID || violations[0].Reason != DefaultRule.Reason || !reflect.DeepEqual(violations[0].Flight, test.flight)) {
+			t.Errorf("%s: unexpected violation %+v", test.name, violations[0])
+		}
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	//between 1000ft (304.8m) and 3000ft (914.4m), between 100kts (185.2km/h) and 200kts (370.4km/h)
+	engine, err := New(Configuration{Rule: []RuleConfiguration{{ID: "window", MinAltitude: 300, MaxAltitude: 900, MinSpeed: 180, MaxSpeed: 360}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		altitude int64
+		speed    int64
+		violates bool
+	}{
+		{"inside", 2000, 150, true},
+		{"just above the minimum altitude", 985, 150, true},
+		{"just below the minimum altitude", 984, 150, false},
+		{"just below the maximum altitude", 2952, 150, true},
+		{"just above the maximum altitude", 2953, 150, false},
+		{"just above the minimum speed", 2000, 98, true},
+		{"just below the minimum speed", 2000, 97, false},
+		{"just below the maximum speed", 2000, 194, true},
+		{"just above the maximum speed", 2000, 195, false},
+	}
+	for _, test := range tests {
+		if got := len(engine.Evaluate(flight(test.altitude, test.speed))) == 1; got != test.violates {
+			t.Errorf("%s (%dft, %dkts): expected violation %v", test.name, test.altitude, test.speed, test.violates)
+		}
+	}
+}
+
+func TestRuleSelection(t *testing.T) {
+	engine, err := New(Configuration{Rule: []RuleConfiguration{
+		{ID: "helicopters", AircraftTypes: []string{"r44", "EC35"}},
+		{ID: "private", Callsigns: []string{"^FG", "^FH"}},
+		{ID: "bbox", Zones: []string{"43.5,1.4^43.7,1.5"}},
+		{ID: "village", Zones: []string{"village"}},
+		{Reason: "unnamed"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	airliner := flight(1000, 100)
+	airliner.AircraftType, airliner.Hint, airliner.Lat = "A320", "AFR61CJ", 48.85
+	tagged := airliner
+	tagged.Zones = []string{"village"}
+
+	tests := []struct {
+		name   string
+		flight app.FlightData
+		rules  []string
+	}{
+		{"helicopter in the bbox", flight(1000, 100), []string{"helicopters", "private", "bbox", "rule-5"}},
+		{"airliner outside of the zones", airliner, []string{"rule-5"}},
+		{"airliner tagged in the village", tagged, []string{"village", "rule-5"}},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, violation := range engine.Violations([]app.FlightData{test.flight}) {
+			got = append(got, violation.RuleID)
+		}
+		if !reflect.DeepEqual(got, test.rules) {
+			t.Errorf("%s: expected rules %v, got %v", test.name, test.rules, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		conf  Configuration
+		valid bool
+		rules int
+	}{
+		{"default rule", Configuration{}, true, 1},
+		{"configured rules replace the default one", Configuration{Rule: []RuleConfiguration{{ID: "a"}, {ID: "b"}}}, true, 2},
+		{"profile without rule", Configuration{Profile: "FR"}, true, 0},
+		{"unknown profile", Configuration{Profile: "XX"}, false, 0},
+		{"callsign pattern malformed", Configuration{Rule: []RuleConfiguration{{Callsigns: []string{"("}}}}, false, 0},
+		{"zone malformed", Configuration{Rule: []RuleConfiguration{{Zones: []string{"43.5^1.4"}}}}, false, 0},
+	}
+	for _, test := range tests {
+		engine, err := New(test.conf)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.valid, err)
+			continue
+		}
+		if err == nil && len(engine.rules) != test.rules {
+			t.Errorf("%s: expected %d rules, got %d", test.name, test.rules, len(engine.rules))
+		}
+	}
+}
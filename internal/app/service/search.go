@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/francois-poidevin/flighttracker/internal/app"
@@ -14,6 +15,7 @@ import (
 
 type Service struct {
 	Log *logrus.Logger
+	mu  sync.Mutex //guard the lazy connection of the concurrent searches
 	db  *sql.DB
 }
 
@@ -28,13 +30,18 @@ func New(log *logrus.Logger) app.Service {
 }
 
 func (s *Service) Search(ctx context.Context, params interface{}, bbox tools.Bbox, altThresholdFeet int, fromTimeStamp, toTimeStamp time.Time) ([]app.FlightData, error) {
+	return s.SearchArea(ctx, params, tools.BboxToWKT(bbox), altThresholdFeet, fromTimeStamp, toTimeStamp)
+}
+
+// SearchArea - same as Search inside a POLYGON or MULTIPOLYGON WKT area
+func (s *Service) SearchArea(ctx context.Context, params interface{}, areaWKT string, altThresholdFeet int, fromTimeStamp, toTimeStamp time.Time) ([]app.FlightData, error) {
 	//Do the search logical here
 	s.Log.WithContext(ctx).Info("Search service called")
 
 	//check if service have a db connection
-	if s.db == nil {
-		s.Log.WithContext(ctx).Info("Search service - init DB")
-		s.init(ctx, params)
+	db, errInit := s.connection(ctx, params)
+	if errInit != nil {
+		return nil, errInit
 	}
 
 	//search SQL statement
@@ -44,8 +51,8 @@ func (s *Service) Search(ctx context.Context, params interface{}, bbox tools.Bbo
 		"SQL": selectSQLstmt,
 	}).Info("Select statement")
 
-	rows, errQuery := s.db.Query(selectSQLstmt,
-		tools.BboxToWKT(bbox),
+	rows, errQuery := db.Query(selectSQLstmt,
+		areaWKT,
 		altThresholdFeet,
 		fromTimeStamp,
 		toTimeStamp,
@@ -118,6 +125,30 @@ func (s *Service) Search(ctx context.Context, params interface{}, bbox tools.Bbo
 	return result, nil
 }
 
+//connection - the db connection, opened by the first search
+func (s *Service) connection(ctx context.Context, params interface{}) (*sql.DB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		s.Log.WithContext(ctx).Info("Search service - init DB")
+		errInit := s.init(ctx, params)
+		if errInit != nil {
+			return nil, errInit
+		}
+	}
+	return s.db, nil
+}
+
+//Close - close the db connection, if any
+func (s *Service) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db != nil {
+		s.db.Close()
+		s.db = nil
+	}
+}
+
 func (s *Service) init(ctx context.Context, params interface{}) error {
 	parameters := params.(db.Configuration)
 
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/sqlite"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
+	"github.com/sirupsen/logrus"
+)
+
+//SQLiteService - search in the SQLite embedded database
+type SQLiteService struct {
+	Log *logrus.Logger
+	mu  sync.Mutex //guard the lazy connection of the concurrent searches
+	db  *sql.DB
+}
+
+func NewSQLite(log *logrus.Logger) app.Service {
+	//init the logger here
+	return &SQLiteService{Log: log}
+}
+
+func (s *SQLiteService) Search(ctx context.Context, params interface{}, bbox tools.Bbox, altThresholdFeet int, fromTimeStamp, toTimeStamp time.Time) ([]app.FlightData, error) {
+	return s.SearchArea(ctx, params, tools.BboxToWKT(bbox), altThresholdFeet, fromTimeStamp, toTimeStamp)
+}
+
+// SearchArea - the R-tree selects the positions in the area envelope, then the exact area is tested
+func (s *SQLiteService) SearchArea(ctx context.Context, params interface{}, areaWKT string, altThresholdFeet int, fromTimeStamp, toTimeStamp time.Time) ([]app.FlightData, error) {
+	s.Log.WithContext(ctx).Info("SQLite search service called")
+
+	polygons, errWKT := tools.ParseWKT(areaWKT)
+	if errWKT != nil {
+		return nil, errWKT
+	}
+	if len(polygons) == 0 {
+		return nil, errors.New("search area without polygon")
+	}
+
+	//check if service have a db connection
+	db, errOpen := s.connection(ctx, params)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	envelope := zones.Envelope([]zones.Zone{{Polygons: polygons}})
+
+	selectSQLstmt := "SELECT f.FlightID, f.ICAO24BITADDRESS, f.Lat, f.Lon, f.Track, f.Altitude, f.GroundSpeed, f.Unknown1, f.TranspondeurType, f.AircraftType, f.Immatriculation1, f.TimeStamp, f.Origine, f.Destination, f.Unknown2, f.VerticalSpeed, f.Immatriculation2, f.Hint, f.Company FROM flight_rtree r JOIN flight f ON f.id = r.id WHERE r.minLon >= ? AND r.maxLon <= ? AND r.minLat >= ? AND r.maxLat <= ? AND f.Altitude <= ? AND f.TimeStamp BETWEEN ? AND ? ORDER BY f.TimeStamp, f.id"
+
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"SQL": selectSQLstmt,
+	}).Info("Select statement")
+
+	rows, errQuery := db.QueryContext(ctx, selectSQLstmt,
+		envelope.LonSW, envelope.LonNE, envelope.LatSW, envelope.LatNE,
+		altThresholdFeet,
+		fromTimeStamp.Unix(),
+		toTimeStamp.Unix(),
+	)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+	defer rows.Close()
+
+	result := make([]app.FlightData, 0)
+	for rows.Next() {
+		var flight app.FlightData
+		var timeStamp int64
+		if errScan := rows.Scan(&flight.FlightID, &flight.ICAO24BITADDRESS, &flight.Lat, &flight.Lon, &flight.Track, &flight.Altitude, &flight.GroundSpeed, &flight.Unknown1, &flight.TranspondeurType, &flight.AircraftType, &flight.Immatriculation1, &timeStamp, &flight.Origine, &flight.Destination, &flight.Unknown2, &flight.VerticalSpeed, &flight.Immatriculation2, &flight.Hint, &flight.Company); errScan != nil {
+			return nil, errScan
+		}
+		flight.TimeStamp = float64(timeStamp)
+
+		for _, polygon := range polygons {
+			if polygon.Contains(flight.Lat, flight.Lon) {
+				result = append(result, flight)
+				break
+			}
+		}
+	}
+
+	errRow := rows.Err()
+	if errRow != nil {
+		return nil, errRow
+	}
+
+	return result, nil
+}
+
+//connection - the db connection, opened by the first search
+func (s *SQLiteService) connection(ctx context.Context, params interface{}) (*sql.DB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		s.Log.WithContext(ctx).Info("SQLite search service - init DB")
+		db, errOpen := sqlite.Open(params.(sqlite.Configuration))
+		if errOpen != nil {
+			return nil, errOpen
+		}
+		s.db = db
+	}
+	return s.db, nil
+}
+
+//Close - close the db connection, if any
+func (s *SQLiteService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db != nil {
+		s.db.Close()
+		s.db = nil
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers/sqlite"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+func TestSQLiteService(t *testing.T) {
+	ctx := context.Background()
+	conf := sqlite.Configuration{Path: filepath.Join(t.TempDir(), "flighttracker.db")}
+	at := time.Date(2021, 07, 22, 10, 00, 00, 0, time.UTC)
+
+	engine, _ := rules.New(rules.Configuration{})
+	sinker := sqlite.New(log, engine)
+	if errInit := sinker.Init(ctx, conf); errInit != nil {
+		t.Fatal(errInit)
+	}
+	errSink := sinker.Sink(ctx, at, []app.FlightData{
+		{FlightID: "inside", Lat: 43.60, Lon: 1.44, Altitude: 400, TimeStamp: float64(at.Unix())},
+		{FlightID: "high", Lat: 43.60, Lon: 1.44, Altitude: 3000, TimeStamp: float64(at.Unix())},
+		{FlightID: "late", Lat: 43.60, Lon: 1.44, Altitude: 400, TimeStamp: float64(at.Add(5 * time.Hour).Unix())},
+		{FlightID: "corner", Lat: 43.69, Lon: 1.33, Altitude: 400, TimeStamp: float64(at.Unix())},
+		{FlightID: "outside", Lat: 48.85, Lon: 2.35, Altitude: 400, TimeStamp: float64(at.Unix())},
+	})
+	if errSink != nil {
+		t.Fatal(errSink)
+	}
+
+	bbox := tools.Bbox{LatSW: 43.52, LonSW: 1.32, LatNE: 43.70, LonNE: 1.69}
+	from, to := at.Add(-time.Hour), at.Add(time.Hour)
+	searchSvc := NewSQLite(log)
+
+	data, errSearch := searchSvc.Search(ctx, conf, bbox, 500, from, to)
+	if errSearch != nil {
+		t.Fatal(errSearch)
+	}
+	if len(data) != 2 || data[0].FlightID != "inside" || data[1].FlightID != "corner" {
+		t.Errorf("expected inside and corner flights in the bbox, in time then insertion order, got %v", data)
+	}
+
+	//bbox with its north west corner cut
+	area := "POLYGON((1.32 43.52, 1.69 43.52, 1.69 43.70, 1.40 43.70, 1.32 43.62, 1.32 43.52))"
+	data, errSearch = searchSvc.SearchArea(ctx, conf, area, 500, from, to)
+	if errSearch != nil {
+		t.Fatal(errSearch)
+	}
+	if len(data) != 1 || data[0].FlightID != "inside" || data[0].TimeStamp != float64(at.Unix()) {
+		t.Errorf("expected inside flight in the area, got %v", data)
+	}
+
+	//area without polygon
+	if _, errSearch = searchSvc.SearchArea(ctx, conf, "MULTIPOLYGON()", 500, from, to); errSearch == nil {
+		t.Error("expected an error for an area without polygon")
+	}
+}
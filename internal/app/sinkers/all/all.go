@@ -0,0 +1,13 @@
+// Package all registers the sinkers, add the import of a new sinker package here
+package all
+
+import (
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/bus"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/db"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/email"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/file"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/mqtt"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/sqlite"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/stdout"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/webhook"
+)
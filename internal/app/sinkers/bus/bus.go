@@ -0,0 +1,164 @@
+package bus
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
+	"github.com/nats-io/nats.go"
+	"github.com/sirupsen/logrus"
+)
+
+const flushtimeout = 10 * time.Second
+
+func init() {
+	sinkers.Register("NATS", "nats", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
+}
+
+//NatsSinker - publish the flights and the violations on a NATS subject keyed by ICAO address
+type NatsSinker struct {
+	Log        *logrus.Logger
+	rules      *rules.Engine
+	conn       *nats.Conn
+	closed     chan struct{}
+	subject    string
+	encoding   string
+	violations bool
+}
+
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *NatsSinker {
+	//init the logger here
+	return &NatsSinker{Log: log, rules: rulesEngine}
+}
+
+func (s *NatsSinker) Init(ctx context.Context, parameters Configuration) error {
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Url":      parameters.Url,
+		"Subject":  parameters.Subject,
+		"Encoding": parameters.Encoding,
+	}).Info("Initialisation NATS sinker Parameters")
+
+	if err := checkEncoding(parameters.Encoding); err != nil {
+		return err
+	}
+	s.subject = parameters.Subject
+	s.encoding = parameters.Encoding
+	s.violations = parameters.Violations
+
+	//the client reconnects by itself, the messages are buffered meanwhile
+	s.closed = make(chan struct{})
+	conn, err := nats.Connect(parameters.Url,
+		nats.Name("flighttracker"),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			s.Log.WithFields(logrus.Fields{"Warning": err}).Warning("NATS disconnected")
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			s.Log.WithFields(logrus.Fields{"Url": conn.ConnectedUrl()}).Info("NATS reconnected")
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			close(s.closed)
+		}))
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+
+	return nil
+}
+
+// Sink publishes a message by flight and by violation, then waits for the server to receive them
+func (s *NatsSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	for _, flight := range data {
+		payload, err := encodeFlight(s.encoding, flight)
+		if err != nil {
+			return err
+		}
+		if err = s.publish(s.subject+".flights", flight.ICAO24BITADDRESS, payload); err != nil {
+			return err
+		}
+	}
+
+	nbViolation := 0
+	if s.violations && s.rules != nil {
+		for _, violation := range s.rules.Violations(data) {
+			payload, err := encodeViolation(s.encoding, violation)
+			if err != nil {
+				return err
+			}
+			if err = s.publish(s.subject+".violations", violation.Flight.ICAO24BITADDRESS, payload); err != nil {
+				return err
+			}
+			nbViolation++
+		}
+	}
+
+	if err := s.flush(ctx); err != nil {
+		return err
+	}
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"flights":    len(data),
+		"violations": nbViolation,
+	}).Info("Publish on NATS ...")
+
+	return nil
+}
+
+// Close drains the connection: the buffered messages are sent before it is closed
+func (s *NatsSinker) Close() {
+	if s.conn == nil {
+		return
+	}
+	if err := s.conn.Drain(); err != nil {
+		s.Log.WithFields(logrus.Fields{"Warning": err}).Warning("Unable to drain the NATS connection")
+		s.conn.Close()
+		return
+	}
+	select {
+	case <-s.closed:
+	case <-time.After(flushtimeout):
+		s.Log.Warning("NATS connection not drained, closed")
+		s.conn.Close()
+	}
+}
+
+// flush waits for the server to receive the published messages, until the ctx deadline if any
+func (s *NatsSinker) flush(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); ok {
+		return s.conn.FlushWithContext(ctx)
+	}
+	return s.conn.FlushTimeout(flushtimeout)
+}
+
+// publish the payload on <prefix>.<ICAO>, the ICAO address is also given in the Key header
+func (s *NatsSinker) publish(prefix, icao string, payload []byte) error {
+	msg := nats.NewMsg(prefix + "." + subjectToken(icao))
+	msg.Header.Set("Key", icao)
+	msg.Header.Set("Content-Type", contentTypes[s.encoding])
+	msg.Data = payload
+	return s.conn.PublishMsg(msg)
+}
+
+// subjectToken - the ICAO address as a NATS subject token (no dot, wildcard nor space)
+func subjectToken(icao string) string {
+	token := strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t':
+			return '_'
+		}
+		return r
+	}, strings.ToUpper(icao))
+	if token == "" {
+		return "UNKNOWN"
+	}
+	return token
+}
@@ -0,0 +1,210 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var log *logrus.Logger
+
+var flights = []app.FlightData{
+	//low flight, violation of the default rule
+	{FlightID: "2b1e4c51", ICAO24BITADDRESS: "39c4b1", Lat: 43.6, Lon: 1.44, Altitude: 800, GroundSpeed: 90, TimeStamp: 1627030800, Hint: "FGXYZ"},
+	{FlightID: "2b1e4c52", ICAO24BITADDRESS: "3944ef", Lat: 43.7, Lon: 1.38, Altitude: 12000, GroundSpeed: 320, TimeStamp: 1627030801, Hint: "AFR61FK"},
+}
+
+// runServer - embedded NATS server on a random port
+func runServer(t *testing.T) *server.Server {
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// publish the test flights and return the received messages
+func publish(t *testing.T, encoding string) []*nats.Msg {
+	ctx := context.Background()
+	srv := runServer(t)
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	sub, err := conn.SubscribeSync("flighttracker.>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Flush()
+
+	rulesEngine, err := rules.New(rules.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sinker := New(log, rulesEngine)
+	err = sinker.Init(ctx, Configuration{Url: srv.ClientURL(), Subject: "flighttracker", Encoding: encoding, Violations: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = sinker.Sink(ctx, time.Now(), flights); err != nil {
+		t.Fatal(err)
+	}
+
+	var result []*nats.Msg
+	for i := 0; i < len(flights)+1; i++ {
+		msg, err := sub.NextMsg(2 * time.Second)
+		if err != nil {
+			t.Fatalf("message %d: %v", i, err)
+		}
+		result = append(result, msg)
+	}
+	if msg, err := sub.NextMsg(100 * time.Millisecond); err == nil {
+		t.Errorf("unexpected message on %s", msg.Subject)
+	}
+
+	var _ app.Closer = sinker
+	sinker.Close()
+	if !sinker.conn.IsClosed() {
+		t.Error("expected the NATS connection closed")
+	}
+	return result
+}
+
+func TestCloseDrains(t *testing.T) {
+	ctx := context.Background()
+	srv := runServer(t)
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	sub, err := conn.SubscribeSync("flighttracker.>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Flush()
+
+	sinker := New(log, nil)
+	err = sinker.Init(ctx, Configuration{Url: srv.ClientURL(), Subject: "flighttracker", Encoding: EncodingJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//published without flush, sent by the drain
+	if err = sinker.publish("flighttracker.flights", "39c4b1", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	sinker.Close()
+
+	if _, err := sub.NextMsg(2 * time.Second); err != nil {
+		t.Errorf("buffered message not sent by Close: %v", err)
+	}
+
+	//closing a sinker not initialized does nothing
+	New(log, nil).Close()
+}
+
+func TestSinkJSON(t *testing.T) {
+	msgs := publish(t, EncodingJSON)
+
+	expected := []string{"flighttracker.flights.39C4B1", "flighttracker.flights.3944EF", "flighttracker.violations.39C4B1"}
+	for idx, msg := range msgs {
+		if msg.Subject != expected[idx] {
+			t.Errorf("message %d: expected subject %s, got %s", idx, expected[idx], msg.Subject)
+		}
+	}
+	if key := msgs[0].Header.Get("Key"); key != "39c4b1" {
+		t.Errorf("expected key 39c4b1, got %s", key)
+	}
+
+	var flight app.FlightData
+	if err := json.Unmarshal(msgs[1].Data, &flight); err != nil {
+		t.Fatal(err)
+	}
+	if flight.Hint != "AFR61FK" || flight.Altitude != 12000 {
+		t.Errorf("unexpected flight %+v", flight)
+	}
+
+	var violation rules.Violation
+	if err := json.Unmarshal(msgs[2].Data, &violation); err != nil {
+		t.Fatal(err)
+	}
+	if violation.RuleID != rules.DefaultRule.ID || violation.Flight.FlightID != "2b1e4c51" {
+		t.Errorf("unexpected violation %+v", violation)
+	}
+}
+
+func TestSinkProtobuf(t *testing.T) {
+	msgs := publish(t, EncodingProtobuf)
+
+	if contentType := msgs[0].Header.Get("Content-Type"); contentType != "application/x-protobuf" {
+		t.Errorf("unexpected content type %s", contentType)
+	}
+
+	fields := decode(t, msgs[0].Data)
+	if string(fields[2]) != "39c4b1" || string(fields[18]) != "FGXYZ" {
+		t.Errorf("unexpected flight fields %v", fields)
+	}
+	if altitude, _ := protowire.ConsumeVarint(fields[6]); int64(altitude) != 800 {
+		t.Errorf("expected altitude 800, got %d", altitude)
+	}
+	if lat, _ := protowire.ConsumeFixed64(fields[3]); math.Float64frombits(lat) != 43.6 {
+		t.Errorf("expected lat 43.6, got %v", math.Float64frombits(lat))
+	}
+
+	violation := decode(t, msgs[2].Data)
+	if string(violation[1]) != rules.DefaultRule.ID || string(decode(t, violation[3])[1]) != "2b1e4c51" {
+		t.Errorf("unexpected violation fields %v", violation)
+	}
+}
+
+// decode the fields of a message by number, the raw value for varint and fixed64, the content for bytes
+func decode(t *testing.T, b []byte) map[protowire.Number][]byte {
+	result := map[protowire.Number][]byte{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		b = b[n:]
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		value := b[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		result[num] = value
+		b = b[n:]
+	}
+	return result
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.WarnLevel
+	log.Out = os.Stdout
+}
@@ -0,0 +1,9 @@
+package bus
+
+// Configuration settings for NATS message bus sinking
+type Configuration struct {
+	Url        string `toml:"url" default:"nats://127.0.0.1:4222" comment:"NATS server url"`
+	Subject    string `toml:"subject" default:"flighttracker" comment:"subject prefix, flights are published on <subject>.flights.<ICAO> and violations on <subject>.violations.<ICAO>"`
+	Encoding   string `toml:"encoding" default:"json" comment:"message encoding (json|protobuf), see flight.proto"`
+	Violations bool   `toml:"violations" default:"true" comment:"publish the violations of the illegal flight rules"`
+}
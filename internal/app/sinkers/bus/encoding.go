@@ -0,0 +1,100 @@
+package bus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message encodings
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+var contentTypes = map[string]string{
+	EncodingJSON:     "application/json",
+	EncodingProtobuf: "application/x-protobuf",
+}
+
+func checkEncoding(encoding string) error {
+	if _, ok := contentTypes[encoding]; !ok {
+		return fmt.Errorf("encoding %s unknown - need %s or %s", encoding, EncodingJSON, EncodingProtobuf)
+	}
+	return nil
+}
+
+func encodeFlight(encoding string, flight app.FlightData) ([]byte, error) {
+	if encoding == EncodingProtobuf {
+		return appendFlight(nil, flight), nil
+	}
+	return json.Marshal(flight)
+}
+
+func encodeViolation(encoding string, violation rules.Violation) ([]byte, error) {
+	if encoding == EncodingProtobuf {
+		var b []byte
+		b = appendString(b, 1, violation.RuleID)
+		b = appendString(b, 2, violation.Reason)
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendFlight(nil, violation.Flight))
+		return b, nil
+	}
+	return json.Marshal(violation)
+}
+
+// appendFlight - Flight message of flight.proto, the zero values are omitted like proto3 does
+func appendFlight(b []byte, flight app.FlightData) []byte {
+	b = appendString(b, 1, flight.FlightID)
+	b = appendString(b, 2, flight.ICAO24BITADDRESS)
+	b = appendDouble(b, 3, flight.Lat)
+	b = appendDouble(b, 4, flight.Lon)
+	b = appendInt64(b, 5, flight.Track)
+	b = appendInt64(b, 6, flight.Altitude)
+	b = appendInt64(b, 7, flight.GroundSpeed)
+	b = appendString(b, 8, flight.Unknown1)
+	b = appendString(b, 9, flight.TranspondeurType)
+	b = appendString(b, 10, flight.AircraftType)
+	b = appendString(b, 11, flight.Immatriculation1)
+	b = appendDouble(b, 12, flight.TimeStamp)
+	b = appendString(b, 13, flight.Origine)
+	b = appendString(b, 14, flight.Destination)
+	b = appendString(b, 15, flight.Unknown2)
+	b = appendInt64(b, 16, flight.VerticalSpeed)
+	b = appendString(b, 17, flight.Immatriculation2)
+	b = appendString(b, 18, flight.Hint)
+	b = appendString(b, 19, flight.Company)
+	for _, zone := range flight.Zones {
+		b = protowire.AppendTag(b, 20, protowire.BytesType)
+		b = protowire.AppendString(b, zone)
+	}
+	return b
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func appendInt64(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
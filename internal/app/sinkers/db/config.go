@@ -2,9 +2,13 @@ package db
 
 // Configuration settings for Postgres DB sinking
 type Configuration struct {
-	Host     string `toml:"host" default:"172.17.0.2" comment:"Postgres host"`
-	Port     int    `toml:"port" default:"5432" comment:"Postgres port"`
-	User     string `toml:"user" default:"postgres" comment:"Postgres user"`
-	Password string `toml:"password" default:"mysecretpassword" comment:"Postgres password"`
-	Dbname   string `toml:"dbName" default:"postgres" comment:"Postgres dbName"`
+	Host          string `toml:"host" default:"172.17.0.2" comment:"Postgres host"`
+	Port          int    `toml:"port" default:"5432" comment:"Postgres port"`
+	User          string `toml:"user" default:"postgres" comment:"Postgres user"`
+	Password      string `toml:"password" default:"mysecretpassword" comment:"Postgres password"`
+	Dbname        string `toml:"dbName" default:"postgres" comment:"Postgres dbName"`
+	Unique        bool   `toml:"unique" default:"false" comment:"create a unique index on (FlightID, TimeStamp), the duplicated positions are ignored by the database"`
+	Partitioning  string `toml:"partitioning" default:"" comment:"range partition the flight table by TimeStamp (daily|monthly), not partitioned if empty"`
+	Retention     int    `toml:"retention" default:"0" comment:"days of positions kept when partitioned, the older partitions are removed (0 keeps everything)"`
+	Retentionmode string `toml:"retentionMode" default:"drop" comment:"drop the old partitions or detach them as standalone tables to archive (drop|detach)"`
 }
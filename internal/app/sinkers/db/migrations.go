@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	versiontablename = "schema_version"
+	//advisory lock key serializing the migrations of several instances
+	migrationlock = 7438117
+)
+
+//migration - a schema version, its statements then its run function are applied in a single transaction
+// an optional migration is only applied once its configuration option is enabled
+type migration struct {
+	version     int
+	description string
+	statements  []string
+	run         func(ctx context.Context, tx *sql.Tx) error
+	option      string
+	enabled     func(parameters Configuration) bool
+}
+
+// migrations of the flighttracker schema, never modify an applied migration: append a new version
+// the first versions use IF NOT EXISTS to adopt the databases created before the versioning
+var migrations = []migration{
+	{
+		version:     1,
+		description: "flight table",
+		statements: []string{
+			"CREATE TABLE IF NOT EXISTS " + schemaname + "." + tablename + " (FlightID varchar(40) NOT NULL, ICAO24BITADDRESS varchar(40), Lat decimal, Lon decimal, Track integer, Altitude integer, GroundSpeed integer, Unknown1 varchar(40), TranspondeurType varchar(40), AircraftType varchar(40), Immatriculation1 varchar(40), TimeStamp timestamp, Origine varchar(40), Destination varchar(40), Unknown2 varchar(40), VerticalSpeed integer, Immatriculation2 varchar(40), Hint varchar(40), Company varchar(40), geom geometry(Geometry,4326))",
+		},
+	},
+	{
+		version:     2,
+		description: "track table",
+		statements: []string{
+			"CREATE TABLE IF NOT EXISTS " + schemaname + "." + tracktablename + " (FlightID varchar(40) NOT NULL, ICAO24BITADDRESS varchar(40), StartTime timestamp, EndTime timestamp, MinAltitude integer, MaxGroundSpeed integer, NbPosition integer, AircraftType varchar(40), Immatriculation1 varchar(40), geom geometry(Geometry,4326))",
+		},
+	},
+	{
+		version:     3,
+		description: "flight indexes on timestamp, ICAO address and geometry",
+		statements: []string{
+			"CREATE INDEX IF NOT EXISTS flight_timestamp_idx ON " + schemaname + "." + tablename + " (TimeStamp)",
+			"CREATE INDEX IF NOT EXISTS flight_icao24bitaddress_idx ON " + schemaname + "." + tablename + " (ICAO24BITADDRESS)",
+			"CREATE INDEX IF NOT EXISTS flight_geom_idx ON " + schemaname + "." + tablename + " USING GIST (geom)",
+		},
+	},
+	{
+		version:     4,
+		description: "violation table of the illegal flight rules",
+		statements: []string{
+			"CREATE TABLE IF NOT EXISTS " + schemaname + "." + violationtablename + " (FlightID varchar(40) NOT NULL, ICAO24BITADDRESS varchar(40), TimeStamp timestamp, RuleID varchar(100), Reason varchar(400), Altitude integer, GroundSpeed integer, geom geometry(Geometry,4326))",
+			"CREATE INDEX IF NOT EXISTS violation_timestamp_idx ON " + schemaname + "." + violationtablename + " (TimeStamp)",
+		},
+	},
+	{
+		version:     5,
+		description: "unique position index on (FlightID, TimeStamp)",
+		statements: []string{
+			"CREATE UNIQUE INDEX IF NOT EXISTS " + uniqueindex + " ON " + schemaname + "." + tablename + " (FlightID, TimeStamp)",
+		},
+		option:  "unique",
+		enabled: func(parameters Configuration) bool { return parameters.Unique },
+	},
+	{
+		version:     6,
+		description: "flight table partitioned by TimeStamp, existing positions kept in a default partition",
+		run:         partitionFlight,
+		option:      "partitioning",
+		enabled:     func(parameters Configuration) bool { return parameters.Partitioning != "" },
+	},
+}
+
+//MigrationStatus - a schema version and when it was applied (zero time when pending)
+// Option is the configuration option of an optional migration, Enabled tells if it is applied by migrate
+type MigrationStatus struct {
+	Version     int
+	Description string
+	Option      string
+	Enabled     bool
+	AppliedAt   time.Time
+}
+
+// Open the connection to the database
+func Open(parameters Configuration) (*sql.DB, error) {
+	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s "+
+		"password=%s dbname=%s sslmode=disable",
+		parameters.Host, parameters.Port, parameters.User, parameters.Password, parameters.Dbname)
+
+	db, err := sql.Open("postgres", psqlInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Migrate applies the pending migrations in order and returns the applied versions
+// the optional migrations whose option is disabled are skipped
+func Migrate(ctx context.Context, db *sql.DB, parameters Configuration) ([]int, error) {
+	if err := createVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	if parameters.Partitioning != "" {
+		if err := checkPartitioning(parameters); err != nil {
+			return nil, err
+		}
+	}
+
+	var applied []int
+	for _, m := range migrations {
+		if !m.isEnabled(parameters) {
+			continue
+		}
+		done, err := apply(ctx, db, m)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %v", m.version, m.description, err)
+		}
+		if done {
+			applied = append(applied, m.version)
+		}
+	}
+	return applied, nil
+}
+
+// Status lists the known migrations with their applied time
+func Status(ctx context.Context, db *sql.DB, parameters Configuration) ([]MigrationStatus, error) {
+	if err := createVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version, applied_at FROM "+schemaname+"."+versiontablename)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appliedAt := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		result = append(result, MigrationStatus{Version: m.version, Description: m.description, Option: m.option, Enabled: m.isEnabled(parameters), AppliedAt: appliedAt[m.version]})
+	}
+	return result, nil
+}
+
+func (m migration) isEnabled(parameters Configuration) bool {
+	return m.enabled == nil || m.enabled(parameters)
+}
+
+func createVersionTable(ctx context.Context, db *sql.DB) error {
+	//TODO: reduce SQL injection
+	_, err := db.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+schemaname)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+schemaname+"."+versiontablename+" (version integer PRIMARY KEY, description varchar(200), applied_at timestamp NOT NULL DEFAULT now())")
+	return err
+}
+
+// apply the migration if not already done, the advisory lock makes the check and the statements atomic between instances
+func apply(ctx context.Context, db *sql.DB, m migration) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationlock); err != nil {
+		return false, err
+	}
+
+	var done bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+schemaname+"."+versiontablename+" WHERE version = $1)", m.version).Scan(&done)
+	if err != nil || done {
+		return false, err
+	}
+
+	for _, statement := range m.statements {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			return false, err
+		}
+	}
+	if m.run != nil {
+		if err = m.run(ctx, tx); err != nil {
+			return false, err
+		}
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO "+schemaname+"."+versiontablename+" (version, description) VALUES ($1, $2)", m.version, m.description)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// partitionFlight converts the flight table to a partitioned one, the existing positions are kept in a default partition
+// the table is kept as is if it is already partitioned (databases partitioned before the versioning)
+func partitionFlight(ctx context.Context, tx *sql.Tx) error {
+	var kind string
+	err := tx.QueryRowContext(ctx, "SELECT c.relkind FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relname = $2", schemaname, tablename).Scan(&kind)
+	if err != nil {
+		return err
+	}
+	if kind == "p" {
+		return nil
+	}
+
+	legacyuniqueindex := strings.Replace(uniqueindex, tablename, legacytablename, 1)
+	statements := []string{
+		"ALTER TABLE " + schemaname + "." + tablename + " RENAME TO " + legacytablename,
+		"ALTER INDEX IF EXISTS " + schemaname + "." + uniqueindex + " RENAME TO " + legacyuniqueindex,
+		"CREATE TABLE " + schemaname + "." + tablename + " (LIKE " + schemaname + "." + legacytablename + " INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY RANGE (TimeStamp)",
+	}
+	for index, definition := range flightindexes {
+		statements = append(statements,
+			"ALTER INDEX IF EXISTS "+schemaname+"."+index+" RENAME TO "+strings.Replace(index, tablename, legacytablename, 1),
+			"CREATE INDEX "+index+" ON "+schemaname+"."+tablename+" "+definition)
+	}
+	for _, statement := range statements {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	// the unique index of the migration 5 is recreated on the partitioned table
+	var unique bool
+	err = tx.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", schemaname+"."+legacyuniqueindex).Scan(&unique)
+	if err != nil {
+		return err
+	}
+	if unique {
+		_, err = tx.ExecContext(ctx, "CREATE UNIQUE INDEX "+uniqueindex+" ON "+schemaname+"."+tablename+" (FlightID, TimeStamp)")
+		if err != nil {
+			return err
+		}
+	}
+
+	var legacyRows bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+schemaname+"."+legacytablename+")").Scan(&legacyRows)
+	if err != nil {
+		return err
+	}
+	if legacyRows {
+		_, err = tx.ExecContext(ctx, "ALTER TABLE "+schemaname+"."+tablename+" ATTACH PARTITION "+schemaname+"."+legacytablename+" DEFAULT")
+	} else {
+		_, err = tx.ExecContext(ctx, "DROP TABLE "+schemaname+"."+legacytablename)
+	}
+	return err
+}
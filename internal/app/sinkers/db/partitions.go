@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// partitioning periods
+const (
+	PartitionDaily   = "daily"
+	PartitionMonthly = "monthly"
+)
+
+// retention modes
+const (
+	RetentionDrop   = "drop"
+	RetentionDetach = "detach"
+)
+
+const (
+	partitionprefix     = tablename + "_p"
+	legacytablename     = tablename + "_legacy"
+	retentioninterval   = time.Hour
+	retryinterval       = time.Hour
+	dailypartitionfmt   = "20060102"
+	monthlypartitionfmt = "200601"
+)
+
+// flight indexes renamed with the legacy table when it is converted, recreated on the partitioned table
+var flightindexes = map[string]string{
+	"flight_timestamp_idx":        "(TimeStamp)",
+	"flight_icao24bitaddress_idx": "(ICAO24BITADDRESS)",
+	"flight_geom_idx":             "USING GIST (geom)",
+}
+
+// partitioner - create the flight partitions on demand and apply the retention
+type partitioner struct {
+	log           *logrus.Logger
+	db            *sql.DB
+	period        string
+	retention     int
+	retentionMode string
+	known         map[time.Time]bool
+	failed        map[time.Time]time.Time //last failed creation by period start, i.e. overlapping the legacy default partition
+	lastRetention time.Time
+}
+
+// Partition creates the current partitions of the flight table, converted to a partitioned one by the migration 6
+func Partition(ctx context.Context, log *logrus.Logger, db *sql.DB, parameters Configuration) error {
+	_, err := partition(ctx, log, db, parameters)
+	return err
+}
+
+func partition(ctx context.Context, log *logrus.Logger, db *sql.DB, parameters Configuration) (*partitioner, error) {
+	p, err := newPartitioner(log, db, parameters)
+	if err != nil {
+		return nil, err
+	}
+	p.ensure(ctx, []time.Time{time.Now()})
+	return p, nil
+}
+
+func newPartitioner(log *logrus.Logger, db *sql.DB, parameters Configuration) (*partitioner, error) {
+	p := &partitioner{
+		log:           log,
+		db:            db,
+		period:        parameters.Partitioning,
+		retention:     parameters.Retention,
+		retentionMode: parameters.Retentionmode,
+		known:         map[time.Time]bool{},
+		failed:        map[time.Time]time.Time{},
+	}
+	if err := checkPartitioning(parameters); err != nil {
+		return nil, err
+	}
+	if p.retentionMode == "" {
+		p.retentionMode = RetentionDrop
+	}
+	return p, nil
+}
+
+// checkPartitioning validates the partitioning parameters, before the flight table is converted
+func checkPartitioning(parameters Configuration) error {
+	if parameters.Partitioning != PartitionDaily && parameters.Partitioning != PartitionMonthly {
+		return fmt.Errorf("partitioning %s unknown - need %s or %s", parameters.Partitioning, PartitionDaily, PartitionMonthly)
+	}
+	if parameters.Retentionmode != "" && parameters.Retentionmode != RetentionDrop && parameters.Retentionmode != RetentionDetach {
+		return fmt.Errorf("retention mode %s unknown - need %s or %s", parameters.Retentionmode, RetentionDrop, RetentionDetach)
+	}
+	return nil
+}
+
+// start of the period containing t, the TimeStamp column is stored without time zone like the inserted times
+func (p *partitioner) start(t time.Time) time.Time {
+	if p.period == PartitionMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (p *partitioner) next(start time.Time) time.Time {
+	if p.period == PartitionMonthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func (p *partitioner) name(start time.Time) string {
+	if p.period == PartitionMonthly {
+		return partitionprefix + start.Format(monthlypartitionfmt)
+	}
+	return partitionprefix + start.Format(dailypartitionfmt)
+}
+
+// periods - the starts of the periods of the times and of the next ones, without duplicates
+func (p *partitioner) periods(times []time.Time) []time.Time {
+	var starts []time.Time
+	seen := map[time.Time]bool{}
+	for _, t := range times {
+		start := p.start(t)
+		for _, s := range []time.Time{start, p.next(start)} {
+			if !seen[s] {
+				seen[s] = true
+				starts = append(starts, s)
+			}
+		}
+	}
+	return starts
+}
+
+// ensure the partitions of the times exist, and the one of the next period
+// a partition overlapping positions of the legacy default partition can't be created, they keep going there
+// and its creation is retried at most once an hour
+func (p *partitioner) ensure(ctx context.Context, times []time.Time) {
+	now := time.Now()
+	for _, s := range p.periods(times) {
+		if p.known[s] || now.Sub(p.failed[s]) < retryinterval {
+			continue
+		}
+
+		createSQL := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s PARTITION OF %s.%s FOR VALUES FROM ('%s') TO ('%s')",
+			schemaname, p.name(s), schemaname, tablename, s.Format("2006-01-02"), p.next(s).Format("2006-01-02"))
+		p.log.WithContext(ctx).WithFields(logrus.Fields{
+			"SQL": createSQL,
+		}).Info("create partition")
+		if _, err := p.db.ExecContext(ctx, createSQL); err != nil {
+			p.log.WithContext(ctx).WithFields(logrus.Fields{
+				"Warning":   err,
+				"partition": p.name(s),
+			}).Warning("Unable to create partition")
+			p.failed[s] = now
+			continue
+		}
+		p.known[s] = true
+		delete(p.failed, s)
+	}
+}
+
+// applyRetention drops or detaches the partitions ended for more than retention days, at most once an hour
+func (p *partitioner) applyRetention(ctx context.Context, now time.Time) error {
+	if p.retention <= 0 || now.Sub(p.lastRetention) < retentioninterval {
+		return nil
+	}
+	p.lastRetention = now
+	limit := now.AddDate(0, 0, -p.retention)
+
+	rows, err := p.db.QueryContext(ctx, "SELECT c.relname FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid JOIN pg_class parent ON parent.oid = i.inhparent JOIN pg_namespace n ON n.oid = parent.relnamespace WHERE n.nspname = $1 AND parent.relname = $2", schemaname, tablename)
+	if err != nil {
+		return err
+	}
+	var expired []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		end, ok := p.end(name)
+		if ok && !end.After(limit) {
+			expired = append(expired, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range expired {
+		retentionSQL := "DROP TABLE " + schemaname + "." + name
+		if p.retentionMode == RetentionDetach {
+			retentionSQL = "ALTER TABLE " + schemaname + "." + tablename + " DETACH PARTITION " + schemaname + "." + name
+		}
+		p.log.WithContext(ctx).WithFields(logrus.Fields{
+			"SQL": retentionSQL,
+		}).Info("retention")
+		if _, err := p.db.ExecContext(ctx, retentionSQL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// end of a partition from its name, false for the partitions not created by the partitioner
+func (p *partitioner) end(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, partitionprefix) {
+		return time.Time{}, false
+	}
+	suffix := strings.TrimPrefix(name, partitionprefix)
+	if start, err := time.ParseInLocation(dailypartitionfmt, suffix, time.Local); err == nil && len(suffix) == len(dailypartitionfmt) {
+		return start.AddDate(0, 0, 1), true
+	}
+	if start, err := time.ParseInLocation(monthlypartitionfmt, suffix, time.Local); err == nil && len(suffix) == len(monthlypartitionfmt) {
+		return start.AddDate(0, 1, 0), true
+	}
+	return time.Time{}, false
+}
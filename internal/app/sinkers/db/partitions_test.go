@@ -0,0 +1,120 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionPeriod(t *testing.T) {
+	tests := []struct {
+		name      string
+		period    string
+		t         time.Time
+		start     time.Time
+		next      time.Time
+		partition string
+	}{
+		{"daily", PartitionDaily, time.Date(2021, 7, 22, 14, 30, 0, 0, time.Local), time.Date(2021, 7, 22, 0, 0, 0, 0, time.Local), time.Date(2021, 7, 23, 0, 0, 0, 0, time.Local), "flight_p20210722"},
+		{"daily midnight", PartitionDaily, time.Date(2021, 7, 22, 0, 0, 0, 0, time.Local), time.Date(2021, 7, 22, 0, 0, 0, 0, time.Local), time.Date(2021, 7, 23, 0, 0, 0, 0, time.Local), "flight_p20210722"},
+		{"daily end of month", PartitionDaily, time.Date(2021, 7, 31, 23, 59, 59, 0, time.Local), time.Date(2021, 7, 31, 0, 0, 0, 0, time.Local), time.Date(2021, 8, 1, 0, 0, 0, 0, time.Local), "flight_p20210731"},
+		{"daily leap day", PartitionDaily, time.Date(2024, 2, 29, 12, 0, 0, 0, time.Local), time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local), time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local), "flight_p20240229"},
+		{"monthly", PartitionMonthly, time.Date(2021, 7, 22, 14, 30, 0, 0, time.Local), time.Date(2021, 7, 1, 0, 0, 0, 0, time.Local), time.Date(2021, 8, 1, 0, 0, 0, 0, time.Local), "flight_p202107"},
+		{"monthly end of year", PartitionMonthly, time.Date(2021, 12, 31, 23, 0, 0, 0, time.Local), time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local), time.Date(2022, 1, 1, 0, 0, 0, 0, time.Local), "flight_p202112"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &partitioner{period: tt.period}
+			start := p.start(tt.t)
+			if !start.Equal(tt.start) {
+				t.Errorf("start %v, expected %v", start, tt.start)
+			}
+			if next := p.next(start); !next.Equal(tt.next) {
+				t.Errorf("next %v, expected %v", next, tt.next)
+			}
+			if name := p.name(start); name != tt.partition {
+				t.Errorf("name %s, expected %s", name, tt.partition)
+			}
+		})
+	}
+}
+
+func TestPartitionPeriods(t *testing.T) {
+	p := &partitioner{period: PartitionDaily}
+	times := []time.Time{
+		time.Date(2021, 7, 22, 14, 30, 0, 0, time.Local),
+		time.Date(2021, 7, 22, 14, 31, 0, 0, time.Local),
+		time.Date(2021, 7, 23, 0, 0, 0, 0, time.Local),
+	}
+	expected := []time.Time{
+		time.Date(2021, 7, 22, 0, 0, 0, 0, time.Local),
+		time.Date(2021, 7, 23, 0, 0, 0, 0, time.Local),
+		time.Date(2021, 7, 24, 0, 0, 0, 0, time.Local),
+	}
+
+	periods := p.periods(times)
+	if len(periods) != len(expected) {
+		t.Fatalf("periods %v, expected %v", periods, expected)
+	}
+	for idx := range expected {
+		if !periods[idx].Equal(expected[idx]) {
+			t.Errorf("period %d %v, expected %v", idx, periods[idx], expected[idx])
+		}
+	}
+}
+
+func TestPartitionEnd(t *testing.T) {
+	tests := []struct {
+		name string
+		end  time.Time
+		ok   bool
+	}{
+		{"flight_p20210722", time.Date(2021, 7, 23, 0, 0, 0, 0, time.Local), true},
+		{"flight_p20210731", time.Date(2021, 8, 1, 0, 0, 0, 0, time.Local), true},
+		{"flight_p202107", time.Date(2021, 8, 1, 0, 0, 0, 0, time.Local), true},
+		{"flight_p202112", time.Date(2022, 1, 1, 0, 0, 0, 0, time.Local), true},
+		{"flight_legacy", time.Time{}, false},
+		{"flight_p2021", time.Time{}, false},
+		{"flight_p20211340", time.Time{}, false},
+		{"flight_p2021072", time.Time{}, false},
+		{"track", time.Time{}, false},
+	}
+
+	// the end doesn't depend on the period: a partition keeps its name when the period is changed
+	for _, period := range []string{PartitionDaily, PartitionMonthly} {
+		p := &partitioner{period: period}
+		for _, tt := range tests {
+			end, ok := p.end(tt.name)
+			if ok != tt.ok || !end.Equal(tt.end) {
+				t.Errorf("%s end of %s: %v %v, expected %v %v", period, tt.name, end, ok, tt.end, tt.ok)
+			}
+		}
+	}
+
+	// a partition name ends at the next period start
+	for _, period := range []string{PartitionDaily, PartitionMonthly} {
+		p := &partitioner{period: period}
+		start := p.start(time.Date(2021, 7, 22, 14, 30, 0, 0, time.Local))
+		end, ok := p.end(p.name(start))
+		if !ok || !end.Equal(p.next(start)) {
+			t.Errorf("%s end of %s: %v %v, expected %v", period, p.name(start), end, ok, p.next(start))
+		}
+	}
+}
+
+func TestCheckPartitioning(t *testing.T) {
+	tests := []struct {
+		parameters Configuration
+		valid      bool
+	}{
+		{Configuration{Partitioning: PartitionDaily}, true},
+		{Configuration{Partitioning: PartitionMonthly, Retentionmode: RetentionDetach}, true},
+		{Configuration{Partitioning: "weekly"}, false},
+		{Configuration{Partitioning: PartitionDaily, Retentionmode: "archive"}, false},
+	}
+	for _, tt := range tests {
+		if err := checkPartitioning(tt.parameters); (err == nil) != tt.valid {
+			t.Errorf("check %+v: %v, expected valid %v", tt.parameters, err, tt.valid)
+		}
+	}
+}
@@ -4,44 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
 
 	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	schemaname = "flighttracker"
-	tablename  = "flight"
+	schemaname         = "flighttracker"
+	tablename          = "flight"
+	tracktablename     = "track"
+	violationtablename = "violation"
+	uniqueindex        = "flight_flightid_timestamp_key"
+
+	flightcolumns    = 20
+	violationcolumns = 8
+	//rows by INSERT statement, postgres allows 65535 parameters by statement
+	batchsize = 1000
 )
 
 type PostGreSinker struct {
-	Log *logrus.Logger
-	db  *sql.DB
+	Log        *logrus.Logger
+	rules      *rules.Engine
+	db         *sql.DB
+	partitions *partitioner //nil when the flight table is not partitioned
+}
+
+func init() {
+	sinkers.Register("DB", "postgres", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
 }
 
-func New(log *logrus.Logger) app.Sinker {
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *PostGreSinker {
 	//init the logger here
-	return &PostGreSinker{Log: log}
+	return &PostGreSinker{Log: log, rules: rulesEngine}
 }
 
-func (s *PostGreSinker) Init(ctx context.Context, params interface{}) error {
-	parameters := params.(Configuration)
+func (s *PostGreSinker) Init(ctx context.Context, parameters Configuration) error {
 
 	// Init the connection to the database
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s "+
-		"password=%s dbname=%s sslmode=disable",
-		parameters.Host, parameters.Port, parameters.User, parameters.Password, parameters.Dbname)
-	s.Log.WithContext(ctx).Info("Init DB ... : " + psqlInfo)
-
-	db, err := sql.Open("postgres", psqlInfo)
-	if err != nil {
-		return err
-	}
-
-	err = db.Ping()
+	s.Log.WithContext(ctx).Info("Init DB ... : host=" + parameters.Host + " dbname=" + parameters.Dbname)
+	db, err := Open(parameters)
 	if err != nil {
 		return err
 	}
@@ -51,75 +61,203 @@ func (s *PostGreSinker) Init(ctx context.Context, params interface{}) error {
 	s.db = db
 
 	// create database :
-	// schema
-	//TODO: reduce SQL injection
-	createSchemaSQL := "CREATE SCHEMA IF NOT EXISTS " + schemaname
-	s.Log.WithContext(ctx).WithFields(logrus.Fields{
-		"SQL": createSchemaSQL,
-	}).Info("create shema")
-	_, err = s.db.Exec(createSchemaSQL)
+	// schema, tables and optional unique position index by versioned migrations
+	// the unique index fails if the table already holds duplicated positions
+	applied, err := Migrate(ctx, s.db, parameters)
 	if err != nil {
 		return err
 	}
+	if len(applied) > 0 {
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"versions": applied,
+		}).Info("schema migrated")
+	}
 
 	// create database :
-	// table
-	createTableSQL := "CREATE TABLE IF NOT EXISTS " + schemaname + "." + tablename + " (FlightID varchar(40) NOT NULL, ICAO24BITADDRESS varchar(40), Lat decimal, Lon decimal, Track integer, Altitude integer, GroundSpeed integer, Unknown1 varchar(40), TranspondeurType varchar(40), AircraftType varchar(40), Immatriculation1 varchar(40), TimeStamp timestamp, Origine varchar(40), Destination varchar(40), Unknown2 varchar(40), VerticalSpeed integer, Immatriculation2 varchar(40), Hint varchar(40), Company varchar(40), geom geometry(Geometry,4326))"
-	s.Log.WithContext(ctx).WithFields(logrus.Fields{
-		"SQL": createTableSQL,
-	}).Info("create table")
+	// partitioned flight table
+	if parameters.Partitioning != "" {
+		s.partitions, err = partition(ctx, s.Log, s.db, parameters)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sink writes the tick and its violations of the illegal flight rules in a single transaction with multi-row inserts, all or nothing
+func (s *PostGreSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if s.partitions != nil {
+		times := make([]time.Time, 0, len(data))
+		for _, flight := range data {
+			times = append(times, time.Unix(int64(flight.TimeStamp), 0))
+		}
+		s.partitions.ensure(ctx, times)
+		if err := s.partitions.applyRetention(ctx, t); err != nil {
+			s.Log.WithContext(ctx).WithFields(logrus.Fields{
+				"Warning": err,
+			}).Warning("Unable to apply the retention")
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	nbRow := int64(0)
+	for start := 0; start < len(data); start += batchsize {
+		end := start + batchsize
+		if end > len(data) {
+			end = len(data)
+		}
+		insertSQL, args := insertFlights(data[start:end])
 
-	_, err = s.db.Exec(createTableSQL)
+		result, err := tx.ExecContext(ctx, insertSQL, args...)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		nb, _ := result.RowsAffected()
+		nbRow = nbRow + nb
+	}
+
+	violations := s.rules.Violations(data)
+	for start := 0; start < len(violations); start += batchsize {
+		end := start + batchsize
+		if end > len(violations) {
+			end = len(violations)
+		}
+		insertSQL, args := insertViolations(violations[start:end])
+
+		if _, err := tx.ExecContext(ctx, insertSQL, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{"Rows Affected": nbRow, "Flights": len(data), "Violations": len(violations)}).Info("Insert in DB ...")
 
 	return nil
 }
 
-func (s *PostGreSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+// insertFlights - multi-row INSERT statement of the flights and its arguments
+func insertFlights(data []app.FlightData) (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO " + schemaname + "." + tablename + " VALUES ")
 
-	if len(data) > 0 {
-		insertSQL := "INSERT INTO " + schemaname + "." + tablename + " VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, ST_GeomFromText($20, 4326))"
+	args := make([]interface{}, 0, len(data)*flightcolumns)
+	for idx, flight := range data {
+		if idx > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for column := 1; column < flightcolumns; column++ {
+			sb.WriteString("$" + strconv.Itoa(idx*flightcolumns+column) + ", ")
+		}
+		sb.WriteString("ST_GeomFromText($" + strconv.Itoa((idx+1)*flightcolumns) + ", 4326))")
 
-		s.Log.WithContext(ctx).WithFields(logrus.Fields{
-			"SQL": insertSQL,
-		}).Info("Insert statement")
-		nbRow := int64(0)
-		for _, flight := range data {
+		args = append(args,
+			flight.FlightID,
+			flight.ICAO24BITADDRESS,
+			flight.Lat,
+			flight.Lon,
+			flight.Track,
+			flight.Altitude,
+			flight.GroundSpeed,
+			flight.Unknown1,
+			flight.TranspondeurType,
+			flight.AircraftType,
+			flight.Immatriculation1,
+			time.Unix(int64(flight.TimeStamp), 0),
+			flight.Origine,
+			flight.Destination,
+			flight.Unknown2,
+			flight.VerticalSpeed,
+			flight.Immatriculation2,
+			flight.Hint,
+			flight.Company,
+			"POINT("+fmt.Sprintf("%f", flight.Lon)+" "+fmt.Sprintf("%f", flight.Lat)+")",
+		)
+	}
+	sb.WriteString(" ON CONFLICT DO NOTHING")
 
-			result, err := s.db.Exec(insertSQL,
-				flight.FlightID,
-				flight.ICAO24BITADDRESS,
-				flight.Lat,
-				flight.Lon,
-				flight.Track,
-				flight.Altitude,
-				flight.GroundSpeed,
-				flight.Unknown1,
-				flight.TranspondeurType,
-				flight.AircraftType,
-				flight.Immatriculation1,
-				time.Unix(int64(flight.TimeStamp), 0),
-				flight.Origine,
-				flight.Destination,
-				flight.Unknown2,
-				flight.VerticalSpeed,
-				flight.Immatriculation2,
-				flight.Hint,
-				flight.Company,
-				"POINT("+fmt.Sprintf("%f", flight.Lon)+" "+fmt.Sprintf("%f", flight.Lat)+")",
-			)
-
-			if err != nil {
-				return err
-			}
-
-			nb, _ := result.RowsAffected()
-			nbRow = nbRow + nb
+	return sb.String(), args
+}
+
+// insertViolations - multi-row INSERT statement of the violations and its arguments
+func insertViolations(violations []rules.Violation) (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO " + schemaname + "." + violationtablename + " VALUES ")
+
+	args := make([]interface{}, 0, len(violations)*violationcolumns)
+	for idx, violation := range violations {
+		if idx > 0 {
+			sb.WriteString(", ")
 		}
-		s.Log.WithContext(ctx).WithFields(logrus.Fields{"Rows Affected": nbRow}).Info("Insert in DB ...")
+		sb.WriteString("(")
+		for column := 1; column < violationcolumns; column++ {
+			sb.WriteString("$" + strconv.Itoa(idx*violationcolumns+column) + ", ")
+		}
+		sb.WriteString("ST_GeomFromText($" + strconv.Itoa((idx+1)*violationcolumns) + ", 4326))")
+
+		flight := violation.Flight
+		args = append(args,
+			flight.FlightID,
+			flight.ICAO24BITADDRESS,
+			time.Unix(int64(flight.TimeStamp), 0),
+			violation.RuleID,
+			violation.Reason,
+			flight.Altitude,
+			flight.GroundSpeed,
+			"POINT("+fmt.Sprintf("%f", flight.Lon)+" "+fmt.Sprintf("%f", flight.Lat)+")",
+		)
 	}
 
+	return sb.String(), args
+}
+
+func (s *PostGreSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+	insertSQL := "INSERT INTO " + schemaname + "." + tracktablename + " VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, ST_GeomFromText($10, 4326))"
+
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"SQL": insertSQL,
+	}).Info("Insert statement")
+	nbRow := int64(0)
+	for _, track := range tracks {
+		last := track.Positions[len(track.Positions)-1]
+
+		result, err := s.db.Exec(insertSQL,
+			track.FlightID,
+			track.ICAO24BITADDRESS,
+			track.Start,
+			track.End,
+			track.MinAltitude,
+			track.MaxGroundSpeed,
+			len(track.Positions),
+			last.AircraftType,
+			last.Immatriculation1,
+			track.WKT(),
+		)
+
+		if err != nil {
+			return err
+		}
+
+		nb, _ := result.RowsAffected()
+		nbRow = nbRow + nb
+	}
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{"Rows Affected": nbRow}).Info("Insert tracks in DB ...")
+
 	return nil
 }
+
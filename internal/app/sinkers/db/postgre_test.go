@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+// flights - n aircraft over France
+func flights(n int) []app.FlightData {
+	now := float64(time.Now().Unix())
+	data := make([]app.FlightData, 0, n)
+	for idx := 0; idx < n; idx++ {
+		data = append(data, app.FlightData{
+			FlightID:         fmt.Sprintf("bench%06d", idx),
+			ICAO24BITADDRESS: fmt.Sprintf("%06X", idx),
+			Lat:              42.5 + float64(idx%500)/100,
+			Lon:              -4.5 + float64(idx/500)/10,
+			Altitude:         int64(1000 + idx%35000),
+			GroundSpeed:      int64(100 + idx%400),
+			AircraftType:     "A320",
+			TimeStamp:        now,
+			Hint:             fmt.Sprintf("BCH%04d", idx%10000),
+		})
+	}
+	return data
+}
+
+func TestInsertFlights(t *testing.T) {
+	insertSQL, args := insertFlights(flights(3))
+
+	if len(args) != 3*flightcolumns {
+		t.Errorf("expected %d arguments, got %d", 3*flightcolumns, len(args))
+	}
+	if strings.Count(insertSQL, "$") != len(args) {
+		t.Errorf("expected %d placeholders in %s", len(args), insertSQL)
+	}
+	if !strings.Contains(insertSQL, "ST_GeomFromText($60, 4326)) ON CONFLICT DO NOTHING") {
+		t.Errorf("unexpected statement end: %s", insertSQL)
+	}
+}
+
+func TestInsertViolations(t *testing.T) {
+	engine, _ := rules.New(rules.Configuration{})
+	data := flights(3)
+	//only the second flight is under the 500m of the default rule
+	data[0].Altitude = 5000
+	data[1].Altitude = 1000
+	data[2].Altitude = 5000
+	violations := engine.Violations(data)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %d", len(violations))
+	}
+
+	insertSQL, args := insertViolations(violations)
+	if len(args) != violationcolumns || args[0] != "bench000001" || args[3] != rules.DefaultRule.ID {
+		t.Errorf("unexpected arguments %v", args)
+	}
+	if strings.Count(insertSQL, "$") != len(args) || !strings.HasSuffix(insertSQL, "ST_GeomFromText($8, 4326))") {
+		t.Errorf("unexpected statement %s", insertSQL)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	for idx, m := range migrations {
+		if m.version != idx+1 {
+			t.Errorf("migration %d has version %d, versions must follow each other", idx, m.version)
+		}
+		if m.enabled != nil && m.option == "" {
+			t.Errorf("optional migration %d without option name", m.version)
+		}
+	}
+
+	enabled := func(parameters Configuration) []int {
+		var versions []int
+		for _, m := range migrations {
+			if m.isEnabled(parameters) {
+				versions = append(versions, m.version)
+			}
+		}
+		return versions
+	}
+	if got := fmt.Sprint(enabled(Configuration{})); got != "[1 2 3 4]" {
+		t.Errorf("enabled migrations without option %s, expected [1 2 3 4]", got)
+	}
+	if got := fmt.Sprint(enabled(Configuration{Unique: true})); got != "[1 2 3 4 5]" {
+		t.Errorf("enabled migrations with unique %s, expected [1 2 3 4 5]", got)
+	}
+	if got := fmt.Sprint(enabled(Configuration{Partitioning: PartitionDaily})); got != "[1 2 3 4 6]" {
+		t.Errorf("enabled migrations with partitioning %s, expected [1 2 3 4 6]", got)
+	}
+}
+
+// BenchmarkInsertFlights - statement building of a tick of 5000 aircraft
+func BenchmarkInsertFlights(b *testing.B) {
+	data := flights(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for start := 0; start < len(data); start += batchsize {
+			end := start + batchsize
+			if end > len(data) {
+				end = len(data)
+			}
+			insertFlights(data[start:end])
+		}
+	}
+}
+
+// BenchmarkSink - ticks of 5000 aircraft in a Postgres database, skipped when unreachable
+func BenchmarkSink(b *testing.B) {
+	ctx := context.Background()
+	engine, _ := rules.New(rules.Configuration{})
+	sinker := New(log, engine)
+	errInit := sinker.Init(ctx, Configuration{
+		Host:     "172.17.0.2",
+		Port:     5432,
+		User:     "postgres",
+		Password: "mysecretpassword",
+		Dbname:   "postgres",
+	})
+	if errInit != nil {
+		b.Skipf("Postgres unreachable: %v", errInit)
+	}
+	defer sinker.db.Exec("DELETE FROM " + schemaname + "." + tablename + " WHERE FlightID LIKE 'bench%'")
+
+	data := flights(5000)
+	start := time.Now()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		//new positions at each tick, the unique index would ignore them otherwise
+		for idx := range data {
+			data[idx].TimeStamp++
+		}
+		if errSink := sinker.Sink(ctx, time.Now(), data); errSink != nil {
+			b.Fatal(errSink)
+		}
+	}
+	b.ReportMetric(float64(b.N*len(data))/time.Since(start).Seconds(), "rows/s")
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.WarnLevel
+	log.Out = os.Stdout
+}
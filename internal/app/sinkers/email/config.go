@@ -0,0 +1,16 @@
+package email
+
+// Configuration settings for email alerting
+type Configuration struct {
+	Host      string   `toml:"host" default:"127.0.0.1" comment:"SMTP server host"`
+	Port      int      `toml:"port" default:"25" comment:"SMTP server port, STARTTLS is used when the server offers it"`
+	Username  string   `toml:"username" default:"" comment:"SMTP user, no authentication if empty"`
+	Password  string   `toml:"password" default:"" comment:"SMTP password"`
+	From      string   `toml:"from" default:"flighttracker@localhost" comment:"sender address"`
+	To        []string `toml:"to" comment:"recipient addresses"`
+	Subject   string   `toml:"subject" default:"[flighttracker]" comment:"prefix of the mail subjects"`
+	Immediate bool     `toml:"immediate" default:"true" comment:"send a mail for each new violating track"`
+	Digest    string   `toml:"digest" default:"08:00" comment:"local time of the daily digest of the violations (HH:MM), no digest if empty"`
+	Gap       int      `toml:"gap" default:"600" comment:"delay in second without violation after which a new violation of the same flight starts a new violating track"`
+	Zones     []string `toml:"zones" comment:"monitoring zone names whose violations are mailed, all if empty"`
+}
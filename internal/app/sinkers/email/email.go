@@ -0,0 +1,225 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+	"github.com/sirupsen/logrus"
+)
+
+// delay to send a mail: connection, SMTP dialogue and message
+const sendtimeout = 60 * time.Second
+
+func init() {
+	sinkers.Register("EMAIL", "email", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
+}
+
+//violatingTrack - the successive violating positions of a flight, identified like the tracks of the export
+type violatingTrack struct {
+	FlightID     string
+	ICAO         string
+	Callsign     string
+	AircraftType string
+	Registration string
+	Rules        []string //rule identifiers and reasons
+	Zones        []string
+	Positions    int
+	MinAltitude  int64 //meter
+	Lat          float64
+	Lon          float64 //position of the minimum altitude
+	GroundSpeed  int64   //km/h at the minimum altitude
+	Start        time.Time
+	End          time.Time
+	inDigest     bool //reported by the next digest
+}
+
+//EmailSinker - mail the new violating tracks and a daily digest of the violations
+type EmailSinker struct {
+	Log        *logrus.Logger
+	rules      *rules.Engine
+	parameters Configuration
+	zones      map[string]bool
+	gap        time.Duration
+	hour       int
+	minute     int
+	tracks     map[string]*violatingTrack //current violating track by tracking key
+	digest     []*violatingTrack          //violating tracks since the last digest
+	since      time.Time                  //start of the digest period
+	nextDigest time.Time                  //zero without digest
+	timeout    time.Duration              //delay to send a mail
+	wg         sync.WaitGroup
+}
+
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *EmailSinker {
+	//init the logger here
+	return &EmailSinker{Log: log, rules: rulesEngine, zones: map[string]bool{}, tracks: map[string]*violatingTrack{}, timeout: sendtimeout}
+}
+
+func (s *EmailSinker) Init(ctx context.Context, parameters Configuration) error {
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Host":      parameters.Host,
+		"Port":      parameters.Port,
+		"To":        parameters.To,
+		"Immediate": parameters.Immediate,
+		"Digest":    parameters.Digest,
+	}).Info("Initialisation Email sinker Parameters")
+
+	if s.rules == nil {
+		return errors.New("email sinker needs the illegal flight rules")
+	}
+	if len(parameters.To) == 0 {
+		return errors.New("no mail recipient configured")
+	}
+	if parameters.From == "" {
+		return errors.New("no mail sender configured")
+	}
+
+	if parameters.Digest != "" {
+		digestTime, err := time.Parse("15:04", parameters.Digest)
+		if err != nil {
+			return fmt.Errorf("digest time %s malformed - need HH:MM", parameters.Digest)
+		}
+		s.hour, s.minute = digestTime.Hour(), digestTime.Minute()
+		s.since = time.Now()
+		s.nextDigest = s.next(s.since)
+	}
+
+	for _, zone := range parameters.Zones {
+		s.zones[zone] = true
+	}
+	s.gap = time.Duration(parameters.Gap) * time.Second
+	s.parameters = parameters
+	return nil
+}
+
+// Sink follows the violating tracks, mails the new ones and the daily digest.
+// The immediate mail reports the first violating position of the track, the digest its lowest one.
+// The mails are sent in background, Close waits for them
+func (s *EmailSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	for _, violation := range s.rules.Violations(data) {
+		if !s.inZones(violation.Flight) {
+			continue
+		}
+		track, isNew := s.update(t, violation)
+		if isNew && s.parameters.Immediate {
+			s.send(ctx, alertSubject(track), alertBody, track)
+		}
+	}
+
+	for k, track := range s.tracks {
+		if t.Sub(track.End) > s.gap {
+			delete(s.tracks, k)
+		}
+	}
+
+	if !s.nextDigest.IsZero() && !t.Before(s.nextDigest) {
+		if len(s.digest) > 0 {
+			d := newDigest(s.since, t, s.digest)
+			s.send(ctx, digestSubject(d), digestBody, d)
+		}
+		for _, track := range s.digest {
+			track.inDigest = false
+		}
+		s.digest = nil
+		s.since = t
+		s.nextDigest = s.next(t)
+	}
+	return nil
+}
+
+// Close waits for the pending mails
+func (s *EmailSinker) Close() {
+	s.wg.Wait()
+}
+
+// update the violating track of the flight with the violation, isNew when the violation starts a track
+func (s *EmailSinker) update(t time.Time, violation rules.Violation) (*violatingTrack, bool) {
+	flight := violation.Flight
+	k := tracking.Key(flight)
+	altitude := int64(float64(flight.Altitude) * app.FEETTOMETER)
+	rule := violation.RuleID + " - " + violation.Reason
+
+	track, exists := s.tracks[k]
+	isNew := !exists || t.Sub(track.End) > s.gap
+	if isNew {
+		track = &violatingTrack{
+			FlightID:    flight.FlightID,
+			ICAO:        flight.ICAO24BITADDRESS,
+			Start:       t,
+			MinAltitude: altitude,
+			Lat:         flight.Lat,
+			Lon:         flight.Lon,
+			GroundSpeed: int64(float64(flight.GroundSpeed) * app.KTSKMH),
+		}
+		s.tracks[k] = track
+	}
+	if !track.inDigest {
+		track.inDigest = true
+		s.digest = append(s.digest, track)
+	}
+
+	if callsign := strings.TrimSpace(flight.Hint); callsign != "" {
+		track.Callsign = callsign
+	}
+	if flight.AircraftType != "" {
+		track.AircraftType = flight.AircraftType
+	}
+	if flight.Immatriculation1 != "" {
+		track.Registration = flight.Immatriculation1
+	}
+	track.Rules = appendUnique(track.Rules, rule)
+	for _, zone := range flight.Zones {
+		track.Zones = appendUnique(track.Zones, zone)
+	}
+	if track.End != t {
+		track.Positions++
+	}
+	track.End = t
+	if altitude < track.MinAltitude {
+		track.MinAltitude = altitude
+		track.Lat, track.Lon = flight.Lat, flight.Lon
+		track.GroundSpeed = int64(float64(flight.GroundSpeed) * app.KTSKMH)
+	}
+	return track, isNew
+}
+
+// inZones - true if the flight is in one of the configured zones, or no zone is configured
+func (s *EmailSinker) inZones(flight app.FlightData) bool {
+	if len(s.zones) == 0 {
+		return true
+	}
+	for _, zone := range flight.Zones {
+		if s.zones[zone] {
+			return true
+		}
+	}
+	return false
+}
+
+// next - the digest time following t
+func (s *EmailSinker) next(t time.Time) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), s.hour, s.minute, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,231 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+// low flight over the village, violation of the default rule
+var lowFlight = app.FlightData{FlightID: "2b1e4c51", ICAO24BITADDRESS: "39c4b1", Lat: 43.6, Lon: 1.44, Altitude: 800, GroundSpeed: 90,
+	Hint: "FGXYZ", AircraftType: "R44", Zones: []string{"village"}}
+
+// smtpStandIn - minimal SMTP server keeping the received messages
+type smtpStandIn struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+}
+
+func runSMTP(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &smtpStandIn{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return srv
+}
+
+func (srv *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			srv.mu.Lock()
+			srv.messages = append(srv.messages, data.String())
+			srv.mu.Unlock()
+			reply("250 queued")
+		case strings.HasPrefix(command, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func newSinker(t *testing.T, srv *smtpStandIn, zones []string) *EmailSinker {
+	rulesEngine, err := rules.New(rules.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(strings.Split(srv.listener.Addr().String(), ":")[1])
+	s := New(log, rulesEngine)
+	err = s.Init(context.Background(), Configuration{
+		Host:      "127.0.0.1",
+		Port:      port,
+		From:      "flighttracker@localhost",
+		To:        []string{"residents@localhost"},
+		Subject:   "[flighttracker]",
+		Immediate: true,
+		Digest:    "08:00",
+		Gap:       600,
+		Zones:     zones,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSinkImmediateAndDigest(t *testing.T) {
+	srv := runSMTP(t)
+	s := newSinker(t, srv, nil)
+	ctx := context.Background()
+
+	start := s.nextDigest.Add(-time.Hour)
+	//a violating track of 3 positions, the lowest at 149 meters
+	for tick, altitude := range []int64{800, 492, 600} {
+		flight := lowFlight
+		flight.Altitude = altitude
+		if err := s.Sink(ctx, start.Add(time.Duration(tick)*5*time.Second), []app.FlightData{flight}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//same aircraft after the gap, a new violating track
+	if err := s.Sink(ctx, start.Add(20*time.Minute), []app.FlightData{lowFlight}); err != nil {
+		t.Fatal(err)
+	}
+	//digest time
+	if err := s.Sink(ctx, start.Add(time.Hour), nil); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if len(srv.messages) != 3 {
+		t.Fatalf("expected 2 alerts and 1 digest, got %d mails", len(srv.messages))
+	}
+	var alerts, digests []string
+	for _, message := range srv.messages {
+		if strings.Contains(message, "Subject: [flighttracker] Daily digest") {
+			digests = append(digests, message)
+		} else {
+			alerts = append(alerts, message)
+		}
+	}
+	if len(alerts) != 2 || len(digests) != 1 {
+		t.Fatalf("expected 2 alerts and 1 digest, got %d alerts and %d digests", len(alerts), len(digests))
+	}
+
+	for _, expected := range []string{
+		"To: residents@localhost\r\n",
+		"Subject: [flighttracker] Illegal flight FGXYZ (ICAO 39c4b1, R44) over village\r\n",
+		"Altitude:     243 m\r\n",
+		"Map:          https://www.openstreetmap.org/?mlat=43.60000&mlon=1.44000#map=15/43.60000/1.44000\r\n",
+	} {
+		if !strings.Contains(alerts[0], expected) {
+			t.Errorf("alert without %q:\n%s", expected, alerts[0])
+		}
+	}
+	for _, expected := range []string{
+		": 2 violating tracks by 1 aircraft\r\n",
+		"Zone village: 2 violating tracks\r\n",
+		"Min altitude:     149 m\r\n",
+		"  Positions:    3\r\n",
+	} {
+		if !strings.Contains(digests[0], expected) {
+			t.Errorf("digest without %q:\n%s", expected, digests[0])
+		}
+	}
+}
+
+func TestSinkZones(t *testing.T) {
+	srv := runSMTP(t)
+	s := newSinker(t, srv, []string{"airport"})
+
+	if err := s.Sink(context.Background(), time.Now(), []app.FlightData{lowFlight}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if len(srv.messages) != 0 {
+		t.Errorf("expected no mail outside of the zones, got %d", len(srv.messages))
+	}
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.FatalLevel
+	log.Out = os.Stdout
+}
+
+func TestSendMailTimeout(t *testing.T) {
+	//server accepting the connection without answering
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	port, _ := strconv.Atoi(strings.Split(listener.Addr().String(), ":")[1])
+	s := New(log, nil)
+	s.parameters = Configuration{Host: "127.0.0.1", Port: port, From: "flighttracker@localhost", To: []string{"residents@localhost"}}
+	s.timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	if err := s.sendMail([]byte("Subject: test\r\n\r\ntest\r\n")); err == nil {
+		t.Error("expected an error from a silent server")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("waited %v for a silent server, expected the timeout", elapsed)
+	}
+}
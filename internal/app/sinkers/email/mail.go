@@ -0,0 +1,220 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var templateFuncs = template.FuncMap{
+	"join":     strings.Join,
+	"mapLink":  mapLink,
+	"aircraft": aircraftName,
+}
+
+var alertBody = template.Must(template.New("alert").Funcs(templateFuncs).Parse(`Illegal flight detected
+
+Aircraft:     {{aircraft .}}
+Rules:        {{join .Rules ", "}}
+Time:         {{.Start.Format "2006-01-02 15:04:05 MST"}}
+Altitude:     {{.MinAltitude}} m
+Ground speed: {{.GroundSpeed}} km/h
+{{- if .Zones}}
+Zones:        {{join .Zones ", "}}
+{{- end}}
+Position:     {{printf "%.5f,%.5f" .Lat .Lon}}
+Map:          {{mapLink .Lat .Lon}}
+`))
+
+var digestBody = template.Must(template.New("digest").Funcs(templateFuncs).Parse(`Violations from {{.Since.Format "2006-01-02 15:04"}} to {{.Until.Format "2006-01-02 15:04 MST"}}
+
+Violating tracks: {{len .Tracks}}
+Aircraft:         {{.Aircraft}}
+Min altitude:     {{.MinAltitude}} m
+{{- range .Zones}}
+Zone {{.Name}}: {{.Tracks}} violating tracks
+{{- end}}
+{{range .Tracks}}
+{{aircraft .}}
+  Time:         {{.Start.Format "2006-01-02 15:04:05"}} - {{.End.Format "15:04:05"}}
+  Rules:        {{join .Rules ", "}}
+{{- if .Zones}}
+  Zones:        {{join .Zones ", "}}
+{{- end}}
+  Positions:    {{.Positions}}
+  Min altitude: {{.MinAltitude}} m at {{printf "%.5f,%.5f" .Lat .Lon}}
+  Map:          {{mapLink .Lat .Lon}}
+{{end}}`))
+
+//digest - the violations of a period
+type digest struct {
+	Since       time.Time
+	Until       time.Time
+	Tracks      []*violatingTrack
+	Aircraft    int
+	MinAltitude int64
+	Zones       []zoneCount
+}
+
+type zoneCount struct {
+	Name   string
+	Tracks int
+}
+
+func newDigest(since time.Time, until time.Time, tracks []*violatingTrack) digest {
+	d := digest{Since: since, Until: until, Tracks: tracks, MinAltitude: tracks[0].MinAltitude}
+	aircraft := map[string]bool{}
+	zones := map[string]int{}
+	for _, track := range tracks {
+		aircraft[track.ICAO+track.FlightID] = true
+		if track.MinAltitude < d.MinAltitude {
+			d.MinAltitude = track.MinAltitude
+		}
+		for _, zone := range track.Zones {
+			zones[zone]++
+		}
+	}
+	d.Aircraft = len(aircraft)
+	for name, nb := range zones {
+		d.Zones = append(d.Zones, zoneCount{Name: name, Tracks: nb})
+	}
+	sort.Slice(d.Zones, func(i, j int) bool { return d.Zones[i].Name < d.Zones[j].Name })
+	return d
+}
+
+func alertSubject(track *violatingTrack) string {
+	subject := "Illegal flight " + aircraftName(track)
+	if len(track.Zones) > 0 {
+		subject += " over " + strings.Join(track.Zones, ", ")
+	}
+	return subject
+}
+
+func digestSubject(d digest) string {
+	return fmt.Sprintf("Daily digest %s: %d violating tracks by %d aircraft", d.Until.Format("2006-01-02"), len(d.Tracks), d.Aircraft)
+}
+
+// aircraftName - callsign, ICAO address, type and registration when known
+func aircraftName(track *violatingTrack) string {
+	details := []string{"ICAO " + track.ICAO}
+	if track.AircraftType != "" {
+		details = append(details, track.AircraftType)
+	}
+	if track.Registration != "" && track.Registration != track.Callsign {
+		details = append(details, track.Registration)
+	}
+	name := track.Callsign
+	if name == "" {
+		name = track.ICAO
+	}
+	return name + " (" + strings.Join(details, ", ") + ")"
+}
+
+func mapLink(lat float64, lon float64) string {
+	return fmt.Sprintf("https://www.openstreetmap.org/?mlat=%.5f&mlon=%.5f#map=15/%.5f/%.5f", lat, lon, lat, lon)
+}
+
+// send renders the mail now and sends it in background
+func (s *EmailSinker) send(ctx context.Context, subject string, body *template.Template, data interface{}) {
+	var text bytes.Buffer
+	if err := body.Execute(&text, data); err != nil {
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{"Error": err}).Error("Unable to render the mail")
+		return
+	}
+	message := s.message(time.Now(), s.parameters.Subject+" "+subject, text.Bytes())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.sendMail(message)
+		if err != nil {
+			s.Log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error":   err,
+				"subject": subject,
+			}).Error("Unable to send the mail")
+			return
+		}
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{"subject": subject}).Info("Send mail ...")
+	}()
+}
+
+// message - the RFC 5322 message, plain text in UTF-8
+func (s *EmailSinker) message(t time.Time, subject string, body []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("From: " + s.parameters.From + "\r\n")
+	b.WriteString("To: " + strings.Join(s.parameters.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", strings.TrimSpace(subject)) + "\r\n")
+	b.WriteString("Date: " + t.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// sendMail with STARTTLS when the server offers it, authenticated when a user is configured
+// the connection and the whole SMTP dialogue are bounded by the sinker timeout
+func (s *EmailSinker) sendMail(message []byte) error {
+	addr := net.JoinHostPort(s.parameters.Host, strconv.Itoa(s.parameters.Port))
+	conn, err := net.DialTimeout("tcp", addr, s.timeout)
+	if err != nil {
+		return err
+	}
+	if err = conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, s.parameters.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.parameters.Host}); err != nil {
+			return err
+		}
+	}
+	if s.parameters.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("SMTP server doesn't support AUTH")
+		}
+		if err = client.Auth(smtp.PlainAuth("", s.parameters.Username, s.parameters.Password, s.parameters.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(s.parameters.From); err != nil {
+		return err
+	}
+	for _, to := range s.parameters.To {
+		if err = client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(message); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,8 @@
+package fanout
+
+// Configuration settings for the fan-out to several sinkers
+type Configuration struct {
+	Queue   int `toml:"queue" default:"10" comment:"ticks waiting for each sinker, a tick is dropped for a sinker when its queue is full"`
+	Timeout int `toml:"timeout" default:"30" comment:"delay in second for a sinker to write a tick"`
+	Stats   int `toml:"stats" default:"300" comment:"delay in second between two logs of the queued, dropped and failed ticks of each sinker, 0 to disable"`
+}
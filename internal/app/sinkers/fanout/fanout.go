@@ -0,0 +1,195 @@
+package fanout
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/sirupsen/logrus"
+)
+
+//job - a tick or completed tracks to write
+type job struct {
+	t      time.Time
+	data   []app.FlightData
+	tracks []app.Track
+}
+
+//output - a sinker with its own queue and counters
+type output struct {
+	name    string
+	sinker  app.Sinker
+	queue   chan job
+	errors  int64
+	dropped int64
+}
+
+//Stats - counters of a sinker
+type Stats struct {
+	Name    string
+	Queued  int
+	Errors  int64
+	Dropped int64
+}
+
+//FanOutSinker - send each tick to several initialized sinkers concurrently, a slow sinker doesn't block the others
+type FanOutSinker struct {
+	Log     *logrus.Logger
+	outputs []*output
+	timeout time.Duration
+	wg      sync.WaitGroup
+	stop    chan struct{}
+	stats   sync.WaitGroup
+}
+
+func New(log *logrus.Logger) *FanOutSinker {
+	//init the logger here
+	return &FanOutSinker{Log: log}
+}
+
+// Add an initialized sinker, before Init
+func (s *FanOutSinker) Add(name string, sinker app.Sinker) {
+	s.outputs = append(s.outputs, &output{name: name, sinker: sinker})
+}
+
+// Init starts a goroutine by sinker
+func (s *FanOutSinker) Init(ctx context.Context, parameters Configuration) error {
+	queue := parameters.Queue
+	if queue <= 0 {
+		queue = 1
+	}
+	s.timeout = time.Duration(parameters.Timeout) * time.Second
+
+	for _, o := range s.outputs {
+		o.queue = make(chan job, queue)
+		s.wg.Add(1)
+		go s.consume(o)
+	}
+
+	s.stop = make(chan struct{})
+	if parameters.Stats > 0 {
+		s.stats.Add(1)
+		go s.logStats(time.Duration(parameters.Stats) * time.Second)
+	}
+	return nil
+}
+
+// Sink queues the tick for each sinker, never blocks
+func (s *FanOutSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	for _, o := range s.outputs {
+		s.enqueue(ctx, o, job{t: t, data: data})
+	}
+	return nil
+}
+
+// SinkTracks queues the tracks for the sinkers handling them
+func (s *FanOutSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+	for _, o := range s.outputs {
+		if _, ok := o.sinker.(app.TrackSinker); ok {
+			s.enqueue(ctx, o, job{t: t, tracks: tracks})
+		}
+	}
+	return nil
+}
+
+// Close waits for the queued ticks to be written, logs the counters, then closes the sinkers
+func (s *FanOutSinker) Close() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+	s.stats.Wait()
+
+	for _, o := range s.outputs {
+		close(o.queue)
+	}
+	s.wg.Wait()
+	s.log(context.Background())
+
+	for _, o := range s.outputs {
+		if closer, ok := o.sinker.(app.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+// Stats of each sinker
+func (s *FanOutSinker) Stats() []Stats {
+	result := make([]Stats, 0, len(s.outputs))
+	for _, o := range s.outputs {
+		result = append(result, Stats{
+			Name:    o.name,
+			Queued:  len(o.queue),
+			Errors:  atomic.LoadInt64(&o.errors),
+			Dropped: atomic.LoadInt64(&o.dropped),
+		})
+	}
+	return result
+}
+
+// logStats logs the counters of each sinker periodically until Close
+func (s *FanOutSinker) logStats(interval time.Duration) {
+	defer s.stats.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.log(context.Background())
+		}
+	}
+}
+
+func (s *FanOutSinker) log(ctx context.Context) {
+	for _, stats := range s.Stats() {
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"sinker":  stats.Name,
+			"queued":  stats.Queued,
+			"errors":  stats.Errors,
+			"dropped": stats.Dropped,
+		}).Info("Sinker stats")
+	}
+}
+
+func (s *FanOutSinker) enqueue(ctx context.Context, o *output, j job) {
+	select {
+	case o.queue <- j:
+	default:
+		dropped := atomic.AddInt64(&o.dropped, 1)
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"sinker":  o.name,
+			"dropped": dropped,
+		}).Warning("Sinker queue full, tick dropped")
+	}
+}
+
+// consume writes the queued jobs, the context is not the worker one so the last ticks are written after a stop
+func (s *FanOutSinker) consume(o *output) {
+	defer s.wg.Done()
+	for j := range o.queue {
+		ctx, cancel := context.Background(), func() {}
+		if s.timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		}
+
+		var err error
+		if j.tracks != nil {
+			err = o.sinker.(app.TrackSinker).SinkTracks(ctx, j.t, j.tracks)
+		} else {
+			err = o.sinker.Sink(ctx, j.t, j.data)
+		}
+		cancel()
+
+		if err != nil {
+			errors := atomic.AddInt64(&o.errors, 1)
+			s.Log.WithContext(ctx).WithFields(logrus.Fields{
+				"sinker": o.name,
+				"errors": errors,
+				"Error":  err,
+			}).Error("Sinker error")
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+// fakeSinker - records the ticks, blocks each write until released when blocking
+type fakeSinker struct {
+	mu       sync.Mutex
+	ticks    []time.Time
+	err      error
+	started  chan time.Time
+	release  chan struct{}
+	blocking bool
+	closed   bool
+}
+
+func newFakeSinker(blocking bool) *fakeSinker {
+	return &fakeSinker{started: make(chan time.Time, 100), release: make(chan struct{}), blocking: blocking}
+}
+
+func (f *fakeSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	f.started <- t
+	if f.blocking {
+		<-f.release
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ticks = append(f.ticks, t)
+	return f.err
+}
+
+func (f *fakeSinker) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
+
+func wait(t *testing.T, started chan time.Time, expected time.Time) {
+	t.Helper()
+	select {
+	case got := <-started:
+		if !got.Equal(expected) {
+			t.Fatalf("tick %v written, expected %v", got, expected)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("tick %v not written", expected)
+	}
+}
+
+func TestSlowSinker(t *testing.T) {
+	ctx := context.Background()
+	slow, fast, failing := newFakeSinker(true), newFakeSinker(false), newFakeSinker(false)
+	failing.err = errors.New("write failed")
+
+	s := New(log)
+	s.Add("slow", slow)
+	s.Add("fast", fast)
+	s.Add("failing", failing)
+	if err := s.Init(ctx, Configuration{Queue: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2021, 07, 22, 10, 00, 00, 0, time.UTC)
+	ticks := make([]time.Time, 5)
+	for idx := range ticks {
+		ticks[idx] = at.Add(time.Duration(idx) * time.Second)
+	}
+
+	// the slow sinker is blocked writing the first tick, the next two fill its queue and the last two are dropped
+	s.Sink(ctx, ticks[0], nil)
+	wait(t, slow.started, ticks[0])
+	wait(t, fast.started, ticks[0])
+	wait(t, failing.started, ticks[0])
+	for _, tick := range ticks[1:] {
+		s.Sink(ctx, tick, nil)
+		// the fast sinker doesn't wait for the slow one
+		wait(t, fast.started, tick)
+		wait(t, failing.started, tick)
+	}
+
+	stats := map[string]Stats{}
+	for _, stat := range s.Stats() {
+		stats[stat.Name] = stat
+	}
+	if stats["slow"].Queued != 2 || stats["slow"].Dropped != 2 || stats["slow"].Errors != 0 {
+		t.Errorf("slow sinker stats %+v, expected 2 queued and 2 dropped", stats["slow"])
+	}
+	if stats["fast"].Dropped != 0 || stats["fast"].Errors != 0 {
+		t.Errorf("fast sinker stats %+v, expected nothing dropped", stats["fast"])
+	}
+
+	close(slow.release)
+	s.Close()
+
+	if len(slow.ticks) != 3 || !slow.ticks[2].Equal(ticks[2]) {
+		t.Errorf("slow sinker wrote %v, expected the 3 first ticks", slow.ticks)
+	}
+	if len(fast.ticks) != len(ticks) {
+		t.Errorf("fast sinker wrote %v, expected every tick", fast.ticks)
+	}
+	for _, stat := range s.Stats() {
+		if stat.Name == "failing" && stat.Errors != int64(len(ticks)) {
+			t.Errorf("failing sinker stats %+v, expected %d errors", stat, len(ticks))
+		}
+	}
+	if !slow.closed || !fast.closed || !failing.closed {
+		t.Error("expected the sinkers closed")
+	}
+}
+
+func TestTracksOnlyToTrackSinkers(t *testing.T) {
+	ctx := context.Background()
+	tracker := &trackSinker{fakeSinker: newFakeSinker(false)}
+	plain := newFakeSinker(false)
+
+	s := New(log)
+	s.Add("tracker", tracker)
+	s.Add("plain", plain)
+	if err := s.Init(ctx, Configuration{Queue: 1}); err != nil {
+		t.Fatal(err)
+	}
+	s.SinkTracks(ctx, time.Now(), []app.Track{{FlightID: "AFR123"}})
+	s.Close()
+
+	if tracker.tracks != 1 {
+		t.Errorf("%d tracks written, expected 1", tracker.tracks)
+	}
+	if len(plain.ticks) != 0 {
+		t.Errorf("plain sinker wrote %v, expected nothing", plain.ticks)
+	}
+}
+
+type trackSinker struct {
+	*fakeSinker
+	tracks int
+}
+
+func (f *trackSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+	f.tracks += len(tracks)
+	return nil
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.FatalLevel
+	log.Out = os.Stdout
+}
@@ -4,4 +4,5 @@ package file
 type Configuration struct {
 	Outputraw    string `toml:"outputraw" default:"rawData.log" comment:"output raw file name"`
 	Outputreport string `toml:"outputreport" default:"report.log" comment:"output report file name"`
+	Outputtracks string `toml:"outputtracks" default:"tracks.log" comment:"output completed tracks file name"`
 }
@@ -13,26 +13,36 @@ import (
 	"time"
 
 	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
 	"github.com/sirupsen/logrus"
 )
 
 //TODO: close the files when app close
 type FileSinker struct {
 	Log             *logrus.Logger
+	rules           *rules.Engine
 	fIllegalFlights *os.File
 	fAllFlights     *os.File
+	fTracks         *os.File
 }
 
-func New(log *logrus.Logger) app.Sinker {
+func init() {
+	sinkers.Register("FILE", "file", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
+}
+
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *FileSinker {
 	//init the logger here
-	return &FileSinker{Log: log}
+	return &FileSinker{Log: log, rules: rulesEngine}
 }
 
-func (s *FileSinker) Init(ctx context.Context, params interface{}) error {
-	parameters := params.(Configuration)
+func (s *FileSinker) Init(ctx context.Context, parameters Configuration) error {
 	s.Log.WithContext(ctx).WithFields(logrus.Fields{
 		"Outputraw":    parameters.Outputraw,
 		"Outputreport": parameters.Outputreport,
+		"Outputtracks": parameters.Outputtracks,
 	}).Info("Initialisation File sinker Parameters")
 
 	logFolder := "log"
@@ -82,6 +92,19 @@ func (s *FileSinker) Init(ctx context.Context, params interface{}) error {
 		"All Flights file": s.fAllFlights.Name(),
 	}).Info("File successfully created")
 
+	fTracks, err := os.OpenFile(filepath.Join(logFolder, strconv.FormatInt(timestampFolderName, 10), parameters.Outputtracks),
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"Error": err,
+		}).Error("Unable to Open file")
+		return err
+	}
+	s.fTracks = fTracks
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Tracks file": s.fTracks.Name(),
+	}).Info("File successfully created")
+
 	return nil
 }
 
@@ -154,15 +177,8 @@ func (s *FileSinker) storeIllegalFlightOnFile(ctx context.Context, t time.Time,
 		}()
 
 		var buffer bytes.Buffer
-		var IllegalFlight []app.FlightData
-		for _, dataObj := range data {
-			//found flight above 500 meters that moving
-			if (float64(dataObj.Altitude)*app.FEETTOMETER) < float64(500) &&
-				(float64(dataObj.Altitude)*app.FEETTOMETER) > float64(25) &&
-				float64(dataObj.GroundSpeed)*app.KTSKMH > 0 {
-				IllegalFlight = append(IllegalFlight, dataObj)
-			}
-		}
+		//found flights matching the illegal flight rules
+		IllegalFlight := s.rules.Violations(data)
 
 		s.Log.WithContext(ctx).WithFields(logrus.Fields{
 			"number of Flights": len(IllegalFlight),
@@ -197,6 +213,37 @@ func (s *FileSinker) storeIllegalFlightOnFile(ctx context.Context, t time.Time,
 	return nil
 }
 
+func (s *FileSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+
+	if s.fTracks != nil {
+		w := bufio.NewWriter(s.fTracks)
+
+		defer func() {
+			w.Flush()
+		}()
+
+		Marshal, err := json.Marshal(tracks)
+		if err != nil {
+			return err
+		}
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"number of Tracks": len(tracks),
+		}).Debug("========Tracks completed=============")
+
+		n4, errWS := w.WriteString(t.String() + " Tracks\n" + string(Marshal) + "\n====================================\n")
+		if errWS != nil {
+			return errWS
+		}
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"length": fmt.Sprintf("wrote %d bytes", n4),
+		}).Debug("Wrote")
+	} else {
+		return errors.New("No Tracks file for storing data")
+	}
+
+	return nil
+}
+
 func makeDirectoryIfNotExists(path string) error {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return os.Mkdir(path, os.ModeDir|0755)
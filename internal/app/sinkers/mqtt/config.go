@@ -0,0 +1,13 @@
+package mqtt
+
+// Configuration settings for MQTT sinking
+type Configuration struct {
+	Broker   string `toml:"broker" default:"tcp://127.0.0.1:1883" comment:"MQTT broker url (tcp://, ssl:// or ws://)"`
+	ClientID string `toml:"clientID" default:"flighttracker" comment:"MQTT client identifier"`
+	Username string `toml:"username" default:"" comment:"MQTT user, anonymous if empty"`
+	Password string `toml:"password" default:"" comment:"MQTT password"`
+	Topic    string `toml:"topic" default:"flighttracker" comment:"topic prefix, aircraft states are published on <topic>/<zone>/<icao> and the tick summary on <topic>/summary"`
+	Qos      int    `toml:"qos" default:"0" comment:"MQTT quality of service (0|1|2)"`
+	Retained bool   `toml:"retained" default:"true" comment:"retain the aircraft states and the summary"`
+	Timeout  int    `toml:"timeout" default:"60" comment:"delay in second without position before the retained state of an aircraft is cleared"`
+}
@@ -0,0 +1,202 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// zone of the flights when no monitoring zone is configured
+	nozone = "all"
+	// delay for the broker to acknowledge the publications of a tick, when the ctx has no earlier deadline
+	publishtimeout = 10 * time.Second
+)
+
+func init() {
+	sinkers.Register("MQTT", "mqtt", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
+}
+
+//state - retained message of an aircraft
+type state struct {
+	app.FlightData
+	Violations []violation `json:"violations"`
+}
+
+type violation struct {
+	RuleID string `json:"ruleID"`
+	Reason string `json:"reason"`
+}
+
+//summary - message of each tick
+type summary struct {
+	Time       time.Time      `json:"time"`
+	Aircraft   int            `json:"aircraft"`
+	Violations int            `json:"violations"`
+	Zones      map[string]int `json:"zones"` //aircraft by zone
+}
+
+//MqttSinker - publish the aircraft states for home automation (Home Assistant, Node-RED, ...)
+type MqttSinker struct {
+	Log      *logrus.Logger
+	rules    *rules.Engine
+	client   paho.Client
+	topic    string
+	qos      byte
+	retained bool
+	timeout  time.Duration
+	seen     map[string]time.Time //last tick of each published aircraft topic
+}
+
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *MqttSinker {
+	//init the logger here
+	return &MqttSinker{Log: log, rules: rulesEngine, seen: map[string]time.Time{}}
+}
+
+func (s *MqttSinker) Init(ctx context.Context, parameters Configuration) error {
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Broker": parameters.Broker,
+		"Topic":  parameters.Topic,
+	}).Info("Initialisation MQTT sinker Parameters")
+
+	if parameters.Qos < 0 || parameters.Qos > 2 {
+		return fmt.Errorf("MQTT qos %d unknown - need 0, 1 or 2", parameters.Qos)
+	}
+	s.topic = strings.TrimSuffix(parameters.Topic, "/")
+	s.qos = byte(parameters.Qos)
+	s.retained = parameters.Retained
+	s.timeout = time.Duration(parameters.Timeout) * time.Second
+
+	opts := paho.NewClientOptions().
+		AddBroker(parameters.Broker).
+		SetClientID(parameters.ClientID).
+		SetUsername(parameters.Username).
+		SetPassword(parameters.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		//Home Assistant shows the sinker availability
+		SetWill(s.topic+"/status", "offline", s.qos, true).
+		SetOnConnectHandler(func(client paho.Client) {
+			client.Publish(s.topic+"/status", s.qos, true, "online")
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			s.Log.WithFields(logrus.Fields{"Warning": err}).Warning("MQTT connection lost")
+		})
+
+	s.client = paho.NewClient(opts)
+	token := s.client.Connect()
+	if !token.WaitTimeout(10 * time.Second) {
+		//the client keeps retrying in background, the messages are queued meanwhile
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{
+			"Broker": parameters.Broker,
+		}).Warning("MQTT broker unreachable, retrying")
+		return nil
+	}
+	return token.Error()
+}
+
+// Sink publishes the state of each aircraft in each of its zones, clears the aircraft not seen anymore and publishes the summary
+func (s *MqttSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	violations := map[string][]violation{}
+	nbViolation := 0
+	if s.rules != nil {
+		for _, v := range s.rules.Violations(data) {
+			k := key(v.Flight)
+			violations[k] = append(violations[k], violation{RuleID: v.RuleID, Reason: v.Reason})
+			nbViolation++
+		}
+	}
+
+	var tokens []paho.Token
+	tick := summary{Time: t, Aircraft: len(data), Violations: nbViolation, Zones: map[string]int{}}
+	for _, flight := range data {
+		flightViolations := violations[key(flight)]
+		if flightViolations == nil {
+			flightViolations = []violation{}
+		}
+		payload, err := json.Marshal(state{FlightData: flight, Violations: flightViolations})
+		if err != nil {
+			return err
+		}
+
+		flightZones := flight.Zones
+		if len(flightZones) == 0 {
+			flightZones = []string{nozone}
+		}
+		for _, zone := range flightZones {
+			topic := s.topic + "/" + topicLevel(zone) + "/" + topicLevel(strings.ToLower(flight.ICAO24BITADDRESS))
+			tokens = append(tokens, s.client.Publish(topic, s.qos, s.retained, payload))
+			s.seen[topic] = t
+			tick.Zones[zone]++
+		}
+	}
+
+	//an empty retained message removes the state of an aircraft gone
+	for topic, last := range s.seen {
+		if t.Sub(last) > s.timeout {
+			tokens = append(tokens, s.client.Publish(topic, s.qos, true, []byte{}))
+			delete(s.seen, topic)
+		}
+	}
+
+	payload, err := json.Marshal(tick)
+	if err != nil {
+		return err
+	}
+	tokens = append(tokens, s.client.Publish(s.topic+"/summary", s.qos, s.retained, payload))
+
+	return s.wait(ctx, tokens)
+}
+
+// wait for the publications, until the ctx deadline or at most publishtimeout
+func (s *MqttSinker) wait(ctx context.Context, tokens []paho.Token) error {
+	deadline := time.Now().Add(publishtimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	for _, token := range tokens {
+		if !token.WaitTimeout(time.Until(deadline)) {
+			return errors.New("MQTT publication not completed before the timeout")
+		}
+		if err := token.Error(); err != nil {
+			return err
+		}
+	}
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{"messages": len(tokens)}).Info("Publish on MQTT ...")
+	return nil
+}
+
+// key of a flight, the ICAO address when the provider gives no flight identifier
+func key(flight app.FlightData) string {
+	if flight.FlightID != "" {
+		return flight.FlightID
+	}
+	return flight.ICAO24BITADDRESS
+}
+
+// topicLevel - a value usable as a topic level (no separator nor wildcard)
+func topicLevel(value string) string {
+	level := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '+', '#', ' ':
+			return '_'
+		}
+		return r
+	}, value)
+	if level == "" {
+		return "unknown"
+	}
+	return level
+}
@@ -0,0 +1,213 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+//message - a publication of the fake client
+type message struct {
+	topic    string
+	retained bool
+	payload  []byte
+}
+
+//fakeClient - records the publications, only Publish is used by the sinker
+type fakeClient struct {
+	paho.Client
+	messages []message
+	pending  bool //the publications are never acknowledged
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, payload interface{}) paho.Token {
+	c.messages = append(c.messages, message{topic: topic, retained: retained, payload: payload.([]byte)})
+	token := &fakeToken{done: make(chan struct{})}
+	if !c.pending {
+		close(token.done)
+	}
+	return token
+}
+
+// topics published since the last call, sorted
+func (c *fakeClient) topics() []string {
+	var result []string
+	for _, m := range c.messages {
+		result = append(result, m.topic)
+	}
+	sort.Strings(result)
+	c.messages = nil
+	return result
+}
+
+type fakeToken struct {
+	done chan struct{}
+}
+
+func (t *fakeToken) Wait() bool {
+	<-t.done
+	return true
+}
+
+func (t *fakeToken) WaitTimeout(d time.Duration) bool {
+	select {
+	case <-t.done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func (t *fakeToken) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *fakeToken) Error() error {
+	return nil
+}
+
+func newSinker(t *testing.T, client paho.Client) *MqttSinker {
+	rulesEngine, err := rules.New(rules.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(log, rulesEngine)
+	s.client = client
+	s.topic = "flighttracker"
+	s.retained = true
+	s.timeout = 60 * time.Second
+	return s
+}
+
+func TestSink(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{}
+	s := newSinker(t, client)
+	at := time.Date(2021, 07, 22, 10, 00, 00, 0, time.UTC)
+
+	err := s.Sink(ctx, at, []app.FlightData{
+		//low flight over two zones, violation of the default rule
+		{FlightID: "2b1e4c51", ICAO24BITADDRESS: "39C4B1", Altitude: 800, GroundSpeed: 90, Zones: []string{"airport", "down town"}},
+		{FlightID: "2b1e4c52", ICAO24BITADDRESS: "3944EF", Altitude: 12000, GroundSpeed: 320},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tick summary
+	states := map[string]state{}
+	for _, m := range client.messages {
+		if !m.retained {
+			t.Errorf("%s not retained", m.topic)
+		}
+		if m.topic == "flighttracker/summary" {
+			if err := json.Unmarshal(m.payload, &tick); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		var st state
+		if err := json.Unmarshal(m.payload, &st); err != nil {
+			t.Fatal(err)
+		}
+		states[m.topic] = st
+	}
+
+	expected := []string{"flighttracker/airport/39c4b1", "flighttracker/all/3944ef", "flighttracker/down_town/39c4b1", "flighttracker/summary"}
+	if topics := client.topics(); !reflect.DeepEqual(topics, expected) {
+		t.Errorf("topics %v, expected %v", topics, expected)
+	}
+
+	if st := states["flighttracker/airport/39c4b1"]; len(st.Violations) != 1 || st.Violations[0].RuleID != rules.DefaultRule.ID || st.FlightID != "2b1e4c51" {
+		t.Errorf("unexpected state %+v", st)
+	}
+	if st := states["flighttracker/all/3944ef"]; st.Violations == nil || len(st.Violations) != 0 {
+		t.Errorf("expected an empty violation list, got %+v", st)
+	}
+
+	expectedTick := summary{Time: at, Aircraft: 2, Violations: 1, Zones: map[string]int{"airport": 1, "down town": 1, nozone: 1}}
+	if !reflect.DeepEqual(tick, expectedTick) {
+		t.Errorf("summary %+v, expected %+v", tick, expectedTick)
+	}
+}
+
+func TestClearRetained(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{}
+	s := newSinker(t, client)
+	at := time.Date(2021, 07, 22, 10, 00, 00, 0, time.UTC)
+	gone := app.FlightData{FlightID: "2b1e4c51", ICAO24BITADDRESS: "39C4B1", Altitude: 12000}
+	staying := app.FlightData{FlightID: "2b1e4c52", ICAO24BITADDRESS: "3944EF", Altitude: 12000}
+
+	if err := s.Sink(ctx, at, []app.FlightData{gone, staying}); err != nil {
+		t.Fatal(err)
+	}
+	client.topics()
+
+	//not cleared before the timeout
+	if err := s.Sink(ctx, at.Add(60*time.Second), []app.FlightData{staying}); err != nil {
+		t.Fatal(err)
+	}
+	if topics := client.topics(); !reflect.DeepEqual(topics, []string{"flighttracker/all/3944ef", "flighttracker/summary"}) {
+		t.Errorf("topics %v, expected the staying aircraft and the summary", topics)
+	}
+
+	if err := s.Sink(ctx, at.Add(61*time.Second), []app.FlightData{staying}); err != nil {
+		t.Fatal(err)
+	}
+	var cleared []message
+	for _, m := range client.messages {
+		if m.topic == "flighttracker/all/39c4b1" {
+			cleared = append(cleared, m)
+		}
+	}
+	if len(cleared) != 1 || !cleared[0].retained || len(cleared[0].payload) != 0 {
+		t.Errorf("expected an empty retained message clearing the gone aircraft, got %+v", cleared)
+	}
+
+	//cleared once
+	client.topics()
+	if err := s.Sink(ctx, at.Add(200*time.Second), nil); err != nil {
+		t.Fatal(err)
+	}
+	if topics := client.topics(); !reflect.DeepEqual(topics, []string{"flighttracker/all/3944ef", "flighttracker/summary"}) {
+		t.Errorf("topics %v, expected the staying aircraft cleared and the summary", topics)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	s := newSinker(t, &fakeClient{pending: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := s.Sink(ctx, time.Now(), []app.FlightData{{ICAO24BITADDRESS: "39C4B1"}}); err == nil {
+		t.Error("expected an error when the broker doesn't acknowledge")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waited %v for the acknowledgements, expected the ctx deadline", elapsed)
+	}
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.FatalLevel
+	log.Out = os.Stdout
+}
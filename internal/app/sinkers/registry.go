@@ -0,0 +1,121 @@
+package sinkers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	defaults "github.com/mcuadros/go-defaults"
+	"github.com/mitchellh/mapstructure"
+	"github.com/sirupsen/logrus"
+)
+
+//Dependencies - shared objects given to the sinker constructors
+type Dependencies struct {
+	Log   *logrus.Logger
+	Rules *rules.Engine
+}
+
+//Sinker - a sinker initialized with its typed configuration C
+type Sinker[C any] interface {
+	app.Sinker
+	Init(ctx context.Context, conf C) error
+}
+
+//Lookup - the configuration section of a sinker: its typed configuration, a map decoded from TOML or nil
+type Lookup func(section string) interface{}
+
+type registration struct {
+	section string
+	create  func(ctx context.Context, deps Dependencies, section interface{}) (app.Sinker, error)
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]registration{}
+)
+
+// Register a sinker type (the sinkertype value) with its configuration section and constructor,
+// usually from the init function of the sinker package, see sinkers/all
+func Register[C any](name, section string, newSinker func(deps Dependencies) Sinker[C]) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	name = strings.ToUpper(name)
+	if _, exists := registry[name]; exists {
+		panic("sinker " + name + " registered twice")
+	}
+
+	registry[name] = registration{
+		section: section,
+		create: func(ctx context.Context, deps Dependencies, raw interface{}) (app.Sinker, error) {
+			conf, err := decode[C](raw)
+			if err != nil {
+				return nil, fmt.Errorf("sinker %s - configuration section %s malformed: %v", name, section, err)
+			}
+			sinker := newSinker(deps)
+			if err := sinker.Init(ctx, conf); err != nil {
+				return nil, err
+			}
+			return sinker, nil
+		},
+	}
+}
+
+// New creates and initializes the sinker registered as name
+func New(ctx context.Context, name string, deps Dependencies, lookup Lookup) (app.Sinker, error) {
+	mu.RLock()
+	r, ok := registry[strings.ToUpper(name)]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Wrong sinker specified: %s - need one of %s", name, strings.Join(Names(), ", "))
+	}
+
+	var raw interface{}
+	if r.section != "" {
+		raw = lookup(r.section)
+	}
+	return r.create(ctx, deps, raw)
+}
+
+// Names of the registered sinkers
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]string, 0, len(registry))
+	for name := range registry {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// decode the configuration section: the typed configuration as is (its missing keys already took the default values
+// when the config file was read), a map on top of the default values
+func decode[C any](raw interface{}) (C, error) {
+	if conf, ok := raw.(C); ok {
+		return conf, nil
+	}
+
+	var conf C
+	defaults.SetDefaults(&conf)
+	if raw == nil {
+		return conf, nil
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           &conf,
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.StringToSliceHookFunc(","),
+	})
+	if err != nil {
+		return conf, err
+	}
+	err = decoder.Decode(raw)
+	return conf, err
+}
@@ -0,0 +1,121 @@
+package sinkers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+type testConfiguration struct {
+	Host    string   `toml:"host" default:"localhost"`
+	Port    int      `toml:"port" default:"4222"`
+	Subject string   `toml:"subject" default:"flights"`
+	Zones   []string `toml:"zones"`
+	Fail    bool     `toml:"fail"`
+}
+
+// testSinker - keeps the configuration it was initialized with
+type testSinker struct {
+	conf testConfiguration
+	deps Dependencies
+}
+
+func (s *testSinker) Init(ctx context.Context, conf testConfiguration) error {
+	if conf.Fail {
+		return errors.New("init failed")
+	}
+	s.conf = conf
+	return nil
+}
+
+func (s *testSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	return nil
+}
+
+func init() {
+	Register("test", "testsection", func(deps Dependencies) Sinker[testConfiguration] {
+		return &testSinker{deps: deps}
+	})
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		section interface{}
+		conf    testConfiguration
+	}{
+		{"no section", nil, testConfiguration{Host: "localhost", Port: 4222, Subject: "flights"}},
+		{"typed section", testConfiguration{Host: "nats", Port: 4223}, testConfiguration{Host: "nats", Port: 4223}},
+		{"map section", map[string]interface{}{"host": "nats", "port": "4223", "zones": "toulouse,blagnac"}, testConfiguration{Host: "nats", Port: 4223, Subject: "flights", Zones: []string{"toulouse", "blagnac"}}},
+		{"map section with list", map[string]interface{}{"zones": []interface{}{"toulouse"}}, testConfiguration{Host: "localhost", Port: 4222, Subject: "flights", Zones: []string{"toulouse"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookedUp string
+			lookup := func(section string) interface{} {
+				lookedUp = section
+				return tt.section
+			}
+			sinker, err := New(context.Background(), "Test", Dependencies{}, lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lookedUp != "testsection" {
+				t.Errorf("section %s looked up, expected testsection", lookedUp)
+			}
+			if conf := sinker.(*testSinker).conf; !reflect.DeepEqual(conf, tt.conf) {
+				t.Errorf("configuration %+v, expected %+v", conf, tt.conf)
+			}
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sinker  string
+		section interface{}
+		message string
+	}{
+		{"unknown sinker", "UNKNOWN", nil, "Wrong sinker specified: UNKNOWN - need one of"},
+		{"malformed section", "TEST", map[string]interface{}{"port": "not a number"}, "configuration section testsection malformed"},
+		{"init error", "TEST", map[string]interface{}{"fail": true}, "init failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(context.Background(), tt.sinker, Dependencies{}, func(string) interface{} { return tt.section })
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("error %v, expected %s", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a sinker registered twice")
+		}
+	}()
+	Register("TEST", "othersection", func(deps Dependencies) Sinker[testConfiguration] {
+		return &testSinker{}
+	})
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	found := false
+	for _, name := range names {
+		found = found || name == "TEST"
+	}
+	if !found {
+		t.Errorf("names %v, expected TEST registered upper case", names)
+	}
+}
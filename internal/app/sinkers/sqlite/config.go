@@ -0,0 +1,6 @@
+package sqlite
+
+// Configuration settings for SQLite embedded DB sinking
+type Configuration struct {
+	Path string `toml:"path" default:"flighttracker.db" comment:"SQLite database file, created if missing"`
+}
@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	tablename          = "flight"
+	rtreetablename     = "flight_rtree"
+	tracktablename     = "track"
+	violationtablename = "violation"
+)
+
+// schema - positions with an R-tree index on their coordinates, TimeStamp in unix seconds
+var schema = []string{
+	"CREATE TABLE IF NOT EXISTS " + tablename + " (id INTEGER PRIMARY KEY, FlightID TEXT NOT NULL, ICAO24BITADDRESS TEXT, Lat REAL, Lon REAL, Track INTEGER, Altitude INTEGER, GroundSpeed INTEGER, Unknown1 TEXT, TranspondeurType TEXT, AircraftType TEXT, Immatriculation1 TEXT, TimeStamp INTEGER, Origine TEXT, Destination TEXT, Unknown2 TEXT, VerticalSpeed INTEGER, Immatriculation2 TEXT, Hint TEXT, Company TEXT)",
+	"CREATE INDEX IF NOT EXISTS flight_timestamp_idx ON " + tablename + " (TimeStamp)",
+	"CREATE INDEX IF NOT EXISTS flight_icao24bitaddress_idx ON " + tablename + " (ICAO24BITADDRESS)",
+	"CREATE VIRTUAL TABLE IF NOT EXISTS " + rtreetablename + " USING rtree(id, minLon, maxLon, minLat, maxLat)",
+	"CREATE TABLE IF NOT EXISTS " + tracktablename + " (FlightID TEXT NOT NULL, ICAO24BITADDRESS TEXT, StartTime INTEGER, EndTime INTEGER, MinAltitude INTEGER, MaxGroundSpeed INTEGER, NbPosition INTEGER, AircraftType TEXT, Immatriculation1 TEXT, geom TEXT)",
+	"CREATE TABLE IF NOT EXISTS " + violationtablename + " (FlightID TEXT NOT NULL, ICAO24BITADDRESS TEXT, TimeStamp INTEGER, RuleID TEXT, Reason TEXT, Lat REAL, Lon REAL, Altitude INTEGER, GroundSpeed INTEGER)",
+	"CREATE INDEX IF NOT EXISTS violation_timestamp_idx ON " + violationtablename + " (TimeStamp)",
+}
+
+type SQLiteSinker struct {
+	Log   *logrus.Logger
+	rules *rules.Engine
+	db    *sql.DB
+}
+
+func init() {
+	sinkers.Register("SQLITE", "sqlite", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
+}
+
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *SQLiteSinker {
+	//init the logger here
+	return &SQLiteSinker{Log: log, rules: rulesEngine}
+}
+
+// Open the database file and create the schema, the WAL journal lets the search read while the sinker writes
+func Open(parameters Configuration) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+parameters.Path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range schema {
+		if _, err = db.Exec(statement); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+func (s *SQLiteSinker) Init(ctx context.Context, parameters Configuration) error {
+
+	s.Log.WithContext(ctx).Info("Init SQLite DB ... : " + parameters.Path)
+	db, err := Open(parameters)
+	if err != nil {
+		return err
+	}
+	s.db = db
+
+	return nil
+}
+
+// Sink writes the tick and its violations of the illegal flight rules in a single transaction, each position indexed in the R-tree
+func (s *SQLiteSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertSQL := "INSERT INTO " + tablename + " (FlightID, ICAO24BITADDRESS, Lat, Lon, Track, Altitude, GroundSpeed, Unknown1, TranspondeurType, AircraftType, Immatriculation1, TimeStamp, Origine, Destination, Unknown2, VerticalSpeed, Immatriculation2, Hint, Company) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	insertStmt, err := tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		return err
+	}
+	defer insertStmt.Close()
+
+	rtreeStmt, err := tx.PrepareContext(ctx, "INSERT INTO "+rtreetablename+" VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer rtreeStmt.Close()
+
+	for _, flight := range data {
+		result, err := insertStmt.ExecContext(ctx,
+			flight.FlightID,
+			flight.ICAO24BITADDRESS,
+			flight.Lat,
+			flight.Lon,
+			flight.Track,
+			flight.Altitude,
+			flight.GroundSpeed,
+			flight.Unknown1,
+			flight.TranspondeurType,
+			flight.AircraftType,
+			flight.Immatriculation1,
+			int64(flight.TimeStamp),
+			flight.Origine,
+			flight.Destination,
+			flight.Unknown2,
+			flight.VerticalSpeed,
+			flight.Immatriculation2,
+			flight.Hint,
+			flight.Company,
+		)
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		_, err = rtreeStmt.ExecContext(ctx, id, flight.Lon, flight.Lon, flight.Lat, flight.Lat)
+		if err != nil {
+			return err
+		}
+	}
+
+	violations := s.rules.Violations(data)
+	for _, violation := range violations {
+		flight := violation.Flight
+		_, err = tx.ExecContext(ctx, "INSERT INTO "+violationtablename+" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			flight.FlightID,
+			flight.ICAO24BITADDRESS,
+			int64(flight.TimeStamp),
+			violation.RuleID,
+			violation.Reason,
+			flight.Lat,
+			flight.Lon,
+			flight.Altitude,
+			flight.GroundSpeed,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{"Rows Affected": len(data), "Violations": len(violations)}).Info("Insert in SQLite DB ...")
+
+	return nil
+}
+
+func (s *SQLiteSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+	insertSQL := "INSERT INTO " + tracktablename + " VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	for _, track := range tracks {
+		last := track.Positions[len(track.Positions)-1]
+
+		_, err := s.db.ExecContext(ctx, insertSQL,
+			track.FlightID,
+			track.ICAO24BITADDRESS,
+			track.Start.Unix(),
+			track.End.Unix(),
+			track.MinAltitude,
+			track.MaxGroundSpeed,
+			len(track.Positions),
+			last.AircraftType,
+			last.Immatriculation1,
+			track.WKT(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{"Rows Affected": len(tracks)}).Info("Insert tracks in SQLite DB ...")
+
+	return nil
+}
+
@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func TestSinkViolations(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2021, 07, 22, 10, 00, 00, 0, time.UTC)
+	engine, _ := rules.New(rules.Configuration{})
+
+	sinker := New(log, engine)
+	if errInit := sinker.Init(ctx, Configuration{Path: filepath.Join(t.TempDir(), "flighttracker.db")}); errInit != nil {
+		t.Fatal(errInit)
+	}
+	errSink := sinker.Sink(ctx, at, []app.FlightData{
+		{FlightID: "low", ICAO24BITADDRESS: "39856C", Lat: 43.60, Lon: 1.44, Altitude: 1000, GroundSpeed: 100, TimeStamp: float64(at.Unix())},
+		{FlightID: "high", Lat: 43.60, Lon: 1.44, Altitude: 5000, GroundSpeed: 100, TimeStamp: float64(at.Unix())},
+	})
+	if errSink != nil {
+		t.Fatal(errSink)
+	}
+
+	var nbFlight int
+	if err := sinker.db.QueryRow("SELECT count(*) FROM " + tablename).Scan(&nbFlight); err != nil || nbFlight != 2 {
+		t.Errorf("expected 2 positions, got %d (%v)", nbFlight, err)
+	}
+
+	var flightID, ruleID string
+	var timeStamp int64
+	rows, err := sinker.db.Query("SELECT FlightID, RuleID, TimeStamp FROM " + violationtablename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	nbViolation := 0
+	for rows.Next() {
+		if err := rows.Scan(&flightID, &ruleID, &timeStamp); err != nil {
+			t.Fatal(err)
+		}
+		nbViolation++
+	}
+	if nbViolation != 1 || flightID != "low" || ruleID != rules.DefaultRule.ID || timeStamp != at.Unix() {
+		t.Errorf("expected the violation of the low flight, got %d violations, last %s %s %d", nbViolation, flightID, ruleID, timeStamp)
+	}
+}
+
+func init() {
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.WarnLevel
+	log.Out = os.Stdout
+}
@@ -0,0 +1,4 @@
+package stdout
+
+// Configuration settings for standard output sinking, nothing to set
+type Configuration struct{}
@@ -7,19 +7,28 @@ import (
 	"time"
 
 	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
 	"github.com/sirupsen/logrus"
 )
 
 type StdOutSinker struct {
-	Log *logrus.Logger
+	Log   *logrus.Logger
+	rules *rules.Engine
 }
 
-func New(log *logrus.Logger) app.Sinker {
+func init() {
+	sinkers.Register("STDOUT", "", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
+}
+
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *StdOutSinker {
 	//init the logger here
-	return &StdOutSinker{Log: log}
+	return &StdOutSinker{Log: log, rules: rulesEngine}
 }
 
-func (s *StdOutSinker) Init(ctx context.Context, params interface{}) error {
+func (s *StdOutSinker) Init(ctx context.Context, parameters Configuration) error {
 	//Nothing to do here
 	return nil
 }
@@ -28,16 +37,9 @@ func (s *StdOutSinker) Sink(ctx context.Context, t time.Time, data []app.FlightD
 	if len(data) > 0 {
 		var buffer bytes.Buffer
 		var bufferIllegalFlight bytes.Buffer
-		var IllegalFlight []app.FlightData
 
-		//found flights above 500 meters that moving
-		for _, dataObj := range data {
-			if (float64(dataObj.Altitude)*app.FEETTOMETER) < float64(500) &&
-				(float64(dataObj.Altitude)*app.FEETTOMETER) > float64(25) &&
-				float64(dataObj.GroundSpeed)*app.KTSKMH > 0 {
-				IllegalFlight = append(IllegalFlight, dataObj)
-			}
-		}
+		//found flights matching the illegal flight rules
+		IllegalFlight := s.rules.Violations(data)
 
 		//All flights
 		Marshal, err := json.Marshal(data)
@@ -61,10 +63,23 @@ func (s *StdOutSinker) Sink(ctx context.Context, t time.Time, data []app.FlightD
 		}).Debug("========IllegalFlight Flights seen=============")
 
 		bufferIllegalFlight.Write(MarshalIllegalFlight)
-		s.Log.WithContext(ctx).Debug(" Illegal Flights" + buffer.String())
+		s.Log.WithContext(ctx).Debug(" Illegal Flights" + bufferIllegalFlight.String())
 
 	} else {
 		s.Log.WithContext(ctx).Info("No Raw data")
 	}
 	return nil
 }
+
+func (s *StdOutSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+	Marshal, err := json.Marshal(tracks)
+	if err != nil {
+		return err
+	}
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"number of Tracks": len(tracks),
+	}).Info("========Tracks completed=============")
+
+	s.Log.WithContext(ctx).Debug(" Tracks" + string(Marshal))
+	return nil
+}
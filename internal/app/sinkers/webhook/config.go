@@ -0,0 +1,20 @@
+package webhook
+
+// Configuration settings for webhook alerting
+type Configuration struct {
+	Cooldown int                 `toml:"cooldown" default:"900" comment:"delay in second before alerting again on the same aircraft"`
+	Retries  int                 `toml:"retries" default:"3" comment:"retries of a failed webhook call (network error, HTTP 429 or 5xx)"`
+	Backoff  int                 `toml:"backoff" default:"2" comment:"delay in second before the first retry, doubled at each retry"`
+	Timeout  int                 `toml:"timeout" default:"10" comment:"timeout in second of a webhook call"`
+	Hook     []HookConfiguration `toml:"hook" comment:"webhooks called on each violation of the illegal flight rules ([[Flighttracker.webhook.hook]] tables)"`
+}
+
+// HookConfiguration describes a webhook
+type HookConfiguration struct {
+	Name        string            `toml:"name" comment:"webhook name, used in the logs"`
+	Url         string            `toml:"url" comment:"URL receiving the POST requests"`
+	Format      string            `toml:"format" comment:"payload format (slack|mattermost|json), json if empty"`
+	Template    string            `toml:"template" comment:"Go text/template of the payload replacing the one of the format"`
+	ContentType string            `toml:"contentType" comment:"Content-Type of the payload, application/json if empty"`
+	Headers     map[string]string `toml:"headers" comment:"additional HTTP headers (i.e. Authorization)"`
+}
@@ -0,0 +1,282 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	FormatJSON       = "json"
+	FormatSlack      = "slack"
+	FormatMattermost = "mattermost"
+
+	// time given to the pending calls by Close before canceling them
+	closetimeout = 30 * time.Second
+)
+
+// payload templates by format, Slack and Mattermost incoming webhooks share the same message format
+var formatTemplates = map[string]string{
+	FormatJSON:       `{{json .}}`,
+	FormatSlack:      `{"text": {{json .Text}}}`,
+	FormatMattermost: `{"text": {{json .Text}}, "username": "flighttracker"}`,
+}
+
+var templateFuncs = template.FuncMap{
+	//json - the JSON encoding of a value, strings are quoted and escaped
+	"json": func(value interface{}) (string, error) {
+		b, err := json.Marshal(value)
+		return string(b), err
+	},
+}
+
+func init() {
+	sinkers.Register("WEBHOOK", "webhook", func(deps sinkers.Dependencies) sinkers.Sinker[Configuration] {
+		return New(deps.Log, deps.Rules)
+	})
+}
+
+//Alert - the data of the payload templates
+type Alert struct {
+	Time          time.Time      `json:"time"`
+	RuleID        string         `json:"ruleID"`
+	Reason        string         `json:"reason"`
+	Callsign      string         `json:"callsign"`
+	AltitudeMeter int64          `json:"altitudeMeter"`
+	SpeedKmh      int64          `json:"speedKmh"`
+	Text          string         `json:"text"` //human readable message
+	Flight        app.FlightData `json:"flight"`
+}
+
+type hook struct {
+	conf     HookConfiguration
+	template *template.Template
+}
+
+//WebhookSinker - POST an alert to the webhooks for each aircraft violating the illegal flight rules
+type WebhookSinker struct {
+	Log      *logrus.Logger
+	rules    *rules.Engine
+	client   *http.Client
+	hooks    []hook
+	retries  int
+	backoff  time.Duration
+	cooldown time.Duration
+	alerted  map[string]time.Time //last alert by aircraft
+	wg       sync.WaitGroup
+
+	calls        context.Context //context of the pending calls, outliving the ticks
+	cancel       context.CancelFunc
+	closeTimeout time.Duration
+}
+
+func New(log *logrus.Logger, rulesEngine *rules.Engine) *WebhookSinker {
+	//init the logger here
+	return &WebhookSinker{Log: log, rules: rulesEngine, alerted: map[string]time.Time{}}
+}
+
+func (s *WebhookSinker) Init(ctx context.Context, parameters Configuration) error {
+	s.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"Hooks":    len(parameters.Hook),
+		"Cooldown": parameters.Cooldown,
+		"Retries":  parameters.Retries,
+	}).Info("Initialisation Webhook sinker Parameters")
+
+	if s.rules == nil {
+		return errors.New("webhook sinker needs the illegal flight rules")
+	}
+	if len(parameters.Hook) == 0 {
+		return errors.New("no webhook configured")
+	}
+
+	for idx, hookConf := range parameters.Hook {
+		if hookConf.Name == "" {
+			hookConf.Name = fmt.Sprintf("hook-%d", idx+1)
+		}
+		if hookConf.Url == "" {
+			return fmt.Errorf("webhook %s - url missing", hookConf.Name)
+		}
+		if hookConf.Format == "" {
+			hookConf.Format = FormatJSON
+		}
+		if hookConf.ContentType == "" {
+			hookConf.ContentType = "application/json"
+		}
+
+		text := hookConf.Template
+		if text == "" {
+			var ok bool
+			if text, ok = formatTemplates[strings.ToLower(hookConf.Format)]; !ok {
+				return fmt.Errorf("webhook %s - unknown format %s", hookConf.Name, hookConf.Format)
+			}
+		}
+		tmpl, err := template.New(hookConf.Name).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return fmt.Errorf("webhook %s - template malformed: %v", hookConf.Name, err)
+		}
+		s.hooks = append(s.hooks, hook{conf: hookConf, template: tmpl})
+	}
+
+	timeout := parameters.Timeout
+	if timeout <= 0 {
+		timeout = 10
+	}
+	s.client = &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	s.retries = parameters.Retries
+	s.backoff = time.Duration(parameters.Backoff) * time.Second
+	s.cooldown = time.Duration(parameters.Cooldown) * time.Second
+	s.calls, s.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	s.closeTimeout = closetimeout
+	return nil
+}
+
+// Sink alerts the webhooks of the violations, at most once by aircraft during the cooldown.
+// The calls are made in background to not delay the next ticks with the retries, they outlive
+// the ctx of the tick and Close waits for them
+func (s *WebhookSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	for aircraft, last := range s.alerted {
+		if t.Sub(last) >= s.cooldown {
+			delete(s.alerted, aircraft)
+		}
+	}
+
+	nbAlert := 0
+	for _, violation := range s.rules.Violations(data) {
+		aircraft := violation.Flight.ICAO24BITADDRESS
+		if aircraft == "" {
+			aircraft = violation.Flight.FlightID
+		}
+		if _, alerted := s.alerted[aircraft]; alerted {
+			continue
+		}
+		s.alerted[aircraft] = t
+
+		alert := newAlert(t, violation)
+		for _, h := range s.hooks {
+			var payload bytes.Buffer
+			if err := h.template.Execute(&payload, alert); err != nil {
+				return fmt.Errorf("webhook %s - %v", h.conf.Name, err)
+			}
+			s.wg.Add(1)
+			go func(h hook, payload []byte) {
+				defer s.wg.Done()
+				s.post(s.calls, h, aircraft, payload)
+			}(h, payload.Bytes())
+		}
+		nbAlert++
+	}
+
+	if nbAlert > 0 {
+		s.Log.WithContext(ctx).WithFields(logrus.Fields{"alerts": nbAlert}).Info("Alert webhooks ...")
+	}
+	return nil
+}
+
+// Close waits for the pending webhook calls, the ones still pending after the close timeout are canceled
+func (s *WebhookSinker) Close() {
+	if s.cancel == nil {
+		return
+	}
+	defer s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(s.closeTimeout):
+		s.Log.WithFields(logrus.Fields{"timeout": s.closeTimeout}).Warning("Webhook calls still pending, canceled")
+		s.cancel()
+		<-done
+	}
+}
+
+// post the payload, retried with an exponential backoff on network errors, HTTP 429 and 5xx
+func (s *WebhookSinker) post(ctx context.Context, h hook, aircraft string, payload []byte) {
+	delay := s.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.call(ctx, h, payload)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= s.retries {
+			s.Log.WithContext(ctx).WithFields(logrus.Fields{
+				"Error":    err,
+				"webhook":  h.conf.Name,
+				"aircraft": aircraft,
+				"attempts": attempt + 1,
+			}).Error("Unable to alert the webhook")
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// call the webhook once, retry is true when the failure is temporary
+func (s *WebhookSinker) call(ctx context.Context, h hook, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.conf.Url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", h.conf.ContentType)
+	for name, value := range h.conf.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook answered %s", resp.Status)
+}
+
+func newAlert(t time.Time, violation rules.Violation) Alert {
+	flight := violation.Flight
+	callsign := strings.TrimSpace(flight.Hint)
+	if callsign == "" {
+		callsign = flight.ICAO24BITADDRESS
+	}
+	altitude := int64(float64(flight.Altitude) * app.FEETTOMETER)
+	speed := int64(float64(flight.GroundSpeed) * app.KTSKMH)
+
+	text := fmt.Sprintf("Illegal flight %s (%s %s) - %s: %d m, %d km/h at %.5f,%.5f", callsign, flight.ICAO24BITADDRESS,
+		flight.AircraftType, violation.Reason, altitude, speed, flight.Lat, flight.Lon)
+	return Alert{
+		Time:          t,
+		RuleID:        violation.RuleID,
+		Reason:        violation.Reason,
+		Callsign:      callsign,
+		AltitudeMeter: altitude,
+		SpeedKmh:      speed,
+		Text:          text,
+		Flight:        flight,
+	}
+}
@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+var flights = []app.FlightData{
+	//low flight, violation of the default rule
+	{FlightID: "2b1e4c51", ICAO24BITADDRESS: "39c4b1", Lat: 43.6, Lon: 1.44, Altitude: 800, GroundSpeed: 90, TimeStamp: 1627030800, Hint: "FGXYZ", AircraftType: "R44"},
+	{FlightID: "2b1e4c52", ICAO24BITADDRESS: "3944ef", Lat: 43.7, Lon: 1.38, Altitude: 12000, GroundSpeed: 320, TimeStamp: 1627030801, Hint: "AFR61FK"},
+}
+
+// receiver - webhook answering the given status codes, then 200
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	payloads []string
+	calls    int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if len(r.statuses) > 0 {
+		w.WriteHeader(r.statuses[0])
+		r.statuses = r.statuses[1:]
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	r.payloads = append(r.payloads, string(body))
+}
+
+func newSinker(t *testing.T, conf Configuration) *WebhookSinker {
+	rulesEngine, err := rules.New(rules.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(log, rulesEngine)
+	if err := s.Init(context.Background(), conf); err != nil {
+		t.Fatal(err)
+	}
+	s.backoff = 10 * time.Millisecond
+	return s
+}
+
+func TestSinkCooldown(t *testing.T) {
+	r := &receiver{}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	s := newSinker(t, Configuration{Cooldown: 60, Timeout: 5, Hook: []HookConfiguration{{Url: srv.URL, Format: "slack"}}})
+	now := time.Now()
+	for tick := 0; tick < 5; tick++ {
+		if err := s.Sink(context.Background(), now.Add(time.Duration(tick)*5*time.Second), flights); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//after the cooldown
+	if err := s.Sink(context.Background(), now.Add(61*time.Second), flights); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if len(r.payloads) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(r.payloads))
+	}
+	var message struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(r.payloads[0]), &message); err != nil {
+		t.Fatalf("slack payload malformed: %v - %s", err, r.payloads[0])
+	}
+	expected := "Illegal flight FGXYZ (39c4b1 R44) - flight under 500 meters: 243 m, 166 km/h at 43.60000,1.44000"
+	if message.Text != expected {
+		t.Errorf("expected text %q, got %q", expected, message.Text)
+	}
+}
+
+func TestSinkRetries(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	template := `{"aircraft": {{json .Flight.ICAO24BITADDRESS}}, "rule": {{json .RuleID}}, "altitude": {{.AltitudeMeter}}}`
+	s := newSinker(t, Configuration{Cooldown: 60, Retries: 3, Timeout: 5, Hook: []HookConfiguration{{Url: srv.URL, Template: template}}})
+	if err := s.Sink(context.Background(), time.Now(), flights); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if r.calls != 3 || len(r.payloads) != 1 {
+		t.Fatalf("expected 3 calls and 1 alert, got %d calls and %d alerts", r.calls, len(r.payloads))
+	}
+	expected := `{"aircraft": "39c4b1", "rule": "low-altitude", "altitude": 243}`
+	if r.payloads[0] != expected {
+		t.Errorf("expected payload %s, got %s", expected, r.payloads[0])
+	}
+}
+
+func TestSinkNoRetryOnClientError(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusBadRequest}}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	s := newSinker(t, Configuration{Cooldown: 60, Retries: 3, Timeout: 5, Hook: []HookConfiguration{{Url: srv.URL}}})
+	if err := s.Sink(context.Background(), time.Now(), flights); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if r.calls != 1 {
+		t.Errorf("expected 1 call, got %d", r.calls)
+	}
+}
+
+func TestCloseCancelsPendingCalls(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	s := newSinker(t, Configuration{Cooldown: 60, Retries: 3, Timeout: 5, Hook: []HookConfiguration{{Url: srv.URL}}})
+	s.backoff = time.Hour
+	s.closeTimeout = 50 * time.Millisecond
+	if err := s.Sink(context.Background(), time.Now(), flights); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	s.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected Close to cancel the retries after its timeout, took %s", elapsed)
+	}
+}
+
+func init() {
+
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.FatalLevel
+	log.Out = os.Stdout
+}
@@ -0,0 +1,35 @@
+package tools
+
+import "math"
+
+// EarthRadius - mean earth radius in meter
+const EarthRadius = 6371008.8
+
+// Distance - great circle distance in meter between two points (haversine)
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi, dLambda := (lat2-lat1)*math.Pi/180, (lon2-lon1)*math.Pi/180
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// SlantDistance - distance in meter between a point on the ground and an aircraft, height is the aircraft
+// altitude above the point
+func SlantDistance(groundDistance, height float64) float64 {
+	return math.Hypot(groundDistance, height)
+}
+
+// LocalXY - meters east and north of the point from the origin, equirectangular projection accurate
+// for a few tens of kilometers
+func LocalXY(latOrigin, lonOrigin, lat, lon float64) (float64, float64) {
+	x := (lon - lonOrigin) * math.Pi / 180 * EarthRadius * math.Cos(latOrigin*math.Pi/180)
+	y := (lat - latOrigin) * math.Pi / 180 * EarthRadius
+	return x, y
+}
+
+// BboxAround - the bbox of the points closer than radius meters from the point
+func BboxAround(lat, lon, radius float64) Bbox {
+	dLat := radius / EarthRadius * 180 / math.Pi
+	dLon := dLat / math.Cos(lat*math.Pi/180)
+	return Bbox{LatSW: lat - dLat, LonSW: lon - dLon, LatNE: lat + dLat, LonNE: lon + dLon}
+}
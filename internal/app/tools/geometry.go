@@ -0,0 +1,310 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Polygon - a polygon with Lon/Lat rings (GeoJSON order), the first ring is the exterior, the next ones are holes
+type Polygon struct {
+	Rings [][][2]float64
+}
+
+// Contains - ray casting point in polygon test
+func (p Polygon) Contains(lat, lon float64) bool {
+	if len(p.Rings) == 0 || !ringContains(p.Rings[0], lat, lon) {
+		return false
+	}
+	for _, hole := range p.Rings[1:] {
+		if ringContains(hole, lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// Envelope - bounding box of the exterior ring
+func (p Polygon) Envelope() Bbox {
+	result := Bbox{LatSW: 90, LonSW: 180, LatNE: -90, LonNE: -180}
+	if len(p.Rings) == 0 {
+		return result
+	}
+	for _, point := range p.Rings[0] {
+		if point[1] < result.LatSW {
+			result.LatSW = point[1]
+		}
+		if point[1] > result.LatNE {
+			result.LatNE = point[1]
+		}
+		if point[0] < result.LonSW {
+			result.LonSW = point[0]
+		}
+		if point[0] > result.LonNE {
+			result.LonNE = point[0]
+		}
+	}
+	return result
+}
+
+func ringContains(ring [][2]float64, lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		lonI, latI := ring[i][0], ring[i][1]
+		lonJ, latJ := ring[j][0], ring[j][1]
+		if (latI > lat) != (latJ > lat) &&
+			lon < (lonJ-lonI)*(lat-latI)/(latJ-latI)+lonI {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Feature - polygons with their GeoJSON properties
+type Feature struct {
+	Properties map[string]interface{}
+	Polygons   []Polygon
+}
+
+// Contains - true if one of the feature polygons contains the point
+func (f Feature) Contains(lat, lon float64) bool {
+	for _, polygon := range f.Polygons {
+		if polygon.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+type geoJSON struct {
+	Type        string                 `json:"type"`
+	Features    []geoJSON              `json:"features"`
+	Geometry    *geoJSON               `json:"geometry"`
+	Geometries  []geoJSON              `json:"geometries"`
+	Properties  map[string]interface{} `json:"properties"`
+	Coordinates json.RawMessage        `json:"coordinates"`
+}
+
+// LoadGeoJSON - read the Polygon and MultiPolygon features of a GeoJSON file, other geometries are ignored
+func LoadGeoJSON(path string) ([]Feature, error) {
+	byt, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGeoJSON(byt)
+}
+
+// ParseGeoJSON - same as LoadGeoJSON on a GeoJSON document
+func ParseGeoJSON(byt []byte) ([]Feature, error) {
+	var doc geoJSON
+	if err := json.Unmarshal(byt, &doc); err != nil {
+		return nil, err
+	}
+
+	var result []Feature
+	switch doc.Type {
+	case "FeatureCollection":
+		for _, feature := range doc.Features {
+			polygons, err := geometryPolygons(feature.Geometry)
+			if err != nil {
+				return nil, err
+			}
+			if len(polygons) > 0 {
+				result = append(result, Feature{Properties: feature.Properties, Polygons: polygons})
+			}
+		}
+	case "Feature":
+		polygons, err := geometryPolygons(doc.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		if len(polygons) > 0 {
+			result = append(result, Feature{Properties: doc.Properties, Polygons: polygons})
+		}
+	default:
+		polygons, err := geometryPolygons(&doc)
+		if err != nil {
+			return nil, err
+		}
+		if len(polygons) > 0 {
+			result = append(result, Feature{Polygons: polygons})
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("GeoJSON without polygon")
+	}
+	return result, nil
+}
+
+func geometryPolygons(geometry *geoJSON) ([]Polygon, error) {
+	if geometry == nil {
+		return nil, nil
+	}
+
+	var result []Polygon
+	switch geometry.Type {
+	case "Polygon":
+		var rings [][][2]float64
+		if err := json.Unmarshal(geometry.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("GeoJSON Polygon malformed: %v", err)
+		}
+		result = append(result, Polygon{Rings: rings})
+	case "MultiPolygon":
+		var polygons [][][][2]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("GeoJSON MultiPolygon malformed: %v", err)
+		}
+		for _, rings := range polygons {
+			result = append(result, Polygon{Rings: rings})
+		}
+	case "GeometryCollection":
+		for idx := range geometry.Geometries {
+			polygons, err := geometryPolygons(&geometry.Geometries[idx])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, polygons...)
+		}
+	}
+	return result, nil
+}
+
+// WKT - polygon as Well Known Text
+func (p Polygon) WKT() string {
+	return "POLYGON" + p.wktRings()
+}
+
+func (p Polygon) wktRings() string {
+	rings := make([]string, 0, len(p.Rings))
+	for _, ring := range p.Rings {
+		points := make([]string, 0, len(ring))
+		for _, point := range ring {
+			points = append(points, fmt.Sprintf("%f %f", point[0], point[1]))
+		}
+		rings = append(rings, "("+strings.Join(points, ", ")+")")
+	}
+	return "(" + strings.Join(rings, ", ") + ")"
+}
+
+// PolygonsToWKT - several polygons as a MULTIPOLYGON Well Known Text
+func PolygonsToWKT(polygons []Polygon) string {
+	parts := make([]string, 0, len(polygons))
+	for _, polygon := range polygons {
+		parts = append(parts, polygon.wktRings())
+	}
+	return "MULTIPOLYGON(" + strings.Join(parts, ", ") + ")"
+}
+
+// LineToWKT - LINESTRING of the Lon/Lat points, POINT for a single point
+func LineToWKT(points [][2]float64) string {
+	coordinates := make([]string, 0, len(points))
+	for _, point := range points {
+		coordinates = append(coordinates, fmt.Sprintf("%f %f", point[0], point[1]))
+	}
+	if len(coordinates) == 1 {
+		return "POINT(" + coordinates[0] + ")"
+	}
+	return "LINESTRING(" + strings.Join(coordinates, ", ") + ")"
+}
+
+// BboxToPolygon - the bbox as a polygon
+func BboxToPolygon(bbox Bbox) Polygon {
+	return Polygon{Rings: [][][2]float64{{
+		{bbox.LonSW, bbox.LatSW},
+		{bbox.LonSW, bbox.LatNE},
+		{bbox.LonNE, bbox.LatNE},
+		{bbox.LonNE, bbox.LatSW},
+		{bbox.LonSW, bbox.LatSW},
+	}}}
+}
+
+// ParseWKT - read a POLYGON or MULTIPOLYGON Well Known Text (Lon Lat order)
+func ParseWKT(data string) ([]Polygon, error) {
+	data = strings.TrimSpace(data)
+	idx := strings.Index(data, "(")
+	if idx < 0 || !strings.HasSuffix(data, ")") {
+		return nil, errors.New("WKT malformed - need parenthesis")
+	}
+	geometryType := strings.ToUpper(strings.TrimSpace(data[:idx]))
+	body := data[idx:]
+
+	switch geometryType {
+	case "POLYGON":
+		polygon, err := parseWKTPolygon(body)
+		if err != nil {
+			return nil, err
+		}
+		return []Polygon{polygon}, nil
+	case "MULTIPOLYGON":
+		var result []Polygon
+		for _, group := range splitWKTGroups(body[1 : len(body)-1]) {
+			polygon, err := parseWKTPolygon(group)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, polygon)
+		}
+		if len(result) == 0 {
+			return nil, errors.New("WKT MULTIPOLYGON without polygon")
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("WKT geometry %s not supported - need POLYGON or MULTIPOLYGON", geometryType)
+}
+
+// parseWKTPolygon reads "((x y, x y, ...), (x y, ...))"
+func parseWKTPolygon(data string) (Polygon, error) {
+	var result Polygon
+	data = strings.TrimSpace(data)
+	if !strings.HasPrefix(data, "(") || !strings.HasSuffix(data, ")") {
+		return result, errors.New("WKT polygon malformed - need parenthesis")
+	}
+	for _, group := range splitWKTGroups(data[1 : len(data)-1]) {
+		var ring [][2]float64
+		for _, point := range strings.Split(group[1:len(group)-1], ",") {
+			coordinates := strings.Fields(point)
+			if len(coordinates) < 2 {
+				return result, fmt.Errorf("WKT point malformed - %s", point)
+			}
+			lon, errLon := strconv.ParseFloat(coordinates[0], 64)
+			if errLon != nil {
+				return result, errLon
+			}
+			lat, errLat := strconv.ParseFloat(coordinates[1], 64)
+			if errLat != nil {
+				return result, errLat
+			}
+			ring = append(ring, [2]float64{lon, lat})
+		}
+		result.Rings = append(result.Rings, ring)
+	}
+	if len(result.Rings) == 0 {
+		return result, errors.New("WKT polygon without ring")
+	}
+	return result, nil
+}
+
+// splitWKTGroups splits "(...), (...)" in top level parenthesis groups
+func splitWKTGroups(data string) []string {
+	var result []string
+	depth, start := 0, 0
+	for idx, c := range data {
+		switch c {
+		case '(':
+			if depth == 0 {
+				start = idx
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				result = append(result, data[start:idx+1])
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+// square - exterior ring from 0 to 10 with a hole from 4 to 6 (Lon/Lat)
+var square = Polygon{Rings: [][][2]float64{
+	{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+	{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}},
+}}
+
+func TestPolygonContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		inside   bool
+	}{
+		{"inside", 2, 2, true},
+		{"outside", 2, 12, false},
+		{"outside on the ring line", 0, -5, false},
+		{"in the hole", 5, 5, false},
+		{"between the hole and the ring", 5, 8, true},
+		//the edges are half open: the west and south edges belong to the polygon, not the east and north ones,
+		//a point on the border of two adjacent polygons is inside only one of them
+		{"on the west edge", 5, 0, true},
+		{"on the south edge", 0, 5, true},
+		{"on the east edge", 5, 10, false},
+		{"on the north edge", 10, 5, false},
+		{"on the west edge of the hole", 5, 4, false},
+		{"on the east edge of the hole", 5, 6, true},
+	}
+	for _, test := range tests {
+		if got := square.Contains(test.lat, test.lon); got != test.inside {
+			t.Errorf("%s (%v,%v): expected inside %v", test.name, test.lat, test.lon, test.inside)
+		}
+	}
+
+	if (Polygon{}).Contains(0, 0) {
+		t.Error("a polygon without ring contains nothing")
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	expected := Bbox{LatSW: 0, LonSW: 0, LatNE: 10, LonNE: 10}
+	if got := square.Envelope(); got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		geojson  string
+		valid    bool
+		features int
+		polygons int
+	}{
+		{"polygon", `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}`, true, 1, 1},
+		{"multipolygon", `{"type": "MultiPolygon", "coordinates": [[[[0, 0], [1, 0], [1, 1], [0, 0]]], [[[2, 2], [3, 2], [3, 3], [2, 2]]]]}`, true, 1, 2},
+		{"feature", `{"type": "Feature", "properties": {"width": 800}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`, true, 1, 1},
+		{"collection ignoring the points", `{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}},
+			{"type": "Feature", "geometry": {"type": "GeometryCollection", "geometries": [
+				{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]},
+				{"type": "LineString", "coordinates": [[0, 0], [1, 1]]}
+			]}}]}`, true, 1, 1},
+		{"not JSON", `POLYGON((0 0, 1 0, 1 1, 0 0))`, false, 0, 0},
+		{"truncated", `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0]`, false, 0, 0},
+		{"polygon coordinates malformed", `{"type": "Polygon", "coordinates": [[0, 0], [1, 0]]}`, false, 0, 0},
+		{"multipolygon coordinates malformed", `{"type": "MultiPolygon", "coordinates": [[["a", "b"]]]}`, false, 0, 0},
+		{"collection without polygon", `{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}}]}`, false, 0, 0},
+		{"feature without geometry", `{"type": "Feature", "properties": {}, "geometry": null}`, false, 0, 0},
+	}
+	for _, test := range tests {
+		features, err := ParseGeoJSON([]byte(test.geojson))
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.valid, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if len(features) != test.features || len(features[0].Polygons) != test.polygons {
+			t.Errorf("%s: expected %d features with %d polygons, got %+v", test.name, test.features, test.polygons, features)
+		}
+	}
+
+	features, _ := ParseGeoJSON([]byte(`{"type": "Feature", "properties": {"width": 800}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]]]}}`))
+	if features[0].Properties["width"] != 800.0 || !features[0].Contains(5, 5) || features[0].Contains(5, 15) {
+		t.Errorf("unexpected feature %+v", features[0])
+	}
+}
+
+func TestWKTRoundTrip(t *testing.T) {
+	polygons := []Polygon{square, BboxToPolygon(Bbox{LatSW: 43.52, LonSW: 1.32, LatNE: 43.7, LonNE: 1.69})}
+
+	parsed, err := ParseWKT(PolygonsToWKT(polygons))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, polygons) {
+		t.Errorf("expected %+v, got %+v", polygons, parsed)
+	}
+
+	parsed, err = ParseWKT(square.WKT())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, []Polygon{square}) {
+		t.Errorf("expected %+v, got %+v", square, parsed)
+	}
+}
+
+func TestParseWKT(t *testing.T) {
+	tests := []struct {
+		name     string
+		wkt      string
+		polygons int
+		rings    int //of the first polygon
+	}{
+		{"polygon", "POLYGON((1.3 43.5, 1.7 43.5, 1.7 43.7, 1.3 43.5))", 1, 1},
+		{"lower case with spaces and new lines", " polygon (\n(1.3 43.5,1.7 43.5, 1.7 43.7,1.3 43.5),\n(1.4 43.55, 1.5 43.55, 1.5 43.6, 1.4 43.55)\n)\n", 1, 2},
+		{"multipolygon", "MULTIPOLYGON(((1.3 43.5, 1.7 43.5, 1.7 43.7, 1.3 43.5)), ((2 44, 3 44, 3 45, 2 44)))", 2, 1},
+		{"empty multipolygon", "MULTIPOLYGON EMPTY", 0, 0},
+		{"multipolygon without polygon", "MULTIPOLYGON()", 0, 0},
+		{"polygon without ring", "POLYGON()", 0, 0},
+		{"empty ring", "POLYGON(())", 0, 0},
+		{"unbalanced parenthesis", "POLYGON((1.3 43.5, 1.7 43.5, 1.7 43.7, 1.3 43.5)", 0, 0},
+		{"point without latitude", "POLYGON((1.3 43.5, 1.7, 1.7 43.7, 1.3 43.5))", 0, 0},
+		{"coordinate not a number", "POLYGON((1.3 43.5, 1.7 north, 1.7 43.7, 1.3 43.5))", 0, 0},
+		{"unsupported geometry", "LINESTRING(1.3 43.5, 1.7 43.5)", 0, 0},
+		{"not WKT", "43.52,1.32^43.70,1.69", 0, 0},
+	}
+	for _, test := range tests {
+		polygons, err := ParseWKT(test.wkt)
+		if (err == nil) != (test.polygons > 0) {
+			t.Errorf("%s: expected %d polygons, got error %v", test.name, test.polygons, err)
+			continue
+		}
+		if err == nil && (len(polygons) != test.polygons || len(polygons[0].Rings) != test.rings) {
+			t.Errorf("%s: expected %d polygons with %d rings, got %+v", test.name, test.polygons, test.rings, polygons)
+		}
+	}
+}
+
+func TestLineToWKT(t *testing.T) {
+	if wkt := LineToWKT([][2]float64{{1.5, 43.5}, {1.6, 43.6}}); wkt != "LINESTRING(1.500000 43.500000, 1.600000 43.600000)" {
+		t.Errorf("unexpected line %s", wkt)
+	}
+	if wkt := LineToWKT([][2]float64{{1.5, 43.5}}); wkt != "POINT(1.500000 43.500000)" {
+		t.Errorf("unexpected point %s", wkt)
+	}
+}
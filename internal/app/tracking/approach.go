@@ -0,0 +1,104 @@
+package tracking
+
+import (
+	"math"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+//Approach - the point of a track closest to a location on the ground
+type Approach struct {
+	Time           time.Time
+	Lat            float64
+	Lon            float64
+	AltitudeMeter  float64
+	HeightMeter    float64 //above the location
+	GroundDistance float64 //meter
+	SlantDistance  float64 //meter
+	GroundSpeedKmh float64
+	Position       app.FlightData //recorded position the closest to the approach, identifies the aircraft
+}
+
+// ClosestApproach - the closest point of approach of the track to the location at elevation meters,
+// interpolated between the recorded positions
+func ClosestApproach(track app.Track, lat, lon, elevation float64) Approach {
+	return closestApproach(track, lat, lon, elevation, false)
+}
+
+// GroundApproach - same as ClosestApproach on the ground, i.e. the point the closest to overfly the location
+// whatever its height
+func GroundApproach(track app.Track, lat, lon, elevation float64) Approach {
+	return closestApproach(track, lat, lon, elevation, true)
+}
+
+// closestApproach - the height is ignored by the distance when ground is true
+func closestApproach(track app.Track, lat, lon, elevation float64, ground bool) Approach {
+	type point struct {
+		x, y, z float64
+		t       float64 //unix seconds
+		speed   float64 //km/h
+	}
+	points := make([]point, 0, len(track.Positions))
+	for _, position := range track.Positions {
+		x, y := tools.LocalXY(lat, lon, position.Lat, position.Lon)
+		points = append(points, point{
+			x:     x,
+			y:     y,
+			z:     float64(position.Altitude)*app.FEETTOMETER - elevation,
+			t:     float64(position.TimeStamp),
+			speed: float64(position.GroundSpeed) * app.KTSKMH,
+		})
+	}
+
+	//weight of the height in the distance
+	height := 1.0
+	if ground {
+		height = 0
+	}
+
+	best, bestIdx, bestDistance := points[0], 0, math.Inf(1)
+	for idx := range points {
+		candidate, ratio := points[idx], 0.0
+		if idx+1 < len(points) {
+			//closest point of the segment to the origin
+			from, to := points[idx], points[idx+1]
+			dx, dy, dz := to.x-from.x, to.y-from.y, to.z-from.z
+			if length := dx*dx + dy*dy + height*dz*dz; length > 0 {
+				ratio = math.Max(0, math.Min(1, -(from.x*dx+from.y*dy+height*from.z*dz)/length))
+			}
+			candidate = point{
+				x:     from.x + ratio*dx,
+				y:     from.y + ratio*dy,
+				z:     from.z + ratio*dz,
+				t:     from.t + ratio*(to.t-from.t),
+				speed: from.speed + ratio*(to.speed-from.speed),
+			}
+		}
+
+		distance := math.Sqrt(candidate.x*candidate.x + candidate.y*candidate.y + height*candidate.z*candidate.z)
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+			bestIdx = idx
+			if ratio > 0.5 {
+				bestIdx = idx + 1
+			}
+		}
+	}
+
+	bestLat := lat + best.y/tools.EarthRadius*180/math.Pi
+	bestLon := lon + best.x/(tools.EarthRadius*math.Cos(lat*math.Pi/180))*180/math.Pi
+	seconds, fraction := math.Modf(best.t)
+	return Approach{
+		Time:           time.Unix(int64(seconds), int64(fraction*1e9)),
+		Lat:            bestLat,
+		Lon:            bestLon,
+		AltitudeMeter:  best.z + elevation,
+		HeightMeter:    best.z,
+		GroundDistance: math.Hypot(best.x, best.y),
+		SlantDistance:  math.Sqrt(best.x*best.x + best.y*best.y + best.z*best.z),
+		GroundSpeedKmh: best.speed,
+		Position:       track.Positions[bestIdx],
+	}
+}
@@ -0,0 +1,7 @@
+package tracking
+
+// Configuration settings for flight track reconstruction
+type Configuration struct {
+	Enabled bool `toml:"enabled" default:"false" comment:"group the successive positions of a flight in tracks sent to the sinkers"`
+	Timeout int  `toml:"timeout" default:"300" comment:"delay in second without new position before a track is completed"`
+}
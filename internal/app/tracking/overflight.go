@@ -0,0 +1,96 @@
+package tracking
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+//Overflight - a track passing near a location, reported at its point the closest on the ground to the location
+type Overflight struct {
+	FlightID         string `json:"flightID"`
+	ICAO24BITADDRESS string `json:"ICAO24BITADDRESS"`
+	Callsign         string `json:"callsign"`
+	AircraftType     string `json:"aircraftType"`
+	Registration     string `json:"registration"`
+	Point
+	Slant Point `json:"slant"` //closest point of approach in 3D, i.e. of the loudest noise
+}
+
+//Point - a point of approach of an overflight
+type Point struct {
+	Time           time.Time `json:"time"`
+	Lat            float64   `json:"lat"`
+	Lon            float64   `json:"lon"`
+	AltitudeFeet   float64   `json:"altitudeFeet"`
+	AltitudeMeter  float64   `json:"altitudeMeter"`
+	Height         float64   `json:"height"`         //meter above the location
+	GroundDistance float64   `json:"groundDistance"` //meter
+	SlantDistance  float64   `json:"slantDistance"`  //meter, 3D
+	GroundSpeedKmh float64   `json:"groundSpeedKmh"`
+}
+
+// Overflights - the tracks whose point the closest on the ground to the location at elevation meters is inside
+// the time window and at less than radius meters, whatever their height, ordered by time
+func Overflights(tracks []app.Track, lat, lon, elevation, radius float64, from, to time.Time) []Overflight {
+	result := []Overflight{}
+	for _, track := range tracks {
+		approach := GroundApproach(track, lat, lon, elevation)
+		if approach.GroundDistance > radius || approach.Time.Before(from) || approach.Time.After(to) {
+			continue
+		}
+
+		callsign, aircraftType, registration := Identity(track)
+		result = append(result, Overflight{
+			FlightID:         track.FlightID,
+			ICAO24BITADDRESS: track.ICAO24BITADDRESS,
+			Callsign:         callsign,
+			AircraftType:     aircraftType,
+			Registration:     registration,
+			Point:            point(approach),
+			Slant:            point(ClosestApproach(track, lat, lon, elevation)),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
+
+// point - the approach rounded to 0.1 meter or km/h
+func point(approach Approach) Point {
+	return Point{
+		Time:           approach.Time,
+		Lat:            approach.Lat,
+		Lon:            approach.Lon,
+		AltitudeFeet:   math.Round(approach.AltitudeMeter / app.FEETTOMETER),
+		AltitudeMeter:  round(approach.AltitudeMeter),
+		Height:         round(approach.HeightMeter),
+		GroundDistance: round(approach.GroundDistance),
+		SlantDistance:  round(approach.SlantDistance),
+		GroundSpeedKmh: round(approach.GroundSpeedKmh),
+	}
+}
+
+// Identity - latest known callsign, aircraft type and registration of the track
+func Identity(track app.Track) (string, string, string) {
+	var callsign, aircraftType, registration string
+	for _, position := range track.Positions {
+		if hint := strings.TrimSpace(position.Hint); hint != "" {
+			callsign = hint
+		}
+		if position.AircraftType != "" {
+			aircraftType = position.AircraftType
+		}
+		if position.Immatriculation1 != "" {
+			registration = position.Immatriculation1
+		}
+	}
+	return callsign, aircraftType, registration
+}
+
+// round to 0.1
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
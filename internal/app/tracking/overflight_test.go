@@ -0,0 +1,102 @@
+package tracking
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+// straight - a west to east track over the latitude at the altitude (feet), recorded every interval seconds
+// from 2km before to 2km after the longitude of the house, abeam the house at t
+func straight(id string, lat float64, altitude int64, interval int64, t time.Time) app.Track {
+	const lon, speed = 1.44, 50.0 //m/s
+	track := app.Track{FlightID: id, ICAO24BITADDRESS: id}
+	for second := int64(-40); second <= 40; second += interval {
+		x := float64(second) * speed
+		track.Positions = append(track.Positions, app.FlightData{
+			FlightID:         id,
+			ICAO24BITADDRESS: id,
+			Lat:              lat,
+			Lon:              lon + x/(tools.EarthRadius*math.Cos(lat*math.Pi/180))*180/math.Pi,
+			Altitude:         altitude,
+			GroundSpeed:      97,
+			AircraftType:     "C172",
+			Immatriculation1: "F-G" + id,
+			Hint:             "F" + id,
+			TimeStamp:        float64(t.Unix() + second),
+		})
+	}
+	return track
+}
+
+func TestOverflights(t *testing.T) {
+	const lat, lon, elevation = 43.6, 1.44, 150.0
+	from := time.Date(2021, 7, 22, 8, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	north := lat + 200/tools.EarthRadius*180/math.Pi
+
+	tracks := []app.Track{
+		//200m north, 1000ft, a position every 40 seconds: the closest one is abeam
+		straight("A", north, 1000, 40, from.Add(30*time.Minute)),
+		//over the house, 2000ft, a position every 80 seconds: 2km before and after, none abeam
+		straight("B", lat, 2000, 80, from.Add(10*time.Minute)),
+		//2km north, too far
+		straight("C", lat+0.018, 1000, 10, from.Add(20*time.Minute)),
+		//outside of the time window
+		straight("D", lat, 1000, 10, to.Add(time.Minute)),
+	}
+
+	overflights := Overflights(tracks, lat, lon, elevation, 500, from, to)
+	if len(overflights) != 2 || overflights[0].FlightID != "B" || overflights[1].FlightID != "A" {
+		t.Fatalf("expected the overflights of B then A, got %+v", overflights)
+	}
+
+	b := overflights[0]
+	if math.Abs(b.GroundDistance) > 1 || b.AltitudeFeet != 2000 || math.Abs(b.Height-(2000*app.FEETTOMETER-elevation)) > 0.1 {
+		t.Errorf("expected B interpolated over the house at 2000ft, got %+v", b)
+	}
+	if !b.Time.Equal(from.Add(10*time.Minute)) || math.Abs(b.SlantDistance-b.Height) > 0.1 {
+		t.Errorf("expected B closest at %s straight above, got %+v", from.Add(10*time.Minute), b)
+	}
+
+	if math.Abs(b.Slant.SlantDistance-b.SlantDistance) > 0.1 {
+		t.Errorf("expected the same ground and 3D approaches of B in level flight, got %+v", b)
+	}
+
+	a := overflights[1]
+	if math.Abs(a.GroundDistance-200) > 1 || math.Abs(a.SlantDistance-tools.SlantDistance(200, 1000*app.FEETTOMETER-elevation)) > 1 {
+		t.Errorf("expected A 200m north at 1000ft, got %+v", a)
+	}
+	if a.Callsign != "FA" || a.AircraftType != "C172" || a.Registration != "F-GA" || math.Abs(a.GroundSpeedKmh-97*app.KTSKMH) > 0.1 {
+		t.Errorf("expected the identity and speed of A, got %+v", a)
+	}
+}
+
+func TestOverflightsDescending(t *testing.T) {
+	const lat, lon, elevation = 43.6, 1.44, 150.0
+	from := time.Date(2021, 7, 22, 8, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	//over the roof at 3000m, then descending to 300m 2km away: the 3D closest point is far from the roof
+	track := straight("E", lat, 9843, 10, from.Add(30*time.Minute))
+	for idx := range track.Positions {
+		if second := track.Positions[idx].TimeStamp - float64(from.Add(30*time.Minute).Unix()); second > 0 {
+			track.Positions[idx].Altitude = 9843 - int64((9843-984)*second/40)
+		}
+	}
+
+	overflights := Overflights([]app.Track{track}, lat, lon, elevation, 500, from, to)
+	if len(overflights) != 1 {
+		t.Fatalf("expected the overflight of E, got %+v", overflights)
+	}
+	e := overflights[0]
+	if math.Abs(e.GroundDistance) > 1 || e.AltitudeFeet != 9843 || !e.Time.Equal(from.Add(30*time.Minute)) {
+		t.Errorf("expected E over the roof at 3000m, got %+v", e)
+	}
+	if e.Slant.GroundDistance < 1000 || e.Slant.SlantDistance >= e.SlantDistance {
+		t.Errorf("expected the 3D closest point of E away from the roof, got %+v", e.Slant)
+	}
+}
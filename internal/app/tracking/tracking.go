@@ -0,0 +1,143 @@
+package tracking
+
+import (
+	"sort"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+//Tracker - group the flights of successive ticks in tracks
+type Tracker struct {
+	timeout time.Duration
+	open    map[string]*openTrack
+}
+
+type openTrack struct {
+	track   *app.Track
+	updated time.Time //tick of the last new position
+}
+
+func New(timeout time.Duration) *Tracker {
+	return &Tracker{timeout: timeout, open: map[string]*openTrack{}}
+}
+
+// Update adds the positions of a tick (t) and returns the tracks without new position
+// during the timeout, the inactivity is measured with the ticks so the provider clock doesn't matter
+func (tr *Tracker) Update(t time.Time, data []app.FlightData) []app.Track {
+	for _, flight := range data {
+		k := Key(flight)
+		current, ok := tr.open[k]
+		if !ok {
+			tr.open[k] = &openTrack{track: newTrack(flight), updated: t}
+			continue
+		}
+		if addPosition(current.track, flight) {
+			current.updated = t
+		}
+	}
+
+	var result []app.Track
+	for k, current := range tr.open {
+		if t.Sub(current.updated) > tr.timeout {
+			result = append(result, *current.track)
+			delete(tr.open, k)
+		}
+	}
+	sortTracks(result)
+
+	return result
+}
+
+// Flush completes all the open tracks
+func (tr *Tracker) Flush() []app.Track {
+	var result []app.Track
+	for k, current := range tr.open {
+		result = append(result, *current.track)
+		delete(tr.open, k)
+	}
+	sortTracks(result)
+
+	return result
+}
+
+// Reconstruct builds the tracks of stored positions, a flight without position during
+// the timeout starts a new track
+func Reconstruct(data []app.FlightData, timeout time.Duration) []app.Track {
+	sorted := make([]app.FlightData, len(data))
+	copy(sorted, data)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimeStamp < sorted[j].TimeStamp
+	})
+
+	var result []app.Track
+	open := map[string]*app.Track{}
+	for _, flight := range sorted {
+		k := Key(flight)
+		track, ok := open[k]
+		if ok && timestamp(flight).Sub(track.End) > timeout {
+			result = append(result, *track)
+			ok = false
+		}
+		if !ok {
+			open[k] = newTrack(flight)
+			continue
+		}
+		addPosition(track, flight)
+	}
+	for _, track := range open {
+		result = append(result, *track)
+	}
+	sortTracks(result)
+
+	return result
+}
+
+// Key identifies the flight of a position, the ICAO address is used when the provider gives no flight identifier
+func Key(flight app.FlightData) string {
+	if flight.FlightID != "" {
+		return flight.FlightID
+	}
+	return flight.ICAO24BITADDRESS
+}
+
+func timestamp(flight app.FlightData) time.Time {
+	return time.Unix(int64(flight.TimeStamp), 0).UTC()
+}
+
+func newTrack(flight app.FlightData) *app.Track {
+	return &app.Track{
+		FlightID:         flight.FlightID,
+		ICAO24BITADDRESS: flight.ICAO24BITADDRESS,
+		Start:            timestamp(flight),
+		End:              timestamp(flight),
+		MinAltitude:      flight.Altitude,
+		MaxGroundSpeed:   flight.GroundSpeed,
+		Positions:        []app.FlightData{flight},
+	}
+}
+
+// addPosition ignores the positions not newer than the last one, a feed can repeat a stale position
+func addPosition(track *app.Track, flight app.FlightData) bool {
+	if flight.TimeStamp <= track.Positions[len(track.Positions)-1].TimeStamp {
+		return false
+	}
+	track.Positions = append(track.Positions, flight)
+	track.End = timestamp(flight)
+	if flight.Altitude < track.MinAltitude {
+		track.MinAltitude = flight.Altitude
+	}
+	if flight.GroundSpeed > track.MaxGroundSpeed {
+		track.MaxGroundSpeed = flight.GroundSpeed
+	}
+	return true
+}
+
+func sortTracks(tracks []app.Track) {
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].Start.Equal(tracks[j].Start) {
+			return tracks[i].FlightID < tracks[j].FlightID
+		}
+		return tracks[i].Start.Before(tracks[j].Start)
+	})
+}
@@ -0,0 +1,138 @@
+package tracking
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+)
+
+var start = time.Date(2021, 7, 22, 8, 0, 0, 0, time.UTC)
+
+// position - a position of the flight second seconds after start
+func position(id string, second int, altitude, speed int64) app.FlightData {
+	return app.FlightData{FlightID: id, ICAO24BITADDRESS: "ICAO" + id, TimeStamp: float64(start.Unix() + int64(second)), Altitude: altitude, GroundSpeed: speed}
+}
+
+// timestamps of the track positions in seconds after start
+func timestamps(track app.Track) []int {
+	var result []int
+	for _, p := range track.Positions {
+		result = append(result, int(int64(p.TimeStamp)-start.Unix()))
+	}
+	return result
+}
+
+func TestUpdateTimeout(t *testing.T) {
+	tracker := New(60 * time.Second)
+	tick := func(second int, data ...app.FlightData) []app.Track {
+		return tracker.Update(start.Add(time.Duration(second)*time.Second), data)
+	}
+
+	if tracks := tick(0, position("A", 0, 1000, 100), position("B", 0, 2000, 100)); len(tracks) != 0 {
+		t.Fatalf("expected no completed track, got %+v", tracks)
+	}
+	if tracks := tick(30, position("A", 30, 800, 120)); len(tracks) != 0 {
+		t.Fatalf("expected no completed track, got %+v", tracks)
+	}
+
+	//B has no new position for more than the timeout
+	tracks := tick(61, position("A", 61, 900, 110))
+	if len(tracks) != 1 || tracks[0].FlightID != "B" || !reflect.DeepEqual(timestamps(tracks[0]), []int{0}) {
+		t.Fatalf("expected the track of B, got %+v", tracks)
+	}
+
+	//A keeps repeating its last position: the stale positions don't keep the track open
+	if tracks := tick(100, position("A", 61, 900, 110)); len(tracks) != 0 {
+		t.Fatalf("expected no completed track, got %+v", tracks)
+	}
+	tracks = tick(122, position("A", 61, 900, 110))
+	if len(tracks) != 1 || tracks[0].FlightID != "A" {
+		t.Fatalf("expected the track of A, got %+v", tracks)
+	}
+	a := tracks[0]
+	if !reflect.DeepEqual(timestamps(a), []int{0, 30, 61}) || a.MinAltitude != 800 || a.MaxGroundSpeed != 120 {
+		t.Errorf("unexpected track %+v", a)
+	}
+	if !a.Start.Equal(start) || !a.End.Equal(start.Add(61*time.Second)) || a.ICAO24BITADDRESS != "ICAOA" {
+		t.Errorf("unexpected track bounds %s - %s", a.Start, a.End)
+	}
+
+	//A comes back: a new track starts
+	tick(200, position("A", 200, 1000, 100))
+	if tracks := tracker.Flush(); len(tracks) != 1 || !reflect.DeepEqual(timestamps(tracks[0]), []int{200}) {
+		t.Errorf("expected a new track of A, got %+v", tracks)
+	}
+}
+
+func TestUpdateOutOfOrder(t *testing.T) {
+	tracker := New(60 * time.Second)
+	tracker.Update(start, []app.FlightData{position("A", 10, 1000, 100)})
+	//an older position received later is ignored
+	tracker.Update(start.Add(5*time.Second), []app.FlightData{position("A", 5, 500, 200), position("A", 20, 1100, 100)})
+
+	tracks := tracker.Flush()
+	if len(tracks) != 1 || !reflect.DeepEqual(timestamps(tracks[0]), []int{10, 20}) || tracks[0].MinAltitude != 1000 || tracks[0].MaxGroundSpeed != 100 {
+		t.Errorf("expected the positions 10 and 20, got %+v", tracks)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	tracker := New(60 * time.Second)
+	tracker.Update(start, []app.FlightData{position("B", 10, 1000, 100), position("C", 0, 1000, 100)})
+	tracker.Update(start.Add(time.Second), []app.FlightData{position("A", 10, 1000, 100)})
+
+	var ids []string
+	for _, track := range tracker.Flush() {
+		ids = append(ids, track.FlightID)
+	}
+	//ordered by start then flight identifier
+	if !reflect.DeepEqual(ids, []string{"C", "A", "B"}) {
+		t.Errorf("expected the tracks C, A and B, got %v", ids)
+	}
+	if tracks := tracker.Flush(); len(tracks) != 0 {
+		t.Errorf("expected no more open track, got %+v", tracks)
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	noID := position("", 40, 1000, 100)
+	noID.ICAO24BITADDRESS = "39856C"
+	data := []app.FlightData{
+		position("A", 400, 1000, 100),
+		position("A", 30, 900, 100),
+		position("B", 10, 1000, 100),
+		position("A", 0, 1000, 100),
+		position("A", 30, 900, 100), //stored twice
+		noID,
+		position("A", 330, 700, 150), //5 minutes after the previous position of A
+		position("A", 331, 1000, 100),
+	}
+
+	tracks := Reconstruct(data, 60*time.Second)
+	expected := []struct {
+		key       string
+		positions []int
+	}{
+		{"A", []int{0, 30}},
+		{"B", []int{10}},
+		{"39856C", []int{40}},
+		{"A", []int{330, 331}},
+		{"A", []int{400}},
+	}
+	if len(tracks) != len(expected) {
+		t.Fatalf("expected %d tracks, got %+v", len(expected), tracks)
+	}
+	for idx, track := range tracks {
+		if Key(track.Positions[0]) != expected[idx].key || !reflect.DeepEqual(timestamps(track), expected[idx].positions) {
+			t.Errorf("track %d: expected %s %v, got %s %v", idx, expected[idx].key, expected[idx].positions, Key(track.Positions[0]), timestamps(track))
+		}
+	}
+	if tracks[3].MinAltitude != 700 || tracks[3].MaxGroundSpeed != 150 {
+		t.Errorf("unexpected track %+v", tracks[3])
+	}
+	if data[0].TimeStamp != float64(start.Unix()+400) {
+		t.Error("the stored positions should not be reordered")
+	}
+}
@@ -0,0 +1,216 @@
+// flighttracker map UI: monitored area, live aircraft from /api/v1/live and historical tracks from /api/v1/search
+'use strict';
+
+// altitude classes in feet, the low flights are the hot colors
+const ALTITUDES = [
+  { max: 1000, color: '#d7191c', label: 'under 1000 ft' },
+  { max: 3000, color: '#fd8d3c', label: '1000 - 3000 ft' },
+  { max: 10000, color: '#fecc5c', label: '3000 - 10000 ft' },
+  { max: 20000, color: '#1a9641', label: '10000 - 20000 ft' },
+  { max: Infinity, color: '#2b83ba', label: 'above 20000 ft' },
+];
+
+// positions more distant in time are not linked in a track
+const TRACK_GAP_SECONDS = 600;
+
+const map = L.map('map');
+L.tileLayer('https://{s}.tile.openstreetmap.org/{z}/{x}/{y}.png', {
+  maxZoom: 18,
+  attribution: '&copy; <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a> contributors',
+}).addTo(map);
+
+const liveLayer = L.layerGroup().addTo(map);
+const historyLayer = L.layerGroup().addTo(map);
+let area = null;
+let flights = [];  // historical positions grouped by flight, sorted by time
+let bounds = null; // time range of the historical positions
+
+function altitudeColor(feet) {
+  return ALTITUDES.find((a) => feet < a.max).color;
+}
+
+function describe(flight) {
+  const callsign = (flight.Hint || '').trim() || flight.ICAO24BITADDRESS;
+  const details = [flight.AircraftType, flight.Immatriculation1].filter((d) => d).join(' ');
+  return `<b>${escapeHTML(callsign)}</b> ${escapeHTML(details)}<br>` +
+    `${flight.Altitude} ft (${Math.round(flight.Altitude * 0.3048)} m), ${flight.GroundSpeed} kts<br>` +
+    new Date(flight.TimeStamp * 1000).toLocaleString();
+}
+
+function escapeHTML(text) {
+  return String(text).replace(/[&<>"']/g, (c) => `&#${c.charCodeAt(0)};`);
+}
+
+function legend() {
+  const list = document.getElementById('legend');
+  for (const a of ALTITUDES) {
+    const item = document.createElement('li');
+    item.innerHTML = `<span style="background:${a.color}"></span>${a.label}`;
+    list.appendChild(item);
+  }
+}
+
+// monitored area: bbox or zones
+async function loadArea() {
+  const response = await fetch('api/v1/area');
+  area = await response.json();
+  L.geoJSON(area, {
+    style: { color: '#3388ff', weight: 2, fillOpacity: 0.05 },
+    onEachFeature: (feature, layer) => layer.bindTooltip(escapeHTML(feature.properties.name)),
+  }).addTo(map);
+  const [lonSW, latSW, lonNE, latNE] = area.bbox;
+  map.fitBounds([[latSW, lonSW], [latNE, lonNE]]);
+
+  const select = document.getElementById('zone');
+  for (const feature of area.features.filter((f) => f.properties.kind === 'zone')) {
+    const option = document.createElement('option');
+    option.value = option.textContent = feature.properties.name;
+    select.appendChild(option);
+  }
+}
+
+// live aircraft, a marker by aircraft updated at each tick
+let source = null;
+function live(enabled) {
+  const status = document.getElementById('live-status');
+  liveLayer.clearLayers();
+  if (source) {
+    source.close();
+    source = null;
+  }
+  if (!enabled) {
+    status.textContent = 'disabled';
+    return;
+  }
+
+  source = new EventSource('api/v1/live');
+  source.onopen = () => { status.textContent = 'waiting for the sinking service (/api/v1/start)'; };
+  source.onerror = () => { status.textContent = 'disconnected, retrying...'; };
+  source.addEventListener('tick', (event) => {
+    const tick = JSON.parse(event.data);
+    liveLayer.clearLayers();
+    for (const flight of tick.data) {
+      L.circleMarker([flight.Lat, flight.Lon], {
+        radius: 6, color: '#333', weight: 1, fillColor: altitudeColor(flight.Altitude), fillOpacity: 0.9,
+      }).bindTooltip(describe(flight)).addTo(liveLayer);
+    }
+    status.textContent = `${tick.nbFlight} aircraft at ${new Date(tick.time).toLocaleTimeString()}`;
+  });
+}
+
+// the datetime-local inputs are in the browser time zone, the search expects UTC
+function searchTime(value) {
+  return new Date(value).toISOString().slice(0, 19);
+}
+
+function inputTime(date) {
+  const local = new Date(date.getTime() - date.getTimezoneOffset() * 60000);
+  return local.toISOString().slice(0, 19);
+}
+
+async function search(event) {
+  event.preventDefault();
+  const status = document.getElementById('search-status');
+  const query = new URLSearchParams({
+    altThresholdFeet: document.getElementById('altitude').value,
+    fromTimeStamp: searchTime(document.getElementById('from').value),
+    toTimeStamp: searchTime(document.getElementById('to').value),
+  });
+  const zone = document.getElementById('zone').value;
+  if (zone) {
+    query.set('zone', zone);
+  } else {
+    const [lonSW, latSW, lonNE, latNE] = area.bbox;
+    query.set('bbox', `${latSW},${lonSW}^${latNE},${lonNE}`);
+  }
+
+  status.textContent = 'searching...';
+  const response = await fetch('api/v1/search?' + query);
+  const result = await response.json();
+  if (!response.ok) {
+    status.textContent = result.message;
+    return;
+  }
+
+  const byFlight = new Map();
+  for (const position of result.data) {
+    const id = position.flightID || position.ICAO24BITADDRESS;
+    if (!byFlight.has(id)) {
+      byFlight.set(id, []);
+    }
+    byFlight.get(id).push(position);
+  }
+  flights = [...byFlight.values()].map((positions) => positions.sort((a, b) => a.TimeStamp - b.TimeStamp));
+  status.textContent = `${result.nbFlight} positions of ${flights.length} flights`;
+
+  document.getElementById('slider-box').hidden = flights.length === 0;
+  if (flights.length === 0) {
+    historyLayer.clearLayers();
+    return;
+  }
+  bounds = {
+    from: Math.min(...flights.map((f) => f[0].TimeStamp)),
+    to: Math.max(...flights.map((f) => f[f.length - 1].TimeStamp)),
+  };
+  document.getElementById('slider').value = 1000;
+  drawHistory();
+}
+
+// draw the tracks until the slider time, each segment colored by its altitude
+function drawHistory() {
+  const slider = document.getElementById('slider');
+  const until = bounds.from + (bounds.to - bounds.from) * slider.value / 1000;
+  document.getElementById('slider-time').textContent = new Date(until * 1000).toLocaleString();
+
+  historyLayer.clearLayers();
+  for (const positions of flights) {
+    const visible = positions.filter((p) => p.TimeStamp <= until);
+    if (visible.length === 0) {
+      continue;
+    }
+    for (let i = 1; i < visible.length; i++) {
+      const [a, b] = [visible[i - 1], visible[i]];
+      if (b.TimeStamp - a.TimeStamp > TRACK_GAP_SECONDS) {
+        continue;
+      }
+      L.polyline([[a.Lat, a.Lon], [b.Lat, b.Lon]], { color: altitudeColor(b.Altitude), weight: 3 }).addTo(historyLayer);
+    }
+    const last = visible[visible.length - 1];
+    L.circleMarker([last.Lat, last.Lon], {
+      radius: 4, color: '#333', weight: 1, fillColor: altitudeColor(last.Altitude), fillOpacity: 0.9,
+    }).bindTooltip(describe(last)).addTo(historyLayer);
+  }
+}
+
+let player = null;
+function play() {
+  const slider = document.getElementById('slider');
+  const button = document.getElementById('play');
+  if (player) {
+    clearInterval(player);
+    player = null;
+    button.textContent = 'Play';
+    return;
+  }
+  if (Number(slider.value) >= 1000) {
+    slider.value = 0;
+  }
+  button.textContent = 'Pause';
+  player = setInterval(() => {
+    slider.value = Number(slider.value) + 5;
+    drawHistory();
+    if (Number(slider.value) >= 1000) {
+      play();
+    }
+  }, 100);
+}
+
+legend();
+const now = new Date();
+document.getElementById('to').value = inputTime(now);
+document.getElementById('from').value = inputTime(new Date(now.getTime() - 24 * 3600 * 1000));
+document.getElementById('search').addEventListener('submit', search);
+document.getElementById('slider').addEventListener('input', drawHistory);
+document.getElementById('play').addEventListener('click', play);
+document.getElementById('live').addEventListener('change', (event) => live(event.target.checked));
+loadArea().then(() => live(true));
@@ -0,0 +1,5 @@
+/* @preserve
+ * Leaflet 1.9.4, a JS library for interactive maps. https://leafletjs.com
+ * (c) 2010-2023 Vladimir Agafonkin, (c) 2010-2011 CloudMade
+ */
+var L=(()=>{var He=Object.defineProperty;var sn=Object.getOwnPropertyDescriptor;var an=Object.getOwnPropertyNames;var hn=Object.prototype.hasOwnProperty;var Ct=(t,e)=>{for(var i in e)He(t,i,{get:e[i],enumerable:!0})},ln=(t,e,i,o)=>{if(e&&typeof e=="object"||typeof e=="function")for(let n of an(e))!hn.call(t,n)&&n!==i&&He(t,n,{get:()=>e[n],enumerable:!(o=sn(e,n))||o.enumerable});return t};var un=t=>ln(He({},"__esModule",{value:!0}),t);var Vn={};Ct(Vn,{Bounds:()=>E,Browser:()=>u,CRS:()=>at,Canvas:()=>Si,Circle:()=>le,CircleMarker:()=>Bt,Class:()=>$,Control:()=>K,DivIcon:()=>Ei,DivOverlay:()=>nt,DomEvent:()=>R,DomUtil:()=>B,Draggable:()=>dt,Evented:()=>_t,FeatureGroup:()=>et,GeoJSON:()=>xt,GridLayer:()=>It,Handler:()=>G,Icon:()=>Pt,ImageOverlay:()=>At,LatLng:()=>T,LatLngBounds:()=>A,Layer:()=>F,LayerGroup:()=>Lt,LineUtil:()=>zt,Map:()=>_,Marker:()=>Vt,Mixin:()=>Fn,Path:()=>ot,Point:()=>c,PolyUtil:()=>Be,Polygon:()=>bt,Polyline:()=>ut,Popup:()=>de,PosAnimation:()=>Se,Projection:()=>Li,Rectangle:()=>ki,Renderer:()=>rt,SVG:()=>Yt,SVGOverlay:()=>Ui,TileLayer:()=>Et,Tooltip:()=>pe,Transformation:()=>ye,Util:()=>g,VideoOverlay:()=>Di,bind:()=>U,bounds:()=>W,canvas:()=>Ne,circle:()=>Zo,circleMarker:()=>Bo,control:()=>Gt,divIcon:()=>jo,extend:()=>b,featureGroup:()=>ko,geoJSON:()=>Ti,geoJson:()=>Ro,gridLayer:()=>Vo,icon:()=>zo,imageOverlay:()=>Fo,latLng:()=>w,latLngBounds:()=>O,layerGroup:()=>So,map:()=>Lo,marker:()=>Oo,point:()=>d,polygon:()=>Io,polyline:()=>Ao,popup:()=>Go,rectangle:()=>Xo,setOptions:()=>x,stamp:()=>P,svg:()=>Re,svgOverlay:()=>Wo,tileLayer:()=>Ci,tooltip:()=>qo,transformation:()=>vt,version:()=>Wi,videoOverlay:()=>Ho});var Wi="1.9.4";var g={};Ct(g,{bind:()=>U,cancelAnimFrame:()=>j,cancelFn:()=>Vi,create:()=>Xt,emptyImageUrl:()=>$t,extend:()=>b,falseFn:()=>z,formatNum:()=>it,getParamString:()=>je,indexOf:()=>Jt,isArray:()=>I,lastId:()=>qi,requestAnimFrame:()=>N,requestFn:()=>Ge,setOptions:()=>x,splitWords:()=>pt,stamp:()=>P,template:()=>Ve,throttle:()=>qe,trim:()=>ge,wrapNum:()=>Mt});function b(t){var e,i,o,n;for(i=1,o=arguments.length;i<o;i++){n=arguments[i];for(e in n)t[e]=n[e]}return t}var Xt=Object.create||function(){function t(){}return function(e){return t.prototype=e,new t}}();function U(t,e){var i=Array.prototype.slice;if(t.bind)return t.bind.apply(t,i.call(arguments,1));var o=i.call(arguments,2);return function(){return t.apply(e,o.length?o.concat(i.call(arguments)):arguments)}}var qi=0;function P(t){return"_leaflet_id"in t||(t._leaflet_id=++qi),t._leaflet_id}function qe(t,e,i){var o,n,r,s;return s=function(){o=!1,n&&(r.apply(i,n),n=!1)},r=function(){o?n=arguments:(t.apply(i,arguments),setTimeout(s,e),o=!0)},r}function Mt(t,e,i){var o=e[1],n=e[0],r=o-n;return t===o&&i?t:((t-n)%r+r)%r+n}function z(){return!1}function it(t,e){if(e===!1)return t;var i=Math.pow(10,e===void 0?6:e);return Math.round(t*i)/i}function ge(t){return t.trim?t.trim():t.replace(/^\s+|\s+$/g,"")}function pt(t){return ge(t).split(/\s+/)}function x(t,e){Object.prototype.hasOwnProperty.call(t,"options")||(t.options=t.options?Xt(t.options):{});for(var i in e)t.options[i]=e[i];return t.options}function je(t,e,i){var o=[];for(var n in t)o.push(encodeURIComponent(i?n.toUpperCase():n)+"="+encodeURIComponent(t[n]));return(!e||e.indexOf("?")===-1?"?":"&")+o.join("&")}var fn=/\{ *([\w_ -]+) *\}/g;function Ve(t,e){return t.replace(fn,function(i,o){var n=e[o];if(n===void 0)throw new Error("No value provided for variable "+i);return typeof n=="function"&&(n=n(e)),n})}var I=Array.isArray||function(t){return Object.prototype.toString.call(t)==="[object Array]"};function Jt(t,e){for(var i=0;i<t.length;i++)if(t[i]===e)return i;return-1}var $t="data:image/gif;base64,R0lGODlhAQABAAD/ACwAAAAAAQABAAACADs=";function We(t){return window["webkit"+t]||window["moz"+t]||window["ms"+t]}var Gi=0;function ji(t){var e=+new Date,i=Math.max(0,16-(e-Gi));return Gi=e+i,window.setTimeout(t,i)}var Ge=window.requestAnimationFrame||We("RequestAnimationFrame")||ji,Vi=window.cancelAnimationFrame||We("CancelAnimationFrame")||We("CancelRequestAnimationFrame")||function(t){window.clearTimeout(t)};function N(t,e,i){if(i&&Ge===ji)t.call(e);else return Ge.call(window,U(t,e))}function j(t){t&&Vi.call(window,t)}function $(){}$.extend=function(t){var e=function(){x(this),this.initialize&&this.initialize.apply(this,arguments),this.callInitHooks()},i=e.__super__=this.prototype,o=Xt(i);o.constructor=e,e.prototype=o;for(var n in this)Object.prototype.hasOwnProperty.call(this,n)&&n!=="prototype"&&n!=="__super__"&&(e[n]=this[n]);return t.statics&&b(e,t.statics),t.includes&&(cn(t.includes),b.apply(null,[o].concat(t.includes))),b(o,t),delete o.statics,delete o.includes,o.options&&(o.options=i.options?Xt(i.options):{},b(o.options,t.options)),o._initHooks=[],o.callInitHooks=function(){if(!this._initHooksCalled){i.callInitHooks&&i.callInitHooks.call(this),this._initHooksCalled=!0;for(var r=0,s=o._initHooks.length;r<s;r++)o._initHooks[r].call(this)}},e};$.include=function(t){var e=this.prototype.options;return b(this.prototype,t),t.options&&(this.prototype.options=e,this.mergeOptions(t.options)),this};$.mergeOptions=function(t){return b(this.prototype.options,t),this};$.addInitHook=function(t){var e=Array.prototype.slice.call(arguments,1),i=typeof t=="function"?t:function(){this[t].apply(this,e)};return this.prototype._initHooks=this.prototype._initHooks||[],this.prototype._initHooks.push(i),this};function cn(t){if(!(typeof L=="undefined"||!L||!L.Mixin)){t=I(t)?t:[t];for(var e=0;e<t.length;e++)t[e]===L.Mixin.Events&&console.warn("Deprecated include of L.Mixin.Events: this property will be removed in future releases, please inherit from L.Evented instead.",new Error().stack)}}var Q={on:function(t,e,i){if(typeof t=="object")for(var o in t)this._on(o,t[o],e);else{t=pt(t);for(var n=0,r=t.length;n<r;n++)this._on(t[n],e,i)}return this},off:function(t,e,i){if(!arguments.length)delete this._events;else if(typeof t=="object")for(var o in t)this._off(o,t[o],e);else{t=pt(t);for(var n=arguments.length===1,r=0,s=t.length;r<s;r++)n?this._off(t[r]):this._off(t[r],e,i)}return this},_on:function(t,e,i,o){if(typeof e!="function"){console.warn("wrong listener type: "+typeof e);return}if(this._listens(t,e,i)===!1){i===this&&(i=void 0);var n={fn:e,ctx:i};o&&(n.once=!0),this._events=this._events||{},this._events[t]=this._events[t]||[],this._events[t].push(n)}},_off:function(t,e,i){var o,n,r;if(this._events&&(o=this._events[t],!!o)){if(arguments.length===1){if(this._firingCount)for(n=0,r=o.length;n<r;n++)o[n].fn=z;delete this._events[t];return}if(typeof e!="function"){console.warn("wrong listener type: "+typeof e);return}var s=this._listens(t,e,i);if(s!==!1){var a=o[s];this._firingCount&&(a.fn=z,this._events[t]=o=o.slice()),o.splice(s,1)}}},fire:function(t,e,i){if(!this.listens(t,i))return this;var o=b({},e,{type:t,target:this,sourceTarget:e&&e.sourceTarget||this});if(this._events){var n=this._events[t];if(n){this._firingCount=this._firingCount+1||1;for(var r=0,s=n.length;r<s;r++){var a=n[r],h=a.fn;a.once&&this.off(t,h,a.ctx),h.call(a.ctx||this,o)}this._firingCount--}}return i&&this._propagateEvent(o),this},listens:function(t,e,i,o){typeof t!="string"&&console.warn('"string" type argument expected');var n=e;typeof e!="function"&&(o=!!e,n=void 0,i=void 0);var r=this._events&&this._events[t];if(r&&r.length&&this._listens(t,n,i)!==!1)return!0;if(o){for(var s in this._eventParents)if(this._eventParents[s].listens(t,e,i,o))return!0}return!1},_listens:function(t,e,i){if(!this._events)return!1;var o=this._events[t]||[];if(!e)return!!o.length;i===this&&(i=void 0);for(var n=0,r=o.length;n<r;n++)if(o[n].fn===e&&o[n].ctx===i)return n;return!1},once:function(t,e,i){if(typeof t=="object")for(var o in t)this._on(o,t[o],e,!0);else{t=pt(t);for(var n=0,r=t.length;n<r;n++)this._on(t[n],e,i,!0)}return this},addEventParent:function(t){return this._eventParents=this._eventParents||{},this._eventParents[P(t)]=t,this},removeEventParent:function(t){return this._eventParents&&delete this._eventParents[P(t)],this},_propagateEvent:function(t){for(var e in this._eventParents)this._eventParents[e].fire(t.type,b({layer:t.target,propagatedFrom:t.target},t),!0)}};Q.addEventListener=Q.on;Q.removeEventListener=Q.clearAllEventListeners=Q.off;Q.addOneTimeEventListener=Q.once;Q.fireEvent=Q.fire;Q.hasEventListeners=Q.listens;var _t=$.extend(Q);function c(t,e,i){this.x=i?Math.round(t):t,this.y=i?Math.round(e):e}var Ki=Math.trunc||function(t){return t>0?Math.floor(t):Math.ceil(t)};c.prototype={clone:function(){return new c(this.x,this.y)},add:function(t){return this.clone()._add(d(t))},_add:function(t){return this.x+=t.x,this.y+=t.y,this},subtract:function(t){return this.clone()._subtract(d(t))},_subtract:function(t){return this.x-=t.x,this.y-=t.y,this},divideBy:function(t){return this.clone()._divideBy(t)},_divideBy:function(t){return this.x/=t,this.y/=t,this},multiplyBy:function(t){return this.clone()._multiplyBy(t)},_multiplyBy:function(t){return this.x*=t,this.y*=t,this},scaleBy:function(t){return new c(this.x*t.x,this.y*t.y)},unscaleBy:function(t){return new c(this.x/t.x,this.y/t.y)},round:function(){return this.clone()._round()},_round:function(){return this.x=Math.round(this.x),this.y=Math.round(this.y),this},floor:function(){return this.clone()._floor()},_floor:function(){return this.x=Math.floor(this.x),this.y=Math.floor(this.y),this},ceil:function(){return this.clone()._ceil()},_ceil:function(){return this.x=Math.ceil(this.x),this.y=Math.ceil(this.y),this},trunc:function(){return this.clone()._trunc()},_trunc:function(){return this.x=Ki(this.x),this.y=Ki(this.y),this},distanceTo:function(t){t=d(t);var e=t.x-this.x,i=t.y-this.y;return Math.sqrt(e*e+i*i)},equals:function(t){return t=d(t),t.x===this.x&&t.y===this.y},contains:function(t){return t=d(t),Math.abs(t.x)<=Math.abs(this.x)&&Math.abs(t.y)<=Math.abs(this.y)},toString:function(){return"Point("+it(this.x)+", "+it(this.y)+")"}};function d(t,e,i){return t instanceof c?t:I(t)?new c(t[0],t[1]):t==null?t:typeof t=="object"&&"x"in t&&"y"in t?new c(t.x,t.y):new c(t,e,i)}function E(t,e){if(t)for(var i=e?[t,e]:t,o=0,n=i.length;o<n;o++)this.extend(i[o])}E.prototype={extend:function(t){var e,i;if(!t)return this;if(t instanceof c||typeof t[0]=="number"||"x"in t)e=i=d(t);else if(t=W(t),e=t.min,i=t.max,!e||!i)return this;return!this.min&&!this.max?(this.min=e.clone(),this.max=i.clone()):(this.min.x=Math.min(e.x,this.min.x),this.max.x=Math.max(i.x,this.max.x),this.min.y=Math.min(e.y,this.min.y),this.max.y=Math.max(i.y,this.max.y)),this},getCenter:function(t){return d((this.min.x+this.max.x)/2,(this.min.y+this.max.y)/2,t)},getBottomLeft:function(){return d(this.min.x,this.max.y)},getTopRight:function(){return d(this.max.x,this.min.y)},getTopLeft:function(){return this.min},getBottomRight:function(){return this.max},getSize:function(){return this.max.subtract(this.min)},contains:function(t){var e,i;return typeof t[0]=="number"||t instanceof c?t=d(t):t=W(t),t instanceof E?(e=t.min,i=t.max):e=i=t,e.x>=this.min.x&&i.x<=this.max.x&&e.y>=this.min.y&&i.y<=this.max.y},intersects:function(t){t=W(t);var e=this.min,i=this.max,o=t.min,n=t.max,r=n.x>=e.x&&o.x<=i.x,s=n.y>=e.y&&o.y<=i.y;return r&&s},overlaps:function(t){t=W(t);var e=this.min,i=this.max,o=t.min,n=t.max,r=n.x>e.x&&o.x<i.x,s=n.y>e.y&&o.y<i.y;return r&&s},isValid:function(){return!!(this.min&&this.max)},pad:function(t){var e=this.min,i=this.max,o=Math.abs(e.x-i.x)*t,n=Math.abs(e.y-i.y)*t;return W(d(e.x-o,e.y-n),d(i.x+o,i.y+n))},equals:function(t){return t?(t=W(t),this.min.equals(t.getTopLeft())&&this.max.equals(t.getBottomRight())):!1}};function W(t,e){return!t||t instanceof E?t:new E(t,e)}function A(t,e){if(t)for(var i=e?[t,e]:t,o=0,n=i.length;o<n;o++)this.extend(i[o])}A.prototype={extend:function(t){var e=this._southWest,i=this._northEast,o,n;if(t instanceof T)o=t,n=t;else if(t instanceof A){if(o=t._southWest,n=t._northEast,!o||!n)return this}else return t?this.extend(w(t)||O(t)):this;return!e&&!i?(this._southWest=new T(o.lat,o.lng),this._northEast=new T(n.lat,n.lng)):(e.lat=Math.min(o.lat,e.lat),e.lng=Math.min(o.lng,e.lng),i.lat=Math.max(n.lat,i.lat),i.lng=Math.max(n.lng,i.lng)),this},pad:function(t){var e=this._southWest,i=this._northEast,o=Math.abs(e.lat-i.lat)*t,n=Math.abs(e.lng-i.lng)*t;return new A(new T(e.lat-o,e.lng-n),new T(i.lat+o,i.lng+n))},getCenter:function(){return new T((this._southWest.lat+this._northEast.lat)/2,(this._southWest.lng+this._northEast.lng)/2)},getSouthWest:function(){return this._southWest},getNorthEast:function(){return this._northEast},getNorthWest:function(){return new T(this.getNorth(),this.getWest())},getSouthEast:function(){return new T(this.getSouth(),this.getEast())},getWest:function(){return this._southWest.lng},getSouth:function(){return this._southWest.lat},getEast:function(){return this._northEast.lng},getNorth:function(){return this._northEast.lat},contains:function(t){typeof t[0]=="number"||t instanceof T||"lat"in t?t=w(t):t=O(t);var e=this._southWest,i=this._northEast,o,n;return t instanceof A?(o=t.getSouthWest(),n=t.getNorthEast()):o=n=t,o.lat>=e.lat&&n.lat<=i.lat&&o.lng>=e.lng&&n.lng<=i.lng},intersects:function(t){t=O(t);var e=this._southWest,i=this._northEast,o=t.getSouthWest(),n=t.getNorthEast(),r=n.lat>=e.lat&&o.lat<=i.lat,s=n.lng>=e.lng&&o.lng<=i.lng;return r&&s},overlaps:function(t){t=O(t);var e=this._southWest,i=this._northEast,o=t.getSouthWest(),n=t.getNorthEast(),r=n.lat>e.lat&&o.lat<i.lat,s=n.lng>e.lng&&o.lng<i.lng;return r&&s},toBBoxString:function(){return[this.getWest(),this.getSouth(),this.getEast(),this.getNorth()].join(",")},equals:function(t,e){return t?(t=O(t),this._southWest.equals(t.getSouthWest(),e)&&this._northEast.equals(t.getNorthEast(),e)):!1},isValid:function(){return!!(this._southWest&&this._northEast)}};function O(t,e){return t instanceof A?t:new A(t,e)}function T(t,e,i){if(isNaN(t)||isNaN(e))throw new Error("Invalid LatLng object: ("+t+", "+e+")");this.lat=+t,this.lng=+e,i!==void 0&&(this.alt=+i)}T.prototype={equals:function(t,e){if(!t)return!1;t=w(t);var i=Math.max(Math.abs(this.lat-t.lat),Math.abs(this.lng-t.lng));return i<=(e===void 0?1e-9:e)},toString:function(t){return"LatLng("+it(this.lat,t)+", "+it(this.lng,t)+")"},distanceTo:function(t){return tt.distance(this,w(t))},wrap:function(){return tt.wrapLatLng(this)},toBounds:function(t){var e=180*t/40075017,i=e/Math.cos(Math.PI/180*this.lat);return O([this.lat-e,this.lng-i],[this.lat+e,this.lng+i])},clone:function(){return new T(this.lat,this.lng,this.alt)}};function w(t,e,i){return t instanceof T?t:I(t)&&typeof t[0]!="object"?t.length===3?new T(t[0],t[1],t[2]):t.length===2?new T(t[0],t[1]):null:t==null?t:typeof t=="object"&&"lat"in t?new T(t.lat,"lng"in t?t.lng:t.lon,t.alt):e===void 0?null:new T(t,e,i)}var at={latLngToPoint:function(t,e){var i=this.projection.project(t),o=this.scale(e);return this.transformation._transform(i,o)},pointToLatLng:function(t,e){var i=this.scale(e),o=this.transformation.untransform(t,i);return this.projection.unproject(o)},project:function(t){return this.projection.project(t)},unproject:function(t){return this.projection.unproject(t)},scale:function(t){return 256*Math.pow(2,t)},zoom:function(t){return Math.log(t/256)/Math.LN2},getProjectedBounds:function(t){if(this.infinite)return null;var e=this.projection.bounds,i=this.scale(t),o=this.transformation.transform(e.min,i),n=this.transformation.transform(e.max,i);return new E(o,n)},infinite:!1,wrapLatLng:function(t){var e=this.wrapLng?Mt(t.lng,this.wrapLng,!0):t.lng,i=this.wrapLat?Mt(t.lat,this.wrapLat,!0):t.lat,o=t.alt;return new T(i,e,o)},wrapLatLngBounds:function(t){var e=t.getCenter(),i=this.wrapLatLng(e),o=e.lat-i.lat,n=e.lng-i.lng;if(o===0&&n===0)return t;var r=t.getSouthWest(),s=t.getNorthEast(),a=new T(r.lat-o,r.lng-n),h=new T(s.lat-o,s.lng-n);return new A(a,h)}};var tt=b({},at,{wrapLng:[-180,180],R:6371e3,distance:function(t,e){var i=Math.PI/180,o=t.lat*i,n=e.lat*i,r=Math.sin((e.lat-t.lat)*i/2),s=Math.sin((e.lng-t.lng)*i/2),a=r*r+Math.cos(o)*Math.cos(n)*s*s,h=2*Math.atan2(Math.sqrt(a),Math.sqrt(1-a));return this.R*h}});var Yi=6378137,Qt={R:Yi,MAX_LATITUDE:85.0511287798,project:function(t){var e=Math.PI/180,i=this.MAX_LATITUDE,o=Math.max(Math.min(i,t.lat),-i),n=Math.sin(o*e);return new c(this.R*t.lng*e,this.R*Math.log((1+n)/(1-n))/2)},unproject:function(t){var e=180/Math.PI;return new T((2*Math.atan(Math.exp(t.y/this.R))-Math.PI/2)*e,t.x*e/this.R)},bounds:function(){var t=Yi*Math.PI;return new E([-t,-t],[t,t])}()};function ye(t,e,i,o){if(I(t)){this._a=t[0],this._b=t[1],this._c=t[2],this._d=t[3];return}this._a=t,this._b=e,this._c=i,this._d=o}ye.prototype={transform:function(t,e){return this._transform(t.clone(),e)},_transform:function(t,e){return e=e||1,t.x=e*(this._a*t.x+this._b),t.y=e*(this._c*t.y+this._d),t},untransform:function(t,e){return e=e||1,new c((t.x/e-this._b)/this._a,(t.y/e-this._d)/this._c)}};function vt(t,e,i,o){return new ye(t,e,i,o)}var te=b({},tt,{code:"EPSG:3857",projection:Qt,transformation:function(){var t=.5/(Math.PI*Qt.R);return vt(t,.5,-t,.5)}()}),Xi=b({},te,{code:"EPSG:900913"});function xe(t){return document.createElementNS("http://www.w3.org/2000/svg",t)}function we(t,e){var i="",o,n,r,s,a,h;for(o=0,r=t.length;o<r;o++){for(a=t[o],n=0,s=a.length;n<s;n++)h=a[n],i+=(n?"L":"M")+h.x+" "+h.y;i+=e?u.svg?"z":"x":""}return i||"M0 0"}var Ke=document.documentElement.style,Le="ActiveXObject"in window,dn=Le&&!document.addEventListener,Ji="msLaunchUri"in navigator&&!("documentMode"in document),Ye=gt("webkit"),$i=gt("android"),Qi=gt("android 2")||gt("android 3"),pn=parseInt(/WebKit\/([0-9]+)|$/.exec(navigator.userAgent)[1],10),_n=$i&&gt("Google")&&pn<537&&!("AudioNode"in window),Xe=!!window.opera,to=!Ji&&gt("chrome"),eo=gt("gecko")&&!Ye&&!Xe&&!Le,vn=!to&&gt("safari"),io=gt("phantom"),oo="OTransition"in Ke,gn=navigator.platform.indexOf("Win")===0,no=Le&&"transition"in Ke,Je="WebKitCSSMatrix"in window&&"m11"in new window.WebKitCSSMatrix&&!Qi,ro="MozPerspective"in Ke,yn=!window.L_DISABLE_3D&&(no||Je||ro)&&!oo&&!io,ee=typeof orientation!="undefined"||gt("mobile"),xn=ee&&Ye,wn=ee&&Je,so=!window.PointerEvent&&window.MSPointerEvent,ao=!!(window.PointerEvent||so),ho="ontouchstart"in window||!!window.TouchEvent,Ln=!window.L_NO_TOUCH&&(ho||ao),Pn=ee&&Xe,bn=ee&&eo,Tn=(window.devicePixelRatio||window.screen.deviceXDPI/window.screen.logicalXDPI)>1,Dn=function(){var t=!1;try{var e=Object.defineProperty({},"passive",{get:function(){t=!0}});window.addEventListener("testPassiveEventSupport",z,e),window.removeEventListener("testPassiveEventSupport",z,e)}catch(i){}return t}(),Un=function(){return!!document.createElement("canvas").getContext}(),$e=!!(document.createElementNS&&xe("svg").createSVGRect),En=!!$e&&function(){var t=document.createElement("div");return t.innerHTML="<svg/>",(t.firstChild&&t.firstChild.namespaceURI)==="http://www.w3.org/2000/svg"}(),Cn=!$e&&function(){try{var t=document.createElement("div");t.innerHTML='<v:shape adj="1"/>';var e=t.firstChild;return e.style.behavior="url(#default#VML)",e&&typeof e.adj=="object"}catch(i){return!1}}(),Mn=navigator.platform.indexOf("Mac")===0,Sn=navigator.platform.indexOf("Linux")===0;function gt(t){return navigator.userAgent.toLowerCase().indexOf(t)>=0}var u={ie:Le,ielt9:dn,edge:Ji,webkit:Ye,android:$i,android23:Qi,androidStock:_n,opera:Xe,chrome:to,gecko:eo,safari:vn,phantom:io,opera12:oo,win:gn,ie3d:no,webkit3d:Je,gecko3d:ro,any3d:yn,mobile:ee,mobileWebkit:xn,mobileWebkit3d:wn,msPointer:so,pointer:ao,touch:Ln,touchNative:ho,mobileOpera:Pn,mobileGecko:bn,retina:Tn,passiveEvents:Dn,canvas:Un,svg:$e,vml:Cn,inlineSvg:En,mac:Mn,linux:Sn};var R={};Ct(R,{addListener:()=>p,disableClickPropagation:()=>wt,disableScrollPropagation:()=>se,getMousePosition:()=>ui,getPropagationPath:()=>ti,getWheelDelta:()=>mi,isExternalTarget:()=>Me,off:()=>C,on:()=>p,preventDefault:()=>Z,removeListener:()=>C,stop:()=>lt,stopPropagation:()=>Dt});var fo=u.msPointer?"MSPointerDown":"pointerdown",co=u.msPointer?"MSPointerMove":"pointermove",po=u.msPointer?"MSPointerUp":"pointerup",_o=u.msPointer?"MSPointerCancel":"pointercancel",Qe={touchstart:fo,touchmove:co,touchend:po,touchcancel:_o},lo={touchstart:Bn,touchmove:Pe,touchend:Pe,touchcancel:Pe},Rt={},uo=!1;function vo(t,e,i){return e==="touchstart"&&On(),lo[e]?(i=lo[e].bind(this,i),t.addEventListener(Qe[e],i,!1),i):(console.warn("wrong event specified:",e),z)}function go(t,e,i){if(!Qe[e]){console.warn("wrong event specified:",e);return}t.removeEventListener(Qe[e],i,!1)}function kn(t){Rt[t.pointerId]=t}function zn(t){Rt[t.pointerId]&&(Rt[t.pointerId]=t)}function mo(t){delete Rt[t.pointerId]}function On(){uo||(document.addEventListener(fo,kn,!0),document.addEventListener(co,zn,!0),document.addEventListener(po,mo,!0),document.addEventListener(_o,mo,!0),uo=!0)}function Pe(t,e){if(e.pointerType!==(e.MSPOINTER_TYPE_MOUSE||"mouse")){e.touches=[];for(var i in Rt)e.touches.push(Rt[i]);e.changedTouches=[e],t(e)}}function Bn(t,e){e.MSPOINTER_TYPE_TOUCH&&e.pointerType===e.MSPOINTER_TYPE_TOUCH&&Z(e),Pe(t,e)}function Zn(t){var e={},i,o;for(o in t)i=t[o],e[o]=i&&i.bind?i.bind(t):i;return t=e,e.type="dblclick",e.detail=2,e.isTrusted=!1,e._simulated=!0,e}var An=200;function yo(t,e){t.addEventListener("dblclick",e);var i=0,o;function n(r){if(r.detail!==1){o=r.detail;return}if(!(r.pointerType==="mouse"||r.sourceCapabilities&&!r.sourceCapabilities.firesTouchEvents)){var s=ti(r);if(!(s.some(function(h){return h instanceof HTMLLabelElement&&h.attributes.for})&&!s.some(function(h){return h instanceof HTMLInputElement||h instanceof HTMLSelectElement}))){var a=Date.now();a-i<=An?(o++,o===2&&e(Zn(r))):o=1,i=a}}}return t.addEventListener("click",n),{dblclick:e,simDblclick:n}}function xo(t,e){t.removeEventListener("dblclick",e.dblclick),t.removeEventListener("click",e.simDblclick)}var B={};Ct(B,{TRANSFORM:()=>De,TRANSITION:()=>Ht,TRANSITION_END:()=>oi,addClass:()=>f,create:()=>y,disableImageDrag:()=>ie,disableTextSelection:()=>St,empty:()=>Wt,enableImageDrag:()=>oe,enableTextSelection:()=>kt,get:()=>ni,getClass:()=>Ee,getPosition:()=>ht,getScale:()=>re,getSizedParentNode:()=>si,getStyle:()=>Tt,hasClass:()=>Ue,preventOutline:()=>ne,remove:()=>M,removeClass:()=>k,restoreOutline:()=>Te,setClass:()=>ri,setOpacity:()=>V,setPosition:()=>S,setTransform:()=>ct,testProp:()=>Ce,toBack:()=>ft,toFront:()=>mt});var De=Ce(["transform","webkitTransform","OTransform","MozTransform","msTransform"]),Ht=Ce(["webkitTransition","transition","OTransition","MozTransition","msTransition"]),oi=Ht==="webkitTransition"||Ht==="OTransition"?Ht+"End":"transitionend";function ni(t){return typeof t=="string"?document.getElementById(t):t}function Tt(t,e){var i=t.style[e]||t.currentStyle&&t.currentStyle[e];if((!i||i==="auto")&&document.defaultView){var o=document.defaultView.getComputedStyle(t,null);i=o?o[e]:null}return i==="auto"?null:i}function y(t,e,i){var o=document.createElement(t);return o.className=e||"",i&&i.appendChild(o),o}function M(t){var e=t.parentNode;e&&e.removeChild(t)}function Wt(t){for(;t.firstChild;)t.removeChild(t.firstChild)}function mt(t){var e=t.parentNode;e&&e.lastChild!==t&&e.appendChild(t)}function ft(t){var e=t.parentNode;e&&e.firstChild!==t&&e.insertBefore(t,e.firstChild)}function Ue(t,e){if(t.classList!==void 0)return t.classList.contains(e);var i=Ee(t);return i.length>0&&new RegExp("(^|\\s)"+e+"(\\s|$)").test(i)}function f(t,e){if(t.classList!==void 0)for(var i=pt(e),o=0,n=i.length;o<n;o++)t.classList.add(i[o]);else if(!Ue(t,e)){var r=Ee(t);ri(t,(r?r+" ":"")+e)}}function k(t,e){t.classList!==void 0?t.classList.remove(e):ri(t,ge((" "+Ee(t)+" ").replace(" "+e+" "," ")))}function ri(t,e){t.className.baseVal===void 0?t.className=e:t.className.baseVal=e}function Ee(t){return t.correspondingElement&&(t=t.correspondingElement),t.className.baseVal===void 0?t.className:t.className.baseVal}function V(t,e){"opacity"in t.style?t.style.opacity=e:"filter"in t.style&&In(t,e)}function In(t,e){var i=!1,o="DXImageTransform.Microsoft.Alpha";try{i=t.filters.item(o)}catch(n){if(e===1)return}e=Math.round(e*100),i?(i.Enabled=e!==100,i.Opacity=e):t.style.filter+=" progid:"+o+"(opacity="+e+")"}function Ce(t){for(var e=document.documentElement.style,i=0;i<t.length;i++)if(t[i]in e)return t[i];return!1}function ct(t,e,i){var o=e||new c(0,0);t.style[De]=(u.ie3d?"translate("+o.x+"px,"+o.y+"px)":"translate3d("+o.x+"px,"+o.y+"px,0)")+(i?" scale("+i+")":"")}function S(t,e){t._leaflet_pos=e,u.any3d?ct(t,e):(t.style.left=e.x+"px",t.style.top=e.y+"px")}function ht(t){return t._leaflet_pos||new c(0,0)}var St,kt,ei;"onselectstart"in document?(St=function(){p(window,"selectstart",Z)},kt=function(){C(window,"selectstart",Z)}):(Ft=Ce(["userSelect","WebkitUserSelect","OUserSelect","MozUserSelect","msUserSelect"]),St=function(){if(Ft){var t=document.documentElement.style;ei=t[Ft],t[Ft]="none"}},kt=function(){Ft&&(document.documentElement.style[Ft]=ei,ei=void 0)});var Ft;function ie(){p(window,"dragstart",Z)}function oe(){C(window,"dragstart",Z)}var be,ii;function ne(t){for(;t.tabIndex===-1;)t=t.parentNode;t.style&&(Te(),be=t,ii=t.style.outlineStyle,t.style.outlineStyle="none",p(window,"keydown",Te))}function Te(){be&&(be.style.outlineStyle=ii,be=void 0,ii=void 0,C(window,"keydown",Te))}function si(t){do t=t.parentNode;while((!t.offsetWidth||!t.offsetHeight)&&t!==document.body);return t}function re(t){var e=t.getBoundingClientRect();return{x:e.width/t.offsetWidth||1,y:e.height/t.offsetHeight||1,boundingClientRect:e}}function p(t,e,i,o){if(e&&typeof e=="object")for(var n in e)hi(t,n,e[n],i);else{e=pt(e);for(var r=0,s=e.length;r<s;r++)hi(t,e[r],i,o)}return this}var yt="_leaflet_events";function C(t,e,i,o){if(arguments.length===1)wo(t),delete t[yt];else if(e&&typeof e=="object")for(var n in e)li(t,n,e[n],i);else if(e=pt(e),arguments.length===2)wo(t,function(a){return Jt(e,a)!==-1});else for(var r=0,s=e.length;r<s;r++)li(t,e[r],i,o);return this}function wo(t,e){for(var i in t[yt]){var o=i.split(/\d/)[0];(!e||e(o))&&li(t,o,null,null,i)}}var ai={mouseenter:"mouseover",mouseleave:"mouseout",wheel:!("onwheel"in window)&&"mousewheel"};function hi(t,e,i,o){var n=e+P(i)+(o?"_"+P(o):"");if(t[yt]&&t[yt][n])return this;var r=function(a){return i.call(o||t,a||window.event)},s=r;!u.touchNative&&u.pointer&&e.indexOf("touch")===0?r=vo(t,e,r):u.touch&&e==="dblclick"?r=yo(t,r):"addEventListener"in t?e==="touchstart"||e==="touchmove"||e==="wheel"||e==="mousewheel"?t.addEventListener(ai[e]||e,r,u.passiveEvents?{passive:!1}:!1):e==="mouseenter"||e==="mouseleave"?(r=function(a){a=a||window.event,Me(t,a)&&s(a)},t.addEventListener(ai[e],r,!1)):t.addEventListener(e,s,!1):t.attachEvent("on"+e,r),t[yt]=t[yt]||{},t[yt][n]=r}function li(t,e,i,o,n){n=n||e+P(i)+(o?"_"+P(o):"");var r=t[yt]&&t[yt][n];if(!r)return this;!u.touchNative&&u.pointer&&e.indexOf("touch")===0?go(t,e,r):u.touch&&e==="dblclick"?xo(t,r):"removeEventListener"in t?t.removeEventListener(ai[e]||e,r,!1):t.detachEvent("on"+e,r),t[yt][n]=null}function Dt(t){return t.stopPropagation?t.stopPropagation():t.originalEvent?t.originalEvent._stopped=!0:t.cancelBubble=!0,this}function se(t){return hi(t,"wheel",Dt),this}function wt(t){return p(t,"mousedown touchstart dblclick contextmenu",Dt),t._leaflet_disable_click=!0,this}function Z(t){return t.preventDefault?t.preventDefault():t.returnValue=!1,this}function lt(t){return Z(t),Dt(t),this}function ti(t){if(t.composedPath)return t.composedPath();for(var e=[],i=t.target;i;)e.push(i),i=i.parentNode;return e}function ui(t,e){if(!e)return new c(t.clientX,t.clientY);var i=re(e),o=i.boundingClientRect;return new c((t.clientX-o.left)/i.x-e.clientLeft,(t.clientY-o.top)/i.y-e.clientTop)}var Nn=u.linux&&u.chrome?window.devicePixelRatio:u.mac?window.devicePixelRatio*3:window.devicePixelRatio>0?2*window.devicePixelRatio:1;function mi(t){return u.edge?t.wheelDeltaY/2:t.deltaY&&t.deltaMode===0?-t.deltaY/Nn:t.deltaY&&t.deltaMode===1?-t.deltaY*20:t.deltaY&&t.deltaMode===2?-t.deltaY*60:t.deltaX||t.deltaZ?0:t.wheelDelta?(t.wheelDeltaY||t.wheelDelta)/2:t.detail&&Math.abs(t.detail)<32765?-t.detail*20:t.detail?t.detail/-32765*60:0}function Me(t,e){var i=e.relatedTarget;if(!i)return!0;try{for(;i&&i!==t;)i=i.parentNode}catch(o){return!1}return i!==t}var Se=_t.extend({run:function(t,e,i,o){this.stop(),this._el=t,this._inProgress=!0,this._duration=i||.25,this._easeOutPower=1/Math.max(o||.5,.2),this._startPos=ht(t),this._offset=e.subtract(this._startPos),this._startTime=+new Date,this.fire("start"),this._animate()},stop:function(){this._inProgress&&(this._step(!0),this._complete())},_animate:function(){this._animId=N(this._animate,this),this._step()},_step:function(t){var e=+new Date-this._startTime,i=this._duration*1e3;e<i?this._runFrame(this._easeOut(e/i),t):(this._runFrame(1),this._complete())},_runFrame:function(t,e){var i=this._startPos.add(this._offset.multiplyBy(t));e&&i._round(),S(this._el,i),this.fire("step")},_complete:function(){j(this._animId),this._inProgress=!1,this.fire("end")},_easeOut:function(t){return 1-Math.pow(1-t,this._easeOutPower)}});var _=_t.extend({options:{crs:te,center:void 0,zoom:void 0,minZoom:void 0,maxZoom:void 0,layers:[],maxBounds:void 0,renderer:void 0,zoomAnimation:!0,zoomAnimationThreshold:4,fadeAnimation:!0,markerZoomAnimation:!0,transform3DLimit:8388608,zoomSnap:1,zoomDelta:1,trackResize:!0},initialize:function(t,e){e=x(this,e),this._handlers=[],this._layers={},this._zoomBoundLayers={},this._sizeChanged=!0,this._initContainer(t),this._initLayout(),this._onResize=U(this._onResize,this),this._initEvents(),e.maxBounds&&this.setMaxBounds(e.maxBounds),e.zoom!==void 0&&(this._zoom=this._limitZoom(e.zoom)),e.center&&e.zoom!==void 0&&this.setView(w(e.center),e.zoom,{reset:!0}),this.callInitHooks(),this._zoomAnimated=Ht&&u.any3d&&!u.mobileOpera&&this.options.zoomAnimation,this._zoomAnimated&&(this._createAnimProxy(),p(this._proxy,oi,this._catchTransitionEnd,this)),this._addLayers(this.options.layers)},setView:function(t,e,i){if(e=e===void 0?this._zoom:this._limitZoom(e),t=this._limitCenter(w(t),e,this.options.maxBounds),i=i||{},this._stop(),this._loaded&&!i.reset&&i!==!0){i.animate!==void 0&&(i.zoom=b({animate:i.animate},i.zoom),i.pan=b({animate:i.animate,duration:i.duration},i.pan));var o=this._zoom!==e?this._tryAnimatedZoom&&this._tryAnimatedZoom(t,e,i.zoom):this._tryAnimatedPan(t,i.pan);if(o)return clearTimeout(this._sizeTimer),this}return this._resetView(t,e,i.pan&&i.pan.noMoveStart),this},setZoom:function(t,e){return this._loaded?this.setView(this.getCenter(),t,{zoom:e}):(this._zoom=t,this)},zoomIn:function(t,e){return t=t||(u.any3d?this.options.zoomDelta:1),this.setZoom(this._zoom+t,e)},zoomOut:function(t,e){return t=t||(u.any3d?this.options.zoomDelta:1),this.setZoom(this._zoom-t,e)},setZoomAround:function(t,e,i){var o=this.getZoomScale(e),n=this.getSize().divideBy(2),r=t instanceof c?t:this.latLngToContainerPoint(t),s=r.subtract(n).multiplyBy(1-1/o),a=this.containerPointToLatLng(n.add(s));return this.setView(a,e,{zoom:i})},_getBoundsCenterZoom:function(t,e){e=e||{},t=t.getBounds?t.getBounds():O(t);var i=d(e.paddingTopLeft||e.padding||[0,0]),o=d(e.paddingBottomRight||e.padding||[0,0]),n=this.getBoundsZoom(t,!1,i.add(o));if(n=typeof e.maxZoom=="number"?Math.min(e.maxZoom,n):n,n===1/0)return{center:t.getCenter(),zoom:n};var r=o.subtract(i).divideBy(2),s=this.project(t.getSouthWest(),n),a=this.project(t.getNorthEast(),n),h=this.unproject(s.add(a).divideBy(2).add(r),n);return{center:h,zoom:n}},fitBounds:function(t,e){if(t=O(t),!t.isValid())throw new Error("Bounds are not valid.");var i=this._getBoundsCenterZoom(t,e);return this.setView(i.center,i.zoom,e)},fitWorld:function(t){return this.fitBounds([[-90,-180],[90,180]],t)},panTo:function(t,e){return this.setView(t,this._zoom,{pan:e})},panBy:function(t,e){if(t=d(t).round(),e=e||{},!t.x&&!t.y)return this.fire("moveend");if(e.animate!==!0&&!this.getSize().contains(t))return this._resetView(this.unproject(this.project(this.getCenter()).add(t)),this.getZoom()),this;if(this._panAnim||(this._panAnim=new Se,this._panAnim.on({step:this._onPanTransitionStep,end:this._onPanTransitionEnd},this)),e.noMoveStart||this.fire("movestart"),e.animate!==!1){f(this._mapPane,"leaflet-pan-anim");var i=this._getMapPanePos().subtract(t).round();this._panAnim.run(this._mapPane,i,e.duration||.25,e.easeLinearity)}else this._rawPanBy(t),this.fire("move").fire("moveend");return this},flyTo:function(t,e,i){if(i=i||{},i.animate===!1||!u.any3d)return this.setView(t,e,i);this._stop();var o=this.project(this.getCenter()),n=this.project(t),r=this.getSize(),s=this._zoom;t=w(t),e=e===void 0?s:e;var a=Math.max(r.x,r.y),h=a*this.getZoomScale(s,e),l=n.distanceTo(o)||1,m=1.42,v=m*m;function D(H){var ve=H?-1:1,en=H?h:a,on=h*h-a*a+ve*v*v*l*l,nn=2*en*v*l,Fe=on/nn,Hi=Math.sqrt(Fe*Fe+1)-Fe,rn=Hi<1e-9?-18:Math.log(Hi);return rn}function X(H){return(Math.exp(H)-Math.exp(-H))/2}function q(H){return(Math.exp(H)+Math.exp(-H))/2}function st(H){return X(H)/q(H)}var J=D(0);function Nt(H){return a*(q(J)/q(J+m*H))}function Jo(H){return a*(q(J)*st(J+m*H)-X(J))/v}function $o(H){return 1-Math.pow(1-H,1.5)}var Qo=Date.now(),Ri=(D(1)-J)/m,tn=i.duration?1e3*i.duration:1e3*Ri*.8;function Fi(){var H=(Date.now()-Qo)/tn,ve=$o(H)*Ri;H<=1?(this._flyToFrame=N(Fi,this),this._move(this.unproject(o.add(n.subtract(o).multiplyBy(Jo(ve)/l)),s),this.getScaleZoom(a/Nt(ve),s),{flyTo:!0})):this._move(t,e)._moveEnd(!0)}return this._moveStart(!0,i.noMoveStart),Fi.call(this),this},flyToBounds:function(t,e){var i=this._getBoundsCenterZoom(t,e);return this.flyTo(i.center,i.zoom,e)},setMaxBounds:function(t){return t=O(t),this.listens("moveend",this._panInsideMaxBounds)&&this.off("moveend",this._panInsideMaxBounds),t.isValid()?(this.options.maxBounds=t,this._loaded&&this._panInsideMaxBounds(),this.on("moveend",this._panInsideMaxBounds)):(this.options.maxBounds=null,this)},setMinZoom:function(t){var e=this.options.minZoom;return this.options.minZoom=t,this._loaded&&e!==t&&(this.fire("zoomlevelschange"),this.getZoom()<this.options.minZoom)?this.setZoom(t):this},setMaxZoom:function(t){var e=this.options.maxZoom;return this.options.maxZoom=t,this._loaded&&e!==t&&(this.fire("zoomlevelschange"),this.getZoom()>this.options.maxZoom)?this.setZoom(t):this},panInsideBounds:function(t,e){this._enforcingBounds=!0;var i=this.getCenter(),o=this._limitCenter(i,this._zoom,O(t));return i.equals(o)||this.panTo(o,e),this._enforcingBounds=!1,this},panInside:function(t,e){e=e||{};var i=d(e.paddingTopLeft||e.padding||[0,0]),o=d(e.paddingBottomRight||e.padding||[0,0]),n=this.project(this.getCenter()),r=this.project(t),s=this.getPixelBounds(),a=W([s.min.add(i),s.max.subtract(o)]),h=a.getSize();if(!a.contains(r)){this._enforcingBounds=!0;var l=r.subtract(a.getCenter()),m=a.extend(r).getSize().subtract(h);n.x+=l.x<0?-m.x:m.x,n.y+=l.y<0?-m.y:m.y,this.panTo(this.unproject(n),e),this._enforcingBounds=!1}return this},invalidateSize:function(t){if(!this._loaded)return this;t=b({animate:!1,pan:!0},t===!0?{animate:!0}:t);var e=this.getSize();this._sizeChanged=!0,this._lastCenter=null;var i=this.getSize(),o=e.divideBy(2).round(),n=i.divideBy(2).round(),r=o.subtract(n);return!r.x&&!r.y?this:(t.animate&&t.pan?this.panBy(r):(t.pan&&this._rawPanBy(r),this.fire("move"),t.debounceMoveend?(clearTimeout(this._sizeTimer),this._sizeTimer=setTimeout(U(this.fire,this,"moveend"),200)):this.fire("moveend")),this.fire("resize",{oldSize:e,newSize:i}))},stop:function(){return this.setZoom(this._limitZoom(this._zoom)),this.options.zoomSnap||this.fire("viewreset"),this._stop()},locate:function(t){if(t=this._locateOptions=b({timeout:1e4,watch:!1},t),!("geolocation"in navigator))return this._handleGeolocationError({code:0,message:"Geolocation not supported."}),this;var e=U(this._handleGeolocationResponse,this),i=U(this._handleGeolocationError,this);return t.watch?this._locationWatchId=navigator.geolocation.watchPosition(e,i,t):navigator.geolocation.getCurrentPosition(e,i,t),this},stopLocate:function(){return navigator.geolocation&&navigator.geolocation.clearWatch&&navigator.geolocation.clearWatch(this._locationWatchId),this._locateOptions&&(this._locateOptions.setView=!1),this},_handleGeolocationError:function(t){if(this._container._leaflet_id){var e=t.code,i=t.message||(e===1?"permission denied":e===2?"position unavailable":"timeout");this._locateOptions.setView&&!this._loaded&&this.fitWorld(),this.fire("locationerror",{code:e,message:"Geolocation error: "+i+"."})}},_handleGeolocationResponse:function(t){if(this._container._leaflet_id){var e=t.coords.latitude,i=t.coords.longitude,o=new T(e,i),n=o.toBounds(t.coords.accuracy*2),r=this._locateOptions;if(r.setView){var s=this.getBoundsZoom(n);this.setView(o,r.maxZoom?Math.min(s,r.maxZoom):s)}var a={latlng:o,bounds:n,timestamp:t.timestamp};for(var h in t.coords)typeof t.coords[h]=="number"&&(a[h]=t.coords[h]);this.fire("locationfound",a)}},addHandler:function(t,e){if(!e)return this;var i=this[t]=new e(this);return this._handlers.push(i),this.options[t]&&i.enable(),this},remove:function(){if(this._initEvents(!0),this.options.maxBounds&&this.off("moveend",this._panInsideMaxBounds),this._containerId!==this._container._leaflet_id)throw new Error("Map container is being reused by another instance");try{delete this._container._leaflet_id,delete this._containerId}catch(e){this._container._leaflet_id=void 0,this._containerId=void 0}this._locationWatchId!==void 0&&this.stopLocate(),this._stop(),M(this._mapPane),this._clearControlPos&&this._clearControlPos(),this._resizeRequest&&(j(this._resizeRequest),this._resizeRequest=null),this._clearHandlers(),this._loaded&&this.fire("unload");var t;for(t in this._layers)this._layers[t].remove();for(t in this._panes)M(this._panes[t]);return this._layers=[],this._panes=[],delete this._mapPane,delete this._renderer,this},createPane:function(t,e){var i="leaflet-pane"+(t?" leaflet-"+t.replace("Pane","")+"-pane":""),o=y("div",i,e||this._mapPane);return t&&(this._panes[t]=o),o},getCenter:function(){return this._checkIfLoaded(),this._lastCenter&&!this._moved()?this._lastCenter.clone():this.layerPointToLatLng(this._getCenterLayerPoint())},getZoom:function(){return this._zoom},getBounds:function(){var t=this.getPixelBounds(),e=this.unproject(t.getBottomLeft()),i=this.unproject(t.getTopRight());return new A(e,i)},getMinZoom:function(){return this.options.minZoom===void 0?this._layersMinZoom||0:this.options.minZoom},getMaxZoom:function(){return this.options.maxZoom===void 0?this._layersMaxZoom===void 0?1/0:this._layersMaxZoom:this.options.maxZoom},getBoundsZoom:function(t,e,i){t=O(t),i=d(i||[0,0]);var o=this.getZoom()||0,n=this.getMinZoom(),r=this.getMaxZoom(),s=t.getNorthWest(),a=t.getSouthEast(),h=this.getSize().subtract(i),l=W(this.project(a,o),this.project(s,o)).getSize(),m=u.any3d?this.options.zoomSnap:1,v=h.x/l.x,D=h.y/l.y,X=e?Math.max(v,D):Math.min(v,D);return o=this.getScaleZoom(X,o),m&&(o=Math.round(o/(m/100))*(m/100),o=e?Math.ceil(o/m)*m:Math.floor(o/m)*m),Math.max(n,Math.min(r,o))},getSize:function(){return(!this._size||this._sizeChanged)&&(this._size=new c(this._container.clientWidth||0,this._container.clientHeight||0),this._sizeChanged=!1),this._size.clone()},getPixelBounds:function(t,e){var i=this._getTopLeftPoint(t,e);return new E(i,i.add(this.getSize()))},getPixelOrigin:function(){return this._checkIfLoaded(),this._pixelOrigin},getPixelWorldBounds:function(t){return this.options.crs.getProjectedBounds(t===void 0?this.getZoom():t)},getPane:function(t){return typeof t=="string"?this._panes[t]:t},getPanes:function(){return this._panes},getContainer:function(){return this._container},getZoomScale:function(t,e){var i=this.options.crs;return e=e===void 0?this._zoom:e,i.scale(t)/i.scale(e)},getScaleZoom:function(t,e){var i=this.options.crs;e=e===void 0?this._zoom:e;var o=i.zoom(t*i.scale(e));return isNaN(o)?1/0:o},project:function(t,e){return e=e===void 0?this._zoom:e,this.options.crs.latLngToPoint(w(t),e)},unproject:function(t,e){return e=e===void 0?this._zoom:e,this.options.crs.pointToLatLng(d(t),e)},layerPointToLatLng:function(t){var e=d(t).add(this.getPixelOrigin());return this.unproject(e)},latLngToLayerPoint:function(t){var e=this.project(w(t))._round();return e._subtract(this.getPixelOrigin())},wrapLatLng:function(t){return this.options.crs.wrapLatLng(w(t))},wrapLatLngBounds:function(t){return this.options.crs.wrapLatLngBounds(O(t))},distance:function(t,e){return this.options.crs.distance(w(t),w(e))},containerPointToLayerPoint:function(t){return d(t).subtract(this._getMapPanePos())},layerPointToContainerPoint:function(t){return d(t).add(this._getMapPanePos())},containerPointToLatLng:function(t){var e=this.containerPointToLayerPoint(d(t));return this.layerPointToLatLng(e)},latLngToContainerPoint:function(t){return this.layerPointToContainerPoint(this.latLngToLayerPoint(w(t)))},mouseEventToContainerPoint:function(t){return ui(t,this._container)},mouseEventToLayerPoint:function(t){return this.containerPointToLayerPoint(this.mouseEventToContainerPoint(t))},mouseEventToLatLng:function(t){return this.layerPointToLatLng(this.mouseEventToLayerPoint(t))},_initContainer:function(t){var e=this._container=ni(t);if(e){if(e._leaflet_id)throw new Error("Map container is already initialized.")}else throw new Error("Map container not found.");p(e,"scroll",this._onScroll,this),this._containerId=P(e)},_initLayout:function(){var t=this._container;this._fadeAnimated=this.options.fadeAnimation&&u.any3d,f(t,"leaflet-container"+(u.touch?" leaflet-touch":"")+(u.retina?" leaflet-retina":"")+(u.ielt9?" leaflet-oldie":"")+(u.safari?" leaflet-safari":"")+(this._fadeAnimated?" leaflet-fade-anim":""));var e=Tt(t,"position");e!=="absolute"&&e!=="relative"&&e!=="fixed"&&e!=="sticky"&&(t.style.position="relative"),this._initPanes(),this._initControlPos&&this._initControlPos()},_initPanes:function(){var t=this._panes={};this._paneRenderers={},this._mapPane=this.createPane("mapPane",this._container),S(this._mapPane,new c(0,0)),this.createPane("tilePane"),this.createPane("overlayPane"),this.createPane("shadowPane"),this.createPane("markerPane"),this.createPane("tooltipPane"),this.createPane("popupPane"),this.options.markerZoomAnimation||(f(t.markerPane,"leaflet-zoom-hide"),f(t.shadowPane,"leaflet-zoom-hide"))},_resetView:function(t,e,i){S(this._mapPane,new c(0,0));var o=!this._loaded;this._loaded=!0,e=this._limitZoom(e),this.fire("viewprereset");var n=this._zoom!==e;this._moveStart(n,i)._move(t,e)._moveEnd(n),this.fire("viewreset"),o&&this.fire("load")},_moveStart:function(t,e){return t&&this.fire("zoomstart"),e||this.fire("movestart"),this},_move:function(t,e,i,o){e===void 0&&(e=this._zoom);var n=this._zoom!==e;return this._zoom=e,this._lastCenter=t,this._pixelOrigin=this._getNewPixelOrigin(t),o?i&&i.pinch&&this.fire("zoom",i):((n||i&&i.pinch)&&this.fire("zoom",i),this.fire("move",i)),this},_moveEnd:function(t){return t&&this.fire("zoomend"),this.fire("moveend")},_stop:function(){return j(this._flyToFrame),this._panAnim&&this._panAnim.stop(),this},_rawPanBy:function(t){S(this._mapPane,this._getMapPanePos().subtract(t))},_getZoomSpan:function(){return this.getMaxZoom()-this.getMinZoom()},_panInsideMaxBounds:function(){this._enforcingBounds||this.panInsideBounds(this.options.maxBounds)},_checkIfLoaded:function(){if(!this._loaded)throw new Error("Set map center and zoom first.")},_initEvents:function(t){this._targets={},this._targets[P(this._container)]=this;var e=t?C:p;e(this._container,"click dblclick mousedown mouseup mouseover mouseout mousemove contextmenu keypress keydown keyup",this._handleDOMEvent,this),this.options.trackResize&&e(window,"resize",this._onResize,this),u.any3d&&this.options.transform3DLimit&&(t?this.off:this.on).call(this,"moveend",this._onMoveEnd)},_onResize:function(){j(this._resizeRequest),this._resizeRequest=N(function(){this.invalidateSize({debounceMoveend:!0})},this)},_onScroll:function(){this._container.scrollTop=0,this._container.scrollLeft=0},_onMoveEnd:function(){var t=this._getMapPanePos();Math.max(Math.abs(t.x),Math.abs(t.y))>=this.options.transform3DLimit&&this._resetView(this.getCenter(),this.getZoom())},_findEventTargets:function(t,e){for(var i=[],o,n=e==="mouseout"||e==="mouseover",r=t.target||t.srcElement,s=!1;r;){if(o=this._targets[P(r)],o&&(e==="click"||e==="preclick")&&this._draggableMoved(o)){s=!0;break}if(o&&o.listens(e,!0)&&(n&&!Me(r,t)||(i.push(o),n))||r===this._container)break;r=r.parentNode}return!i.length&&!s&&!n&&this.listens(e,!0)&&(i=[this]),i},_isClickDisabled:function(t){for(;t&&t!==this._container;){if(t._leaflet_disable_click)return!0;t=t.parentNode}},_handleDOMEvent:function(t){var e=t.target||t.srcElement;if(!(!this._loaded||e._leaflet_disable_events||t.type==="click"&&this._isClickDisabled(e))){var i=t.type;i==="mousedown"&&ne(e),this._fireDOMEvent(t,i)}},_mouseEvents:["click","dblclick","mouseover","mouseout","contextmenu"],_fireDOMEvent:function(t,e,i){if(t.type==="click"){var o=b({},t);o.type="preclick",this._fireDOMEvent(o,o.type,i)}var n=this._findEventTargets(t,e);if(i){for(var r=[],s=0;s<i.length;s++)i[s].listens(e,!0)&&r.push(i[s]);n=r.concat(n)}if(n.length){e==="contextmenu"&&Z(t);var a=n[0],h={originalEvent:t};if(t.type!=="keypress"&&t.type!=="keydown"&&t.type!=="keyup"){var l=a.getLatLng&&(!a._radius||a._radius<=10);h.containerPoint=l?this.latLngToContainerPoint(a.getLatLng()):this.mouseEventToContainerPoint(t),h.layerPoint=this.containerPointToLayerPoint(h.containerPoint),h.latlng=l?a.getLatLng():this.layerPointToLatLng(h.layerPoint)}for(s=0;s<n.length;s++)if(n[s].fire(e,h,!0),h.originalEvent._stopped||n[s].options.bubblingMouseEvents===!1&&Jt(this._mouseEvents,e)!==-1)return}},_draggableMoved:function(t){return t=t.dragging&&t.dragging.enabled()?t:this,t.dragging&&t.dragging.moved()||this.boxZoom&&this.boxZoom.moved()},_clearHandlers:function(){for(var t=0,e=this._handlers.length;t<e;t++)this._handlers[t].disable()},whenReady:function(t,e){return this._loaded?t.call(e||this,{target:this}):this.on("load",t,e),this},_getMapPanePos:function(){return ht(this._mapPane)||new c(0,0)},_moved:function(){var t=this._getMapPanePos();return t&&!t.equals([0,0])},_getTopLeftPoint:function(t,e){var i=t&&e!==void 0?this._getNewPixelOrigin(t,e):this.getPixelOrigin();return i.subtract(this._getMapPanePos())},_getNewPixelOrigin:function(t,e){var i=this.getSize()._divideBy(2);return this.project(t,e)._subtract(i)._add(this._getMapPanePos())._round()},_latLngToNewLayerPoint:function(t,e,i){var o=this._getNewPixelOrigin(i,e);return this.project(t,e)._subtract(o)},_latLngBoundsToNewLayerBounds:function(t,e,i){var o=this._getNewPixelOrigin(i,e);return W([this.project(t.getSouthWest(),e)._subtract(o),this.project(t.getNorthWest(),e)._subtract(o),this.project(t.getSouthEast(),e)._subtract(o),this.project(t.getNorthEast(),e)._subtract(o)])},_getCenterLayerPoint:function(){return this.containerPointToLayerPoint(this.getSize()._divideBy(2))},_getCenterOffset:function(t){return this.latLngToLayerPoint(t).subtract(this._getCenterLayerPoint())},_limitCenter:function(t,e,i){if(!i)return t;var o=this.project(t,e),n=this.getSize().divideBy(2),r=new E(o.subtract(n),o.add(n)),s=this._getBoundsOffset(r,i,e);return Math.abs(s.x)<=1&&Math.abs(s.y)<=1?t:this.unproject(o.add(s),e)},_limitOffset:function(t,e){if(!e)return t;var i=this.getPixelBounds(),o=new E(i.min.add(t),i.max.add(t));return t.add(this._getBoundsOffset(o,e))},_getBoundsOffset:function(t,e,i){var o=W(this.project(e.getNorthEast(),i),this.project(e.getSouthWest(),i)),n=o.min.subtract(t.min),r=o.max.subtract(t.max),s=this._rebound(n.x,-r.x),a=this._rebound(n.y,-r.y);return new c(s,a)},_rebound:function(t,e){return t+e>0?Math.round(t-e)/2:Math.max(0,Math.ceil(t))-Math.max(0,Math.floor(e))},_limitZoom:function(t){var e=this.getMinZoom(),i=this.getMaxZoom(),o=u.any3d?this.options.zoomSnap:1;return o&&(t=Math.round(t/o)*o),Math.max(e,Math.min(i,t))},_onPanTransitionStep:function(){this.fire("move")},_onPanTransitionEnd:function(){k(this._mapPane,"leaflet-pan-anim"),this.fire("moveend")},_tryAnimatedPan:function(t,e){var i=this._getCenterOffset(t)._trunc();return(e&&e.animate)!==!0&&!this.getSize().contains(i)?!1:(this.panBy(i,e),!0)},_createAnimProxy:function(){var t=this._proxy=y("div","leaflet-proxy leaflet-zoom-animated");this._panes.mapPane.appendChild(t),this.on("zoomanim",function(e){var i=De,o=this._proxy.style[i];ct(this._proxy,this.project(e.center,e.zoom),this.getZoomScale(e.zoom,1)),o===this._proxy.style[i]&&this._animatingZoom&&this._onZoomTransitionEnd()},this),this.on("load moveend",this._animMoveEnd,this),this._on("unload",this._destroyAnimProxy,this)},_destroyAnimProxy:function(){M(this._proxy),this.off("load moveend",this._animMoveEnd,this),delete this._proxy},_animMoveEnd:function(){var t=this.getCenter(),e=this.getZoom();ct(this._proxy,this.project(t,e),this.getZoomScale(e,1))},_catchTransitionEnd:function(t){this._animatingZoom&&t.propertyName.indexOf("transform")>=0&&this._onZoomTransitionEnd()},_nothingToAnimate:function(){return!this._container.getElementsByClassName("leaflet-zoom-animated").length},_tryAnimatedZoom:function(t,e,i){if(this._animatingZoom)return!0;if(i=i||{},!this._zoomAnimated||i.animate===!1||this._nothingToAnimate()||Math.abs(e-this._zoom)>this.options.zoomAnimationThreshold)return!1;var o=this.getZoomScale(e),n=this._getCenterOffset(t)._divideBy(1-1/o);return i.animate!==!0&&!this.getSize().contains(n)?!1:(N(function(){this._moveStart(!0,i.noMoveStart||!1)._animateZoom(t,e,!0)},this),!0)},_animateZoom:function(t,e,i,o){this._mapPane&&(i&&(this._animatingZoom=!0,this._animateToCenter=t,this._animateToZoom=e,f(this._mapPane,"leaflet-zoom-anim")),this.fire("zoomanim",{center:t,zoom:e,noUpdate:o}),this._tempFireZoomEvent||(this._tempFireZoomEvent=this._zoom!==this._animateToZoom),this._move(this._animateToCenter,this._animateToZoom,void 0,!0),setTimeout(U(this._onZoomTransitionEnd,this),250))},_onZoomTransitionEnd:function(){this._animatingZoom&&(this._mapPane&&k(this._mapPane,"leaflet-zoom-anim"),this._animatingZoom=!1,this._move(this._animateToCenter,this._animateToZoom,void 0,!0),this._tempFireZoomEvent&&this.fire("zoom"),delete this._tempFireZoomEvent,this.fire("move"),this._moveEnd(!0))}});function Lo(t,e){return new _(t,e)}var K=$.extend({options:{position:"topright"},initialize:function(t){x(this,t)},getPosition:function(){return this.options.position},setPosition:function(t){var e=this._map;return e&&e.removeControl(this),this.options.position=t,e&&e.addControl(this),this},getContainer:function(){return this._container},addTo:function(t){this.remove(),this._map=t;var e=this._container=this.onAdd(t),i=this.getPosition(),o=t._controlCorners[i];return f(e,"leaflet-control"),i.indexOf("bottom")!==-1?o.insertBefore(e,o.firstChild):o.appendChild(e),this._map.on("unload",this.remove,this),this},remove:function(){return this._map?(M(this._container),this.onRemove&&this.onRemove(this._map),this._map.off("unload",this.remove,this),this._map=null,this):this},_refocusOnMap:function(t){this._map&&t&&t.screenX>0&&t.screenY>0&&this._map.getContainer().focus()}}),Gt=function(t){return new K(t)};_.include({addControl:function(t){return t.addTo(this),this},removeControl:function(t){return t.remove(),this},_initControlPos:function(){var t=this._controlCorners={},e="leaflet-",i=this._controlContainer=y("div",e+"control-container",this._container);function o(n,r){var s=e+n+" "+e+r;t[n+r]=y("div",s,i)}o("top","left"),o("top","right"),o("bottom","left"),o("bottom","right")},_clearControlPos:function(){for(var t in this._controlCorners)M(this._controlCorners[t]);M(this._controlContainer),delete this._controlCorners,delete this._controlContainer}});var fi=K.extend({options:{collapsed:!0,position:"topright",autoZIndex:!0,hideSingleBase:!1,sortLayers:!1,sortFunction:function(t,e,i,o){return i<o?-1:o<i?1:0}},initialize:function(t,e,i){x(this,i),this._layerControlInputs=[],this._layers=[],this._lastZIndex=0,this._handlingClick=!1,this._preventClick=!1;for(var o in t)this._addLayer(t[o],o);for(o in e)this._addLayer(e[o],o,!0)},onAdd:function(t){this._initLayout(),this._update(),this._map=t,t.on("zoomend",this._checkDisabledLayers,this);for(var e=0;e<this._layers.length;e++)this._layers[e].layer.on("add remove",this._onLayerChange,this);return this._container},addTo:function(t){return K.prototype.addTo.call(this,t),this._expandIfNotCollapsed()},onRemove:function(){this._map.off("zoomend",this._checkDisabledLayers,this);for(var t=0;t<this._layers.length;t++)this._layers[t].layer.off("add remove",this._onLayerChange,this)},addBaseLayer:function(t,e){return this._addLayer(t,e),this._map?this._update():this},addOverlay:function(t,e){return this._addLayer(t,e,!0),this._map?this._update():this},removeLayer:function(t){t.off("add remove",this._onLayerChange,this);var e=this._getLayer(P(t));return e&&this._layers.splice(this._layers.indexOf(e),1),this._map?this._update():this},expand:function(){f(this._container,"leaflet-control-layers-expanded"),this._section.style.height=null;var t=this._map.getSize().y-(this._container.offsetTop+50);return t<this._section.clientHeight?(f(this._section,"leaflet-control-layers-scrollbar"),this._section.style.height=t+"px"):k(this._section,"leaflet-control-layers-scrollbar"),this._checkDisabledLayers(),this},collapse:function(){return k(this._container,"leaflet-control-layers-expanded"),this},_initLayout:function(){var t="leaflet-control-layers",e=this._container=y("div",t),i=this.options.collapsed;e.setAttribute("aria-haspopup",!0),wt(e),se(e);var o=this._section=y("section",t+"-list");i&&(this._map.on("click",this.collapse,this),p(e,{mouseenter:this._expandSafely,mouseleave:this.collapse},this));var n=this._layersLink=y("a",t+"-toggle",e);n.href="#",n.title="Layers",n.setAttribute("role","button"),p(n,{keydown:function(r){r.keyCode===13&&this._expandSafely()},click:function(r){Z(r),this._expandSafely()}},this),i||this.expand(),this._baseLayersList=y("div",t+"-base",o),this._separator=y("div",t+"-separator",o),this._overlaysList=y("div",t+"-overlays",o),e.appendChild(o)},_getLayer:function(t){for(var e=0;e<this._layers.length;e++)if(this._layers[e]&&P(this._layers[e].layer)===t)return this._layers[e]},_addLayer:function(t,e,i){this._map&&t.on("add remove",this._onLayerChange,this),this._layers.push({layer:t,name:e,overlay:i}),this.options.sortLayers&&this._layers.sort(U(function(o,n){return this.options.sortFunction(o.layer,n.layer,o.name,n.name)},this)),this.options.autoZIndex&&t.setZIndex&&(this._lastZIndex++,t.setZIndex(this._lastZIndex)),this._expandIfNotCollapsed()},_update:function(){if(!this._container)return this;Wt(this._baseLayersList),Wt(this._overlaysList),this._layerControlInputs=[];var t,e,i,o,n=0;for(i=0;i<this._layers.length;i++)o=this._layers[i],this._addItem(o),e=e||o.overlay,t=t||!o.overlay,n+=o.overlay?0:1;return this.options.hideSingleBase&&(t=t&&n>1,this._baseLayersList.style.display=t?"":"none"),this._separator.style.display=e&&t?"":"none",this},_onLayerChange:function(t){this._handlingClick||this._update();var e=this._getLayer(P(t.target)),i=e.overlay?t.type==="add"?"overlayadd":"overlayremove":t.type==="add"?"baselayerchange":null;i&&this._map.fire(i,e)},_createRadioElement:function(t,e){var i='<input type="radio" class="leaflet-control-layers-selector" name="'+t+'"'+(e?' checked="checked"':"")+"/>",o=document.createElement("div");return o.innerHTML=i,o.firstChild},_addItem:function(t){var e=document.createElement("label"),i=this._map.hasLayer(t.layer),o;t.overlay?(o=document.createElement("input"),o.type="checkbox",o.className="leaflet-control-layers-selector",o.defaultChecked=i):o=this._createRadioElement("leaflet-base-layers_"+P(this),i),this._layerControlInputs.push(o),o.layerId=P(t.layer),p(o,"click",this._onInputClick,this);var n=document.createElement("span");n.innerHTML=" "+t.name;var r=document.createElement("span");e.appendChild(r),r.appendChild(o),r.appendChild(n);var s=t.overlay?this._overlaysList:this._baseLayersList;return s.appendChild(e),this._checkDisabledLayers(),e},_onInputClick:function(){if(!this._preventClick){var t=this._layerControlInputs,e,i,o=[],n=[];this._handlingClick=!0;for(var r=t.length-1;r>=0;r--)e=t[r],i=this._getLayer(e.layerId).layer,e.checked?o.push(i):e.checked||n.push(i);for(r=0;r<n.length;r++)this._map.hasLayer(n[r])&&this._map.removeLayer(n[r]);for(r=0;r<o.length;r++)this._map.hasLayer(o[r])||this._map.addLayer(o[r]);this._handlingClick=!1,this._refocusOnMap()}},_checkDisabledLayers:function(){for(var t=this._layerControlInputs,e,i,o=this._map.getZoom(),n=t.length-1;n>=0;n--)e=t[n],i=this._getLayer(e.layerId).layer,e.disabled=i.options.minZoom!==void 0&&o<i.options.minZoom||i.options.maxZoom!==void 0&&o>i.options.maxZoom},_expandIfNotCollapsed:function(){return this._map&&!this.options.collapsed&&this.expand(),this},_expandSafely:function(){var t=this._section;this._preventClick=!0,p(t,"click",Z),this.expand();var e=this;setTimeout(function(){C(t,"click",Z),e._preventClick=!1})}}),Po=function(t,e,i){return new fi(t,e,i)};var ke=K.extend({options:{position:"topleft",zoomInText:'<span aria-hidden="true">+</span>',zoomInTitle:"Zoom in",zoomOutText:'<span aria-hidden="true">&#x2212;</span>',zoomOutTitle:"Zoom out"},onAdd:function(t){var e="leaflet-control-zoom",i=y("div",e+" leaflet-bar"),o=this.options;return this._zoomInButton=this._createButton(o.zoomInText,o.zoomInTitle,e+"-in",i,this._zoomIn),this._zoomOutButton=this._createButton(o.zoomOutText,o.zoomOutTitle,e+"-out",i,this._zoomOut),this._updateDisabled(),t.on("zoomend zoomlevelschange",this._updateDisabled,this),i},onRemove:function(t){t.off("zoomend zoomlevelschange",this._updateDisabled,this)},disable:function(){return this._disabled=!0,this._updateDisabled(),this},enable:function(){return this._disabled=!1,this._updateDisabled(),this},_zoomIn:function(t){!this._disabled&&this._map._zoom<this._map.getMaxZoom()&&this._map.zoomIn(this._map.options.zoomDelta*(t.shiftKey?3:1))},_zoomOut:function(t){!this._disabled&&this._map._zoom>this._map.getMinZoom()&&this._map.zoomOut(this._map.options.zoomDelta*(t.shiftKey?3:1))},_createButton:function(t,e,i,o,n){var r=y("a",i,o);return r.innerHTML=t,r.href="#",r.title=e,r.setAttribute("role","button"),r.setAttribute("aria-label",e),wt(r),p(r,"click",lt),p(r,"click",n,this),p(r,"click",this._refocusOnMap,this),r},_updateDisabled:function(){var t=this._map,e="leaflet-disabled";k(this._zoomInButton,e),k(this._zoomOutButton,e),this._zoomInButton.setAttribute("aria-disabled","false"),this._zoomOutButton.setAttribute("aria-disabled","false"),(this._disabled||t._zoom===t.getMinZoom())&&(f(this._zoomOutButton,e),this._zoomOutButton.setAttribute("aria-disabled","true")),(this._disabled||t._zoom===t.getMaxZoom())&&(f(this._zoomInButton,e),this._zoomInButton.setAttribute("aria-disabled","true"))}});_.mergeOptions({zoomControl:!0});_.addInitHook(function(){this.options.zoomControl&&(this.zoomControl=new ke,this.addControl(this.zoomControl))});var bo=function(t){return new ke(t)};var ci=K.extend({options:{position:"bottomleft",maxWidth:100,metric:!0,imperial:!0},onAdd:function(t){var e="leaflet-control-scale",i=y("div",e),o=this.options;return this._addScales(o,e+"-line",i),t.on(o.updateWhenIdle?"moveend":"move",this._update,this),t.whenReady(this._update,this),i},onRemove:function(t){t.off(this.options.updateWhenIdle?"moveend":"move",this._update,this)},_addScales:function(t,e,i){t.metric&&(this._mScale=y("div",e,i)),t.imperial&&(this._iScale=y("div",e,i))},_update:function(){var t=this._map,e=t.getSize().y/2,i=t.distance(t.containerPointToLatLng([0,e]),t.containerPointToLatLng([this.options.maxWidth,e]));this._updateScales(i)},_updateScales:function(t){this.options.metric&&t&&this._updateMetric(t),this.options.imperial&&t&&this._updateImperial(t)},_updateMetric:function(t){var e=this._getRoundNum(t),i=e<1e3?e+" m":e/1e3+" km";this._updateScale(this._mScale,i,e/t)},_updateImperial:function(t){var e=t*3.2808399,i,o,n;e>5280?(i=e/5280,o=this._getRoundNum(i),this._updateScale(this._iScale,o+" mi",o/i)):(n=this._getRoundNum(e),this._updateScale(this._iScale,n+" ft",n/e))},_updateScale:function(t,e,i){t.style.width=Math.round(this.options.maxWidth*i)+"px",t.innerHTML=e},_getRoundNum:function(t){var e=Math.pow(10,(Math.floor(t)+"").length-1),i=t/e;return i=i>=10?10:i>=5?5:i>=3?3:i>=2?2:1,e*i}}),To=function(t){return new ci(t)};var Rn='<svg aria-hidden="true" xmlns="http://www.w3.org/2000/svg" width="12" height="8" viewBox="0 0 12 8" class="leaflet-attribution-flag"><path fill="#4C7BE1" d="M0 0h12v4H0z"/><path fill="#FFD500" d="M0 4h12v3H0z"/><path fill="#E0BC00" d="M0 7h12v1H0z"/></svg>',ze=K.extend({options:{position:"bottomright",prefix:'<a href="https://leafletjs.com" title="A JavaScript library for interactive maps">'+(u.inlineSvg?Rn+" ":"")+"Leaflet</a>"},initialize:function(t){x(this,t),this._attributions={}},onAdd:function(t){t.attributionControl=this,this._container=y("div","leaflet-control-attribution"),wt(this._container);for(var e in t._layers)t._layers[e].getAttribution&&this.addAttribution(t._layers[e].getAttribution());return this._update(),t.on("layeradd",this._addAttribution,this),this._container},onRemove:function(t){t.off("layeradd",this._addAttribution,this)},_addAttribution:function(t){t.layer.getAttribution&&(this.addAttribution(t.layer.getAttribution()),t.layer.once("remove",function(){this.removeAttribution(t.layer.getAttribution())},this))},setPrefix:function(t){return this.options.prefix=t,this._update(),this},addAttribution:function(t){return t?(this._attributions[t]||(this._attributions[t]=0),this._attributions[t]++,this._update(),this):this},removeAttribution:function(t){return t?(this._attributions[t]&&(this._attributions[t]--,this._update()),this):this},_update:function(){if(this._map){var t=[];for(var e in this._attributions)this._attributions[e]&&t.push(e);var i=[];this.options.prefix&&i.push(this.options.prefix),t.length&&i.push(t.join(", ")),this._container.innerHTML=i.join(' <span aria-hidden="true">|</span> ')}}});_.mergeOptions({attributionControl:!0});_.addInitHook(function(){this.options.attributionControl&&new ze().addTo(this)});var Do=function(t){return new ze(t)};K.Layers=fi;K.Zoom=ke;K.Scale=ci;K.Attribution=ze;Gt.layers=Po;Gt.zoom=bo;Gt.scale=To;Gt.attribution=Do;var G=$.extend({initialize:function(t){this._map=t},enable:function(){return this._enabled?this:(this._enabled=!0,this.addHooks(),this)},disable:function(){return this._enabled?(this._enabled=!1,this.removeHooks(),this):this},enabled:function(){return!!this._enabled}});G.addTo=function(t,e){return t.addHandler(e,this),this};var Fn={Events:Q};var Uo=u.touch?"touchstart mousedown":"mousedown",dt=_t.extend({options:{clickTolerance:3},initialize:function(t,e,i,o){x(this,o),this._element=t,this._dragStartTarget=e||t,this._preventOutline=i},enable:function(){this._enabled||(p(this._dragStartTarget,Uo,this._onDown,this),this._enabled=!0)},disable:function(){this._enabled&&(dt._dragging===this&&this.finishDrag(!0),C(this._dragStartTarget,Uo,this._onDown,this),this._enabled=!1,this._moved=!1)},_onDown:function(t){if(this._enabled&&(this._moved=!1,!Ue(this._element,"leaflet-zoom-anim"))){if(t.touches&&t.touches.length!==1){dt._dragging===this&&this.finishDrag();return}if(!(dt._dragging||t.shiftKey||t.which!==1&&t.button!==1&&!t.touches)&&(dt._dragging=this,this._preventOutline&&ne(this._element),ie(),St(),!this._moving)){this.fire("down");var e=t.touches?t.touches[0]:t,i=si(this._element);this._startPoint=new c(e.clientX,e.clientY),this._startPos=ht(this._element),this._parentScale=re(i);var o=t.type==="mousedown";p(document,o?"mousemove":"touchmove",this._onMove,this),p(document,o?"mouseup":"touchend touchcancel",this._onUp,this)}}},_onMove:function(t){if(this._enabled){if(t.touches&&t.touches.length>1){this._moved=!0;return}var e=t.touches&&t.touches.length===1?t.touches[0]:t,i=new c(e.clientX,e.clientY)._subtract(this._startPoint);!i.x&&!i.y||Math.abs(i.x)+Math.abs(i.y)<this.options.clickTolerance||(i.x/=this._parentScale.x,i.y/=this._parentScale.y,Z(t),this._moved||(this.fire("dragstart"),this._moved=!0,f(document.body,"leaflet-dragging"),this._lastTarget=t.target||t.srcElement,window.SVGElementInstance&&this._lastTarget instanceof window.SVGElementInstance&&(this._lastTarget=this._lastTarget.correspondingUseElement),f(this._lastTarget,"leaflet-drag-target")),this._newPos=this._startPos.add(i),this._moving=!0,this._lastEvent=t,this._updatePosition())}},_updatePosition:function(){var t={originalEvent:this._lastEvent};this.fire("predrag",t),S(this._element,this._newPos),this.fire("drag",t)},_onUp:function(){this._enabled&&this.finishDrag()},finishDrag:function(t){k(document.body,"leaflet-dragging"),this._lastTarget&&(k(this._lastTarget,"leaflet-drag-target"),this._lastTarget=null),C(document,"mousemove touchmove",this._onMove,this),C(document,"mouseup touchend touchcancel",this._onUp,this),oe(),kt();var e=this._moved&&this._moving;this._moving=!1,dt._dragging=!1,e&&this.fire("dragend",{noInertia:t,distance:this._newPos.distanceTo(this._startPos)})}});var zt={};Ct(zt,{_flat:()=>xi,_getBitCode:()=>Ut,_getEdgeIntersection:()=>ae,_sqClosestPointOnSegment:()=>qt,clipSegment:()=>yi,closestPointOnSegment:()=>Hn,isFlat:()=>Y,pointToSegmentDistance:()=>gi,polylineCenter:()=>wi,simplify:()=>vi});var Be={};Ct(Be,{centroid:()=>Oe,clipPolygon:()=>di,polygonCenter:()=>pi});function di(t,e,i){var o,n=[1,4,2,8],r,s,a,h,l,m,v,D;for(r=0,m=t.length;r<m;r++)t[r]._code=Ut(t[r],e);for(a=0;a<4;a++){for(v=n[a],o=[],r=0,m=t.length,s=m-1;r<m;s=r++)h=t[r],l=t[s],h._code&v?l._code&v||(D=ae(l,h,v,e,i),D._code=Ut(D,e),o.push(D)):(l._code&v&&(D=ae(l,h,v,e,i),D._code=Ut(D,e),o.push(D)),o.push(h));t=o}return t}function pi(t,e){var i,o,n,r,s,a,h,l,m;if(!t||t.length===0)throw new Error("latlngs not passed");Y(t)||(console.warn("latlngs are not flat! Only the first ring will be used"),t=t[0]);var v=w([0,0]),D=O(t),X=D.getNorthWest().distanceTo(D.getSouthWest())*D.getNorthEast().distanceTo(D.getNorthWest());X<1700&&(v=Oe(t));var q=t.length,st=[];for(i=0;i<q;i++){var J=w(t[i]);st.push(e.project(w([J.lat-v.lat,J.lng-v.lng])))}for(a=h=l=0,i=0,o=q-1;i<q;o=i++)n=st[i],r=st[o],s=n.y*r.x-r.y*n.x,h+=(n.x+r.x)*s,l+=(n.y+r.y)*s,a+=s*3;a===0?m=st[0]:m=[h/a,l/a];var Nt=e.unproject(d(m));return w([Nt.lat+v.lat,Nt.lng+v.lng])}function Oe(t){for(var e=0,i=0,o=0,n=0;n<t.length;n++){var r=w(t[n]);e+=r.lat,i+=r.lng,o++}return w([e/o,i/o])}function vi(t,e){if(!e||!t.length)return t.slice();var i=e*e;return t=Gn(t,i),t=Wn(t,i),t}function gi(t,e,i){return Math.sqrt(qt(t,e,i,!0))}function Hn(t,e,i){return qt(t,e,i)}function Wn(t,e){var i=t.length,o=typeof Uint8Array!="undefined"?Uint8Array:Array,n=new o(i);n[0]=n[i-1]=1,_i(t,n,e,0,i-1);var r,s=[];for(r=0;r<i;r++)n[r]&&s.push(t[r]);return s}function _i(t,e,i,o,n){var r=0,s,a,h;for(a=o+1;a<=n-1;a++)h=qt(t[a],t[o],t[n],!0),h>r&&(s=a,r=h);r>i&&(e[s]=1,_i(t,e,i,o,s),_i(t,e,i,s,n))}function Gn(t,e){for(var i=[t[0]],o=1,n=0,r=t.length;o<r;o++)qn(t[o],t[n])>e&&(i.push(t[o]),n=o);return n<r-1&&i.push(t[r-1]),i}var Eo;function yi(t,e,i,o,n){var r=o?Eo:Ut(t,i),s=Ut(e,i),a,h,l;for(Eo=s;;){if(!(r|s))return[t,e];if(r&s)return!1;a=r||s,h=ae(t,e,a,i,n),l=Ut(h,i),a===r?(t=h,r=l):(e=h,s=l)}}function ae(t,e,i,o,n){var r=e.x-t.x,s=e.y-t.y,a=o.min,h=o.max,l,m;return i&8?(l=t.x+r*(h.y-t.y)/s,m=h.y):i&4?(l=t.x+r*(a.y-t.y)/s,m=a.y):i&2?(l=h.x,m=t.y+s*(h.x-t.x)/r):i&1&&(l=a.x,m=t.y+s*(a.x-t.x)/r),new c(l,m,n)}function Ut(t,e){var i=0;return t.x<e.min.x?i|=1:t.x>e.max.x&&(i|=2),t.y<e.min.y?i|=4:t.y>e.max.y&&(i|=8),i}function qn(t,e){var i=e.x-t.x,o=e.y-t.y;return i*i+o*o}function qt(t,e,i,o){var n=e.x,r=e.y,s=i.x-n,a=i.y-r,h=s*s+a*a,l;return h>0&&(l=((t.x-n)*s+(t.y-r)*a)/h,l>1?(n=i.x,r=i.y):l>0&&(n+=s*l,r+=a*l)),s=t.x-n,a=t.y-r,o?s*s+a*a:new c(n,r)}function Y(t){return!I(t[0])||typeof t[0][0]!="object"&&typeof t[0][0]!="undefined"}function xi(t){return console.warn("Deprecated use of _flat, please use L.LineUtil.isFlat instead."),Y(t)}function wi(t,e){var i,o,n,r,s,a,h,l;if(!t||t.length===0)throw new Error("latlngs not passed");Y(t)||(console.warn("latlngs are not flat! Only the first ring will be used"),t=t[0]);var m=w([0,0]),v=O(t),D=v.getNorthWest().distanceTo(v.getSouthWest())*v.getNorthEast().distanceTo(v.getNorthWest());D<1700&&(m=Oe(t));var X=t.length,q=[];for(i=0;i<X;i++){var st=w(t[i]);q.push(e.project(w([st.lat-m.lat,st.lng-m.lng])))}for(i=0,o=0;i<X-1;i++)o+=q[i].distanceTo(q[i+1])/2;if(o===0)l=q[0];else for(i=0,r=0;i<X-1;i++)if(s=q[i],a=q[i+1],n=s.distanceTo(a),r+=n,r>o){h=(r-o)/n,l=[a.x-h*(a.x-s.x),a.y-h*(a.y-s.y)];break}var J=e.unproject(d(l));return w([J.lat+m.lat,J.lng+m.lng])}var Li={};Ct(Li,{LonLat:()=>jt,Mercator:()=>he,SphericalMercator:()=>Qt});var jt={project:function(t){return new c(t.lng,t.lat)},unproject:function(t){return new T(t.y,t.x)},bounds:new E([-180,-90],[180,90])};var he={R:6378137,R_MINOR:6356752314245179e-9,bounds:new E([-2003750834279e-5,-1549657073972e-5],[2003750834279e-5,1876465623138e-5]),project:function(t){var e=Math.PI/180,i=this.R,o=t.lat*e,n=this.R_MINOR/i,r=Math.sqrt(1-n*n),s=r*Math.sin(o),a=Math.tan(Math.PI/4-o/2)/Math.pow((1-s)/(1+s),r/2);return o=-i*Math.log(Math.max(a,1e-10)),new c(t.lng*e*i,o)},unproject:function(t){for(var e=180/Math.PI,i=this.R,o=this.R_MINOR/i,n=Math.sqrt(1-o*o),r=Math.exp(-t.y/i),s=Math.PI/2-2*Math.atan(r),a=0,h=.1,l;a<15&&Math.abs(h)>1e-7;a++)l=n*Math.sin(s),l=Math.pow((1-l)/(1+l),n/2),h=Math.PI/2-2*Math.atan(r*l)-s,s+=h;return new T(s*e,t.x*e/i)}};var Co=b({},tt,{code:"EPSG:3395",projection:he,transformation:function(){var t=.5/(Math.PI*he.R);return vt(t,.5,-t,.5)}()});var Ze=b({},tt,{code:"EPSG:4326",projection:jt,transformation:vt(1/180,1,-1/180,.5)});var Mo=b({},at,{projection:jt,transformation:vt(1,0,-1,0),scale:function(t){return Math.pow(2,t)},zoom:function(t){return Math.log(t)/Math.LN2},distance:function(t,e){var i=e.lng-t.lng,o=e.lat-t.lat;return Math.sqrt(i*i+o*o)},infinite:!0});at.Earth=tt;at.EPSG3395=Co;at.EPSG3857=te;at.EPSG900913=Xi;at.EPSG4326=Ze;at.Simple=Mo;var F=_t.extend({options:{pane:"overlayPane",attribution:null,bubblingMouseEvents:!0},addTo:function(t){return t.addLayer(this),this},remove:function(){return this.removeFrom(this._map||this._mapToAdd)},removeFrom:function(t){return t&&t.removeLayer(this),this},getPane:function(t){return this._map.getPane(t?this.options[t]||t:this.options.pane)},addInteractiveTarget:function(t){return this._map._targets[P(t)]=this,this},removeInteractiveTarget:function(t){return delete this._map._targets[P(t)],this},getAttribution:function(){return this.options.attribution},_layerAdd:function(t){var e=t.target;if(e.hasLayer(this)){if(this._map=e,this._zoomAnimated=e._zoomAnimated,this.getEvents){var i=this.getEvents();e.on(i,this),this.once("remove",function(){e.off(i,this)},this)}this.onAdd(e),this.fire("add"),e.fire("layeradd",{layer:this})}}});_.include({addLayer:function(t){if(!t._layerAdd)throw new Error("The provided object is not a Layer.");var e=P(t);return this._layers[e]?this:(this._layers[e]=t,t._mapToAdd=this,t.beforeAdd&&t.beforeAdd(this),this.whenReady(t._layerAdd,t),this)},removeLayer:function(t){var e=P(t);return this._layers[e]?(this._loaded&&t.onRemove(this),delete this._layers[e],this._loaded&&(this.fire("layerremove",{layer:t}),t.fire("remove")),t._map=t._mapToAdd=null,this):this},hasLayer:function(t){return P(t)in this._layers},eachLayer:function(t,e){for(var i in this._layers)t.call(e,this._layers[i]);return this},_addLayers:function(t){t=t?I(t)?t:[t]:[];for(var e=0,i=t.length;e<i;e++)this.addLayer(t[e])},_addZoomLimit:function(t){(!isNaN(t.options.maxZoom)||!isNaN(t.options.minZoom))&&(this._zoomBoundLayers[P(t)]=t,this._updateZoomLevels())},_removeZoomLimit:function(t){var e=P(t);this._zoomBoundLayers[e]&&(delete this._zoomBoundLayers[e],this._updateZoomLevels())},_updateZoomLevels:function(){var t=1/0,e=-1/0,i=this._getZoomSpan();for(var o in this._zoomBoundLayers){var n=this._zoomBoundLayers[o].options;t=n.minZoom===void 0?t:Math.min(t,n.minZoom),e=n.maxZoom===void 0?e:Math.max(e,n.maxZoom)}this._layersMaxZoom=e===-1/0?void 0:e,this._layersMinZoom=t===1/0?void 0:t,i!==this._getZoomSpan()&&this.fire("zoomlevelschange"),this.options.maxZoom===void 0&&this._layersMaxZoom&&this.getZoom()>this._layersMaxZoom&&this.setZoom(this._layersMaxZoom),this.options.minZoom===void 0&&this._layersMinZoom&&this.getZoom()<this._layersMinZoom&&this.setZoom(this._layersMinZoom)}});var Lt=F.extend({initialize:function(t,e){x(this,e),this._layers={};var i,o;if(t)for(i=0,o=t.length;i<o;i++)this.addLayer(t[i])},addLayer:function(t){var e=this.getLayerId(t);return this._layers[e]=t,this._map&&this._map.addLayer(t),this},removeLayer:function(t){var e=t in this._layers?t:this.getLayerId(t);return this._map&&this._layers[e]&&this._map.removeLayer(this._layers[e]),delete this._layers[e],this},hasLayer:function(t){var e=typeof t=="number"?t:this.getLayerId(t);return e in this._layers},clearLayers:function(){return this.eachLayer(this.removeLayer,this)},invoke:function(t){var e=Array.prototype.slice.call(arguments,1),i,o;for(i in this._layers)o=this._layers[i],o[t]&&o[t].apply(o,e);return this},onAdd:function(t){this.eachLayer(t.addLayer,t)},onRemove:function(t){this.eachLayer(t.removeLayer,t)},eachLayer:function(t,e){for(var i in this._layers)t.call(e,this._layers[i]);return this},getLayer:function(t){return this._layers[t]},getLayers:function(){var t=[];return this.eachLayer(t.push,t),t},setZIndex:function(t){return this.invoke("setZIndex",t)},getLayerId:function(t){return P(t)}}),So=function(t,e){return new Lt(t,e)};var et=Lt.extend({addLayer:function(t){return this.hasLayer(t)?this:(t.addEventParent(this),Lt.prototype.addLayer.call(this,t),this.fire("layeradd",{layer:t}))},removeLayer:function(t){return this.hasLayer(t)?(t in this._layers&&(t=this._layers[t]),t.removeEventParent(this),Lt.prototype.removeLayer.call(this,t),this.fire("layerremove",{layer:t})):this},setStyle:function(t){return this.invoke("setStyle",t)},bringToFront:function(){return this.invoke("bringToFront")},bringToBack:function(){return this.invoke("bringToBack")},getBounds:function(){var t=new A;for(var e in this._layers){var i=this._layers[e];t.extend(i.getBounds?i.getBounds():i.getLatLng())}return t}}),ko=function(t,e){return new et(t,e)};var Pt=$.extend({options:{popupAnchor:[0,0],tooltipAnchor:[0,0],crossOrigin:!1},initialize:function(t){x(this,t)},createIcon:function(t){return this._createIcon("icon",t)},createShadow:function(t){return this._createIcon("shadow",t)},_createIcon:function(t,e){var i=this._getIconUrl(t);if(!i){if(t==="icon")throw new Error("iconUrl not set in Icon options (see the docs).");return null}var o=this._createImg(i,e&&e.tagName==="IMG"?e:null);return this._setIconStyles(o,t),(this.options.crossOrigin||this.options.crossOrigin==="")&&(o.crossOrigin=this.options.crossOrigin===!0?"":this.options.crossOrigin),o},_setIconStyles:function(t,e){var i=this.options,o=i[e+"Size"];typeof o=="number"&&(o=[o,o]);var n=d(o),r=d(e==="shadow"&&i.shadowAnchor||i.iconAnchor||n&&n.divideBy(2,!0));t.className="leaflet-marker-"+e+" "+(i.className||""),r&&(t.style.marginLeft=-r.x+"px",t.style.marginTop=-r.y+"px"),n&&(t.style.width=n.x+"px",t.style.height=n.y+"px")},_createImg:function(t,e){return e=e||document.createElement("img"),e.src=t,e},_getIconUrl:function(t){return u.retina&&this.options[t+"RetinaUrl"]||this.options[t+"Url"]}});function zo(t){return new Pt(t)}var Ot=Pt.extend({options:{iconUrl:"marker-icon.png",iconRetinaUrl:"marker-icon-2x.png",shadowUrl:"marker-shadow.png",iconSize:[25,41],iconAnchor:[12,41],popupAnchor:[1,-34],tooltipAnchor:[16,-28],shadowSize:[41,41]},_getIconUrl:function(t){return typeof Ot.imagePath!="string"&&(Ot.imagePath=this._detectIconPath()),(this.options.imagePath||Ot.imagePath)+Pt.prototype._getIconUrl.call(this,t)},_stripUrl:function(t){var e=function(i,o,n){var r=o.exec(i);return r&&r[n]};return t=e(t,/^url\((['"])?(.+)\1\)$/,2),t&&e(t,/^(.*)marker-icon\.png$/,1)},_detectIconPath:function(){var t=y("div","leaflet-default-icon-path",document.body),e=Tt(t,"background-image")||Tt(t,"backgroundImage");if(document.body.removeChild(t),e=this._stripUrl(e),e)return e;var i=document.querySelector('link[href$="leaflet.css"]');return i?i.href.substring(0,i.href.length-11-1):""}});var Pi=G.extend({initialize:function(t){this._marker=t},addHooks:function(){var t=this._marker._icon;this._draggable||(this._draggable=new dt(t,t,!0)),this._draggable.on({dragstart:this._onDragStart,predrag:this._onPreDrag,drag:this._onDrag,dragend:this._onDragEnd},this).enable(),f(t,"leaflet-marker-draggable")},removeHooks:function(){this._draggable.off({dragstart:this._onDragStart,predrag:this._onPreDrag,drag:this._onDrag,dragend:this._onDragEnd},this).disable(),this._marker._icon&&k(this._marker._icon,"leaflet-marker-draggable")},moved:function(){return this._draggable&&this._draggable._moved},_adjustPan:function(t){var e=this._marker,i=e._map,o=this._marker.options.autoPanSpeed,n=this._marker.options.autoPanPadding,r=ht(e._icon),s=i.getPixelBounds(),a=i.getPixelOrigin(),h=W(s.min._subtract(a).add(n),s.max._subtract(a).subtract(n));if(!h.contains(r)){var l=d((Math.max(h.max.x,r.x)-h.max.x)/(s.max.x-h.max.x)-(Math.min(h.min.x,r.x)-h.min.x)/(s.min.x-h.min.x),(Math.max(h.max.y,r.y)-h.max.y)/(s.max.y-h.max.y)-(Math.min(h.min.y,r.y)-h.min.y)/(s.min.y-h.min.y)).multiplyBy(o);i.panBy(l,{animate:!1}),this._draggable._newPos._add(l),this._draggable._startPos._add(l),S(e._icon,this._draggable._newPos),this._onDrag(t),this._panRequest=N(this._adjustPan.bind(this,t))}},_onDragStart:function(){this._oldLatLng=this._marker.getLatLng(),this._marker.closePopup&&this._marker.closePopup(),this._marker.fire("movestart").fire("dragstart")},_onPreDrag:function(t){this._marker.options.autoPan&&(j(this._panRequest),this._panRequest=N(this._adjustPan.bind(this,t)))},_onDrag:function(t){var e=this._marker,i=e._shadow,o=ht(e._icon),n=e._map.layerPointToLatLng(o);i&&S(i,o),e._latlng=n,t.latlng=n,t.oldLatLng=this._oldLatLng,e.fire("move",t).fire("drag",t)},_onDragEnd:function(t){j(this._panRequest),delete this._oldLatLng,this._marker.fire("moveend").fire("dragend",t)}});var Vt=F.extend({options:{icon:new Ot,interactive:!0,keyboard:!0,title:"",alt:"Marker",zIndexOffset:0,opacity:1,riseOnHover:!1,riseOffset:250,pane:"markerPane",shadowPane:"shadowPane",bubblingMouseEvents:!1,autoPanOnFocus:!0,draggable:!1,autoPan:!1,autoPanPadding:[50,50],autoPanSpeed:10},initialize:function(t,e){x(this,e),this._latlng=w(t)},onAdd:function(t){this._zoomAnimated=this._zoomAnimated&&t.options.markerZoomAnimation,this._zoomAnimated&&t.on("zoomanim",this._animateZoom,this),this._initIcon(),this.update()},onRemove:function(t){this.dragging&&this.dragging.enabled()&&(this.options.draggable=!0,this.dragging.removeHooks()),delete this.dragging,this._zoomAnimated&&t.off("zoomanim",this._animateZoom,this),this._removeIcon(),this._removeShadow()},getEvents:function(){return{zoom:this.update,viewreset:this.update}},getLatLng:function(){return this._latlng},setLatLng:function(t){var e=this._latlng;return this._latlng=w(t),this.update(),this.fire("move",{oldLatLng:e,latlng:this._latlng})},setZIndexOffset:function(t){return this.options.zIndexOffset=t,this.update()},getIcon:function(){return this.options.icon},setIcon:function(t){return this.options.icon=t,this._map&&(this._initIcon(),this.update()),this._popup&&this.bindPopup(this._popup,this._popup.options),this},getElement:function(){return this._icon},update:function(){if(this._icon&&this._map){var t=this._map.latLngToLayerPoint(this._latlng).round();this._setPos(t)}return this},_initIcon:function(){var t=this.options,e="leaflet-zoom-"+(this._zoomAnimated?"animated":"hide"),i=t.icon.createIcon(this._icon),o=!1;i!==this._icon&&(this._icon&&this._removeIcon(),o=!0,t.title&&(i.title=t.title),i.tagName==="IMG"&&(i.alt=t.alt||"")),f(i,e),t.keyboard&&(i.tabIndex="0",i.setAttribute("role","button")),this._icon=i,t.riseOnHover&&this.on({mouseover:this._bringToFront,mouseout:this._resetZIndex}),this.options.autoPanOnFocus&&p(i,"focus",this._panOnFocus,this);var n=t.icon.createShadow(this._shadow),r=!1;n!==this._shadow&&(this._removeShadow(),r=!0),n&&(f(n,e),n.alt=""),this._shadow=n,t.opacity<1&&this._updateOpacity(),o&&this.getPane().appendChild(this._icon),this._initInteraction(),n&&r&&this.getPane(t.shadowPane).appendChild(this._shadow)},_removeIcon:function(){this.options.riseOnHover&&this.off({mouseover:this._bringToFront,mouseout:this._resetZIndex}),this.options.autoPanOnFocus&&C(this._icon,"focus",this._panOnFocus,this),M(this._icon),this.removeInteractiveTarget(this._icon),this._icon=null},_removeShadow:function(){this._shadow&&M(this._shadow),this._shadow=null},_setPos:function(t){this._icon&&S(this._icon,t),this._shadow&&S(this._shadow,t),this._zIndex=t.y+this.options.zIndexOffset,this._resetZIndex()},_updateZIndex:function(t){this._icon&&(this._icon.style.zIndex=this._zIndex+t)},_animateZoom:function(t){var e=this._map._latLngToNewLayerPoint(this._latlng,t.zoom,t.center).round();this._setPos(e)},_initInteraction:function(){if(this.options.interactive&&(f(this._icon,"leaflet-interactive"),this.addInteractiveTarget(this._icon),Pi)){var t=this.options.draggable;this.dragging&&(t=this.dragging.enabled(),this.dragging.disable()),this.dragging=new Pi(this),t&&this.dragging.enable()}},setOpacity:function(t){return this.options.opacity=t,this._map&&this._updateOpacity(),this},_updateOpacity:function(){var t=this.options.opacity;this._icon&&V(this._icon,t),this._shadow&&V(this._shadow,t)},_bringToFront:function(){this._updateZIndex(this.options.riseOffset)},_resetZIndex:function(){this._updateZIndex(0)},_panOnFocus:function(){var t=this._map;if(t){var e=this.options.icon.options,i=e.iconSize?d(e.iconSize):d(0,0),o=e.iconAnchor?d(e.iconAnchor):d(0,0);t.panInside(this._latlng,{paddingTopLeft:o,paddingBottomRight:i.subtract(o)})}},_getPopupAnchor:function(){return this.options.icon.options.popupAnchor},_getTooltipAnchor:function(){return this.options.icon.options.tooltipAnchor}});function Oo(t,e){return new Vt(t,e)}var ot=F.extend({options:{stroke:!0,color:"#3388ff",weight:3,opacity:1,lineCap:"round",lineJoin:"round",dashArray:null,dashOffset:null,fill:!1,fillColor:null,fillOpacity:.2,fillRule:"evenodd",interactive:!0,bubblingMouseEvents:!0},beforeAdd:function(t){this._renderer=t.getRenderer(this)},onAdd:function(){this._renderer._initPath(this),this._reset(),this._renderer._addPath(this)},onRemove:function(){this._renderer._removePath(this)},redraw:function(){return this._map&&this._renderer._updatePath(this),this},setStyle:function(t){return x(this,t),this._renderer&&(this._renderer._updateStyle(this),this.options.stroke&&t&&Object.prototype.hasOwnProperty.call(t,"weight")&&this._updateBounds()),this},bringToFront:function(){return this._renderer&&this._renderer._bringToFront(this),this},bringToBack:function(){return this._renderer&&this._renderer._bringToBack(this),this},getElement:function(){return this._path},_reset:function(){this._project(),this._update()},_clickTolerance:function(){return(this.options.stroke?this.options.weight/2:0)+(this._renderer.options.tolerance||0)}});var Bt=ot.extend({options:{fill:!0,radius:10},initialize:function(t,e){x(this,e),this._latlng=w(t),this._radius=this.options.radius},setLatLng:function(t){var e=this._latlng;return this._latlng=w(t),this.redraw(),this.fire("move",{oldLatLng:e,latlng:this._latlng})},getLatLng:function(){return this._latlng},setRadius:function(t){return this.options.radius=this._radius=t,this.redraw()},getRadius:function(){return this._radius},setStyle:function(t){var e=t&&t.radius||this._radius;return ot.prototype.setStyle.call(this,t),this.setRadius(e),this},_project:function(){this._point=this._map.latLngToLayerPoint(this._latlng),this._updateBounds()},_updateBounds:function(){var t=this._radius,e=this._radiusY||t,i=this._clickTolerance(),o=[t+i,e+i];this._pxBounds=new E(this._point.subtract(o),this._point.add(o))},_update:function(){this._map&&this._updatePath()},_updatePath:function(){this._renderer._updateCircle(this)},_empty:function(){return this._radius&&!this._renderer._bounds.intersects(this._pxBounds)},_containsPoint:function(t){return t.distanceTo(this._point)<=this._radius+this._clickTolerance()}});function Bo(t,e){return new Bt(t,e)}var le=Bt.extend({initialize:function(t,e,i){if(typeof e=="number"&&(e=b({},i,{radius:e})),x(this,e),this._latlng=w(t),isNaN(this.options.radius))throw new Error("Circle radius cannot be NaN");this._mRadius=this.options.radius},setRadius:function(t){return this._mRadius=t,this.redraw()},getRadius:function(){return this._mRadius},getBounds:function(){var t=[this._radius,this._radiusY||this._radius];return new A(this._map.layerPointToLatLng(this._point.subtract(t)),this._map.layerPointToLatLng(this._point.add(t)))},setStyle:ot.prototype.setStyle,_project:function(){var t=this._latlng.lng,e=this._latlng.lat,i=this._map,o=i.options.crs;if(o.distance===tt.distance){var n=Math.PI/180,r=this._mRadius/tt.R/n,s=i.project([e+r,t]),a=i.project([e-r,t]),h=s.add(a).divideBy(2),l=i.unproject(h).lat,m=Math.acos((Math.cos(r*n)-Math.sin(e*n)*Math.sin(l*n))/(Math.cos(e*n)*Math.cos(l*n)))/n;(isNaN(m)||m===0)&&(m=r/Math.cos(Math.PI/180*e)),this._point=h.subtract(i.getPixelOrigin()),this._radius=isNaN(m)?0:h.x-i.project([l,t-m]).x,this._radiusY=h.y-s.y}else{var v=o.unproject(o.project(this._latlng).subtract([this._mRadius,0]));this._point=i.latLngToLayerPoint(this._latlng),this._radius=this._point.x-i.latLngToLayerPoint(v).x}this._updateBounds()}});function Zo(t,e,i){return new le(t,e,i)}var ut=ot.extend({options:{smoothFactor:1,noClip:!1},initialize:function(t,e){x(this,e),this._setLatLngs(t)},getLatLngs:function(){return this._latlngs},setLatLngs:function(t){return this._setLatLngs(t),this.redraw()},isEmpty:function(){return!this._latlngs.length},closestLayerPoint:function(t){for(var e=1/0,i=null,o=qt,n,r,s=0,a=this._parts.length;s<a;s++)for(var h=this._parts[s],l=1,m=h.length;l<m;l++){n=h[l-1],r=h[l];var v=o(t,n,r,!0);v<e&&(e=v,i=o(t,n,r))}return i&&(i.distance=Math.sqrt(e)),i},getCenter:function(){if(!this._map)throw new Error("Must add layer to map before using getCenter()");return wi(this._defaultShape(),this._map.options.crs)},getBounds:function(){return this._bounds},addLatLng:function(t,e){return e=e||this._defaultShape(),t=w(t),e.push(t),this._bounds.extend(t),this.redraw()},_setLatLngs:function(t){this._bounds=new A,this._latlngs=this._convertLatLngs(t)},_defaultShape:function(){return Y(this._latlngs)?this._latlngs:this._latlngs[0]},_convertLatLngs:function(t){for(var e=[],i=Y(t),o=0,n=t.length;o<n;o++)i?(e[o]=w(t[o]),this._bounds.extend(e[o])):e[o]=this._convertLatLngs(t[o]);return e},_project:function(){var t=new E;this._rings=[],this._projectLatlngs(this._latlngs,this._rings,t),this._bounds.isValid()&&t.isValid()&&(this._rawPxBounds=t,this._updateBounds())},_updateBounds:function(){var t=this._clickTolerance(),e=new c(t,t);this._rawPxBounds&&(this._pxBounds=new E([this._rawPxBounds.min.subtract(e),this._rawPxBounds.max.add(e)]))},_projectLatlngs:function(t,e,i){var o=t[0]instanceof T,n=t.length,r,s;if(o){for(s=[],r=0;r<n;r++)s[r]=this._map.latLngToLayerPoint(t[r]),i.extend(s[r]);e.push(s)}else for(r=0;r<n;r++)this._projectLatlngs(t[r],e,i)},_clipPoints:function(){var t=this._renderer._bounds;if(this._parts=[],!(!this._pxBounds||!this._pxBounds.intersects(t))){if(this.options.noClip){this._parts=this._rings;return}var e=this._parts,i,o,n,r,s,a,h;for(i=0,n=0,r=this._rings.length;i<r;i++)for(h=this._rings[i],o=0,s=h.length;o<s-1;o++)a=yi(h[o],h[o+1],t,o,!0),a&&(e[n]=e[n]||[],e[n].push(a[0]),(a[1]!==h[o+1]||o===s-2)&&(e[n].push(a[1]),n++))}},_simplifyPoints:function(){for(var t=this._parts,e=this.options.smoothFactor,i=0,o=t.length;i<o;i++)t[i]=vi(t[i],e)},_update:function(){this._map&&(this._clipPoints(),this._simplifyPoints(),this._updatePath())},_updatePath:function(){this._renderer._updatePoly(this)},_containsPoint:function(t,e){var i,o,n,r,s,a,h=this._clickTolerance();if(!this._pxBounds||!this._pxBounds.contains(t))return!1;for(i=0,r=this._parts.length;i<r;i++)for(a=this._parts[i],o=0,s=a.length,n=s-1;o<s;n=o++)if(!(!e&&o===0)&&gi(t,a[n],a[o])<=h)return!0;return!1}});function Ao(t,e){return new ut(t,e)}ut._flat=xi;var bt=ut.extend({options:{fill:!0},isEmpty:function(){return!this._latlngs.length||!this._latlngs[0].length},getCenter:function(){if(!this._map)throw new Error("Must add layer to map before using getCenter()");return pi(this._defaultShape(),this._map.options.crs)},_convertLatLngs:function(t){var e=ut.prototype._convertLatLngs.call(this,t),i=e.length;return i>=2&&e[0]instanceof T&&e[0].equals(e[i-1])&&e.pop(),e},_setLatLngs:function(t){ut.prototype._setLatLngs.call(this,t),Y(this._latlngs)&&(this._latlngs=[this._latlngs])},_defaultShape:function(){return Y(this._latlngs[0])?this._latlngs[0]:this._latlngs[0][0]},_clipPoints:function(){var t=this._renderer._bounds,e=this.options.weight,i=new c(e,e);if(t=new E(t.min.subtract(i),t.max.add(i)),this._parts=[],!(!this._pxBounds||!this._pxBounds.intersects(t))){if(this.options.noClip){this._parts=this._rings;return}for(var o=0,n=this._rings.length,r;o<n;o++)r=di(this._rings[o],t,!0),r.length&&this._parts.push(r)}},_updatePath:function(){this._renderer._updatePoly(this,!0)},_containsPoint:function(t){var e=!1,i,o,n,r,s,a,h,l;if(!this._pxBounds||!this._pxBounds.contains(t))return!1;for(r=0,h=this._parts.length;r<h;r++)for(i=this._parts[r],s=0,l=i.length,a=l-1;s<l;a=s++)o=i[s],n=i[a],o.y>t.y!=n.y>t.y&&t.x<(n.x-o.x)*(t.y-o.y)/(n.y-o.y)+o.x&&(e=!e);return e||ut.prototype._containsPoint.call(this,t,!0)}});function Io(t,e){return new bt(t,e)}var xt=et.extend({initialize:function(t,e){x(this,e),this._layers={},t&&this.addData(t)},addData:function(t){var e=I(t)?t:t.features,i,o,n;if(e){for(i=0,o=e.length;i<o;i++)n=e[i],(n.geometries||n.geometry||n.features||n.coordinates)&&this.addData(n);return this}var r=this.options;if(r.filter&&!r.filter(t))return this;var s=ue(t,r);return s?(s.feature=ce(t),s.defaultOptions=s.options,this.resetStyle(s),r.onEachFeature&&r.onEachFeature(t,s),this.addLayer(s)):this},resetStyle:function(t){return t===void 0?this.eachLayer(this.resetStyle,this):(t.options=b({},t.defaultOptions),this._setLayerStyle(t,this.options.style),this)},setStyle:function(t){return this.eachLayer(function(e){this._setLayerStyle(e,t)},this)},_setLayerStyle:function(t,e){t.setStyle&&(typeof e=="function"&&(e=e(t.feature)),t.setStyle(e))}});function ue(t,e){var i=t.type==="Feature"?t.geometry:t,o=i?i.coordinates:null,n=[],r=e&&e.pointToLayer,s=e&&e.coordsToLatLng||Ae,a,h,l,m;if(!o&&!i)return null;switch(i.type){case"Point":return a=s(o),No(r,t,a,e);case"MultiPoint":for(l=0,m=o.length;l<m;l++)a=s(o[l]),n.push(No(r,t,a,e));return new et(n);case"LineString":case"MultiLineString":return h=me(o,i.type==="LineString"?0:1,s),new ut(h,e);case"Polygon":case"MultiPolygon":return h=me(o,i.type==="Polygon"?1:2,s),new bt(h,e);case"GeometryCollection":for(l=0,m=i.geometries.length;l<m;l++){var v=ue({geometry:i.geometries[l],type:"Feature",properties:t.properties},e);v&&n.push(v)}return new et(n);case"FeatureCollection":for(l=0,m=i.features.length;l<m;l++){var D=ue(i.features[l],e);D&&n.push(D)}return new et(n);default:throw new Error("Invalid GeoJSON object.")}}function No(t,e,i,o){return t?t(e,i):new Vt(i,o&&o.markersInheritOptions&&o)}function Ae(t){return new T(t[1],t[0],t[2])}function me(t,e,i){for(var o=[],n=0,r=t.length,s;n<r;n++)s=e?me(t[n],e-1,i):(i||Ae)(t[n]),o.push(s);return o}function Ie(t,e){return t=w(t),t.alt!==void 0?[it(t.lng,e),it(t.lat,e),it(t.alt,e)]:[it(t.lng,e),it(t.lat,e)]}function fe(t,e,i,o){for(var n=[],r=0,s=t.length;r<s;r++)n.push(e?fe(t[r],Y(t[r])?0:e-1,i,o):Ie(t[r],o));return!e&&i&&n.length>0&&n.push(n[0].slice()),n}function Zt(t,e){return t.feature?b({},t.feature,{geometry:e}):ce(e)}function ce(t){return t.type==="Feature"||t.type==="FeatureCollection"?t:{type:"Feature",properties:{},geometry:t}}var bi={toGeoJSON:function(t){return Zt(this,{type:"Point",coordinates:Ie(this.getLatLng(),t)})}};Vt.include(bi);le.include(bi);Bt.include(bi);ut.include({toGeoJSON:function(t){var e=!Y(this._latlngs),i=fe(this._latlngs,e?1:0,!1,t);return Zt(this,{type:(e?"Multi":"")+"LineString",coordinates:i})}});bt.include({toGeoJSON:function(t){var e=!Y(this._latlngs),i=e&&!Y(this._latlngs[0]),o=fe(this._latlngs,i?2:e?1:0,!0,t);return e||(o=[o]),Zt(this,{type:(i?"Multi":"")+"Polygon",coordinates:o})}});Lt.include({toMultiPoint:function(t){var e=[];return this.eachLayer(function(i){e.push(i.toGeoJSON(t).geometry.coordinates)}),Zt(this,{type:"MultiPoint",coordinates:e})},toGeoJSON:function(t){var e=this.feature&&this.feature.geometry&&this.feature.geometry.type;if(e==="MultiPoint")return this.toMultiPoint(t);var i=e==="GeometryCollection",o=[];return this.eachLayer(function(n){if(n.toGeoJSON){var r=n.toGeoJSON(t);if(i)o.push(r.geometry);else{var s=ce(r);s.type==="FeatureCollection"?o.push.apply(o,s.features):o.push(s)}}}),i?Zt(this,{geometries:o,type:"GeometryCollection"}):{type:"FeatureCollection",features:o}}});function Ti(t,e){return new xt(t,e)}var Ro=Ti;var At=F.extend({options:{opacity:1,alt:"",interactive:!1,crossOrigin:!1,errorOverlayUrl:"",zIndex:1,className:""},initialize:function(t,e,i){this._url=t,this._bounds=O(e),x(this,i)},onAdd:function(){this._image||(this._initImage(),this.options.opacity<1&&this._updateOpacity()),this.options.interactive&&(f(this._image,"leaflet-interactive"),this.addInteractiveTarget(this._image)),this.getPane().appendChild(this._image),this._reset()},onRemove:function(){M(this._image),this.options.interactive&&this.removeInteractiveTarget(this._image)},setOpacity:function(t){return this.options.opacity=t,this._image&&this._updateOpacity(),this},setStyle:function(t){return t.opacity&&this.setOpacity(t.opacity),this},bringToFront:function(){return this._map&&mt(this._image),this},bringToBack:function(){return this._map&&ft(this._image),this},setUrl:function(t){return this._url=t,this._image&&(this._image.src=t),this},setBounds:function(t){return this._bounds=O(t),this._map&&this._reset(),this},getEvents:function(){var t={zoom:this._reset,viewreset:this._reset};return this._zoomAnimated&&(t.zoomanim=this._animateZoom),t},setZIndex:function(t){return this.options.zIndex=t,this._updateZIndex(),this},getBounds:function(){return this._bounds},getElement:function(){return this._image},_initImage:function(){var t=this._url.tagName==="IMG",e=this._image=t?this._url:y("img");if(f(e,"leaflet-image-layer"),this._zoomAnimated&&f(e,"leaflet-zoom-animated"),this.options.className&&f(e,this.options.className),e.onselectstart=z,e.onmousemove=z,e.onload=U(this.fire,this,"load"),e.onerror=U(this._overlayOnError,this,"error"),(this.options.crossOrigin||this.options.crossOrigin==="")&&(e.crossOrigin=this.options.crossOrigin===!0?"":this.options.crossOrigin),this.options.zIndex&&this._updateZIndex(),t){this._url=e.src;return}e.src=this._url,e.alt=this.options.alt},_animateZoom:function(t){var e=this._map.getZoomScale(t.zoom),i=this._map._latLngBoundsToNewLayerBounds(this._bounds,t.zoom,t.center).min;ct(this._image,i,e)},_reset:function(){var t=this._image,e=new E(this._map.latLngToLayerPoint(this._bounds.getNorthWest()),this._map.latLngToLayerPoint(this._bounds.getSouthEast())),i=e.getSize();S(t,e.min),t.style.width=i.x+"px",t.style.height=i.y+"px"},_updateOpacity:function(){V(this._image,this.options.opacity)},_updateZIndex:function(){this._image&&this.options.zIndex!==void 0&&this.options.zIndex!==null&&(this._image.style.zIndex=this.options.zIndex)},_overlayOnError:function(){this.fire("error");var t=this.options.errorOverlayUrl;t&&this._url!==t&&(this._url=t,this._image.src=t)},getCenter:function(){return this._bounds.getCenter()}}),Fo=function(t,e,i){return new At(t,e,i)};var Di=At.extend({options:{autoplay:!0,loop:!0,keepAspectRatio:!0,muted:!1,playsInline:!0},_initImage:function(){var t=this._url.tagName==="VIDEO",e=this._image=t?this._url:y("video");if(f(e,"leaflet-image-layer"),this._zoomAnimated&&f(e,"leaflet-zoom-animated"),this.options.className&&f(e,this.options.className),e.onselectstart=z,e.onmousemove=z,e.onloadeddata=U(this.fire,this,"load"),t){for(var i=e.getElementsByTagName("source"),o=[],n=0;n<i.length;n++)o.push(i[n].src);this._url=i.length>0?o:[e.src];return}I(this._url)||(this._url=[this._url]),!this.options.keepAspectRatio&&Object.prototype.hasOwnProperty.call(e.style,"objectFit")&&(e.style.objectFit="fill"),e.autoplay=!!this.options.autoplay,e.loop=!!this.options.loop,e.muted=!!this.options.muted,e.playsInline=!!this.options.playsInline;for(var r=0;r<this._url.length;r++){var s=y("source");s.src=this._url[r],e.appendChild(s)}}});function Ho(t,e,i){return new Di(t,e,i)}var Ui=At.extend({_initImage:function(){var t=this._image=this._url;f(t,"leaflet-image-layer"),this._zoomAnimated&&f(t,"leaflet-zoom-animated"),this.options.className&&f(t,this.options.className),t.onselectstart=z,t.onmousemove=z}});function Wo(t,e,i){return new Ui(t,e,i)}var nt=F.extend({options:{interactive:!1,offset:[0,0],className:"",pane:void 0,content:""},initialize:function(t,e){t&&(t instanceof T||I(t))?(this._latlng=w(t),x(this,e)):(x(this,t),this._source=e),this.options.content&&(this._content=this.options.content)},openOn:function(t){return t=arguments.length?t:this._source._map,t.hasLayer(this)||t.addLayer(this),this},close:function(){return this._map&&this._map.removeLayer(this),this},toggle:function(t){return this._map?this.close():(arguments.length?this._source=t:t=this._source,this._prepareOpen(),this.openOn(t._map)),this},onAdd:function(t){this._zoomAnimated=t._zoomAnimated,this._container||this._initLayout(),t._fadeAnimated&&V(this._container,0),clearTimeout(this._removeTimeout),this.getPane().appendChild(this._container),this.update(),t._fadeAnimated&&V(this._container,1),this.bringToFront(),this.options.interactive&&(f(this._container,"leaflet-interactive"),this.addInteractiveTarget(this._container))},onRemove:function(t){t._fadeAnimated?(V(this._container,0),this._removeTimeout=setTimeout(U(M,void 0,this._container),200)):M(this._container),this.options.interactive&&(k(this._container,"leaflet-interactive"),this.removeInteractiveTarget(this._container))},getLatLng:function(){return this._latlng},setLatLng:function(t){return this._latlng=w(t),this._map&&(this._updatePosition(),this._adjustPan()),this},getContent:function(){return this._content},setContent:function(t){return this._content=t,this.update(),this},getElement:function(){return this._container},update:function(){this._map&&(this._container.style.visibility="hidden",this._updateContent(),this._updateLayout(),this._updatePosition(),this._container.style.visibility="",this._adjustPan())},getEvents:function(){var t={zoom:this._updatePosition,viewreset:this._updatePosition};return this._zoomAnimated&&(t.zoomanim=this._animateZoom),t},isOpen:function(){return!!this._map&&this._map.hasLayer(this)},bringToFront:function(){return this._map&&mt(this._container),this},bringToBack:function(){return this._map&&ft(this._container),this},_prepareOpen:function(t){var e=this._source;if(!e._map)return!1;if(e instanceof et){e=null;var i=this._source._layers;for(var o in i)if(i[o]._map){e=i[o];break}if(!e)return!1;this._source=e}if(!t)if(e.getCenter)t=e.getCenter();else if(e.getLatLng)t=e.getLatLng();else if(e.getBounds)t=e.getBounds().getCenter();else throw new Error("Unable to get source layer LatLng.");return this.setLatLng(t),this._map&&this.update(),!0},_updateContent:function(){if(this._content){var t=this._contentNode,e=typeof this._content=="function"?this._content(this._source||this):this._content;if(typeof e=="string")t.innerHTML=e;else{for(;t.hasChildNodes();)t.removeChild(t.firstChild);t.appendChild(e)}this.fire("contentupdate")}},_updatePosition:function(){if(this._map){var t=this._map.latLngToLayerPoint(this._latlng),e=d(this.options.offset),i=this._getAnchor();this._zoomAnimated?S(this._container,t.add(i)):e=e.add(t).add(i);var o=this._containerBottom=-e.y,n=this._containerLeft=-Math.round(this._containerWidth/2)+e.x;this._container.style.bottom=o+"px",this._container.style.left=n+"px"}},_getAnchor:function(){return[0,0]}});_.include({_initOverlay:function(t,e,i,o){var n=e;return n instanceof t||(n=new t(o).setContent(e)),i&&n.setLatLng(i),n}});F.include({_initOverlay:function(t,e,i,o){var n=i;return n instanceof t?(x(n,o),n._source=this):(n=e&&!o?e:new t(o,this),n.setContent(i)),n}});var de=nt.extend({options:{pane:"popupPane",offset:[0,7],maxWidth:300,minWidth:50,maxHeight:null,autoPan:!0,autoPanPaddingTopLeft:null,autoPanPaddingBottomRight:null,autoPanPadding:[5,5],keepInView:!1,closeButton:!0,autoClose:!0,closeOnEscapeKey:!0,className:""},openOn:function(t){return t=arguments.length?t:this._source._map,!t.hasLayer(this)&&t._popup&&t._popup.options.autoClose&&t.removeLayer(t._popup),t._popup=this,nt.prototype.openOn.call(this,t)},onAdd:function(t){nt.prototype.onAdd.call(this,t),t.fire("popupopen",{popup:this}),this._source&&(this._source.fire("popupopen",{popup:this},!0),this._source instanceof ot||this._source.on("preclick",Dt))},onRemove:function(t){nt.prototype.onRemove.call(this,t),t.fire("popupclose",{popup:this}),this._source&&(this._source.fire("popupclose",{popup:this},!0),this._source instanceof ot||this._source.off("preclick",Dt))},getEvents:function(){var t=nt.prototype.getEvents.call(this);return(this.options.closeOnClick!==void 0?this.options.closeOnClick:this._map.options.closePopupOnClick)&&(t.preclick=this.close),this.options.keepInView&&(t.moveend=this._adjustPan),t},_initLayout:function(){var t="leaflet-popup",e=this._container=y("div",t+" "+(this.options.className||"")+" leaflet-zoom-animated"),i=this._wrapper=y("div",t+"-content-wrapper",e);if(this._contentNode=y("div",t+"-content",i),wt(e),se(this._contentNode),p(e,"contextmenu",Dt),this._tipContainer=y("div",t+"-tip-container",e),this._tip=y("div",t+"-tip",this._tipContainer),this.options.closeButton){var o=this._closeButton=y("a",t+"-close-button",e);o.setAttribute("role","button"),o.setAttribute("aria-label","Close popup"),o.href="#close",o.innerHTML='<span aria-hidden="true">&#215;</span>',p(o,"click",function(n){Z(n),this.close()},this)}},_updateLayout:function(){var t=this._contentNode,e=t.style;e.width="",e.whiteSpace="nowrap";var i=t.offsetWidth;i=Math.min(i,this.options.maxWidth),i=Math.max(i,this.options.minWidth),e.width=i+1+"px",e.whiteSpace="",e.height="";var o=t.offsetHeight,n=this.options.maxHeight,r="leaflet-popup-scrolled";n&&o>n?(e.height=n+"px",f(t,r)):k(t,r),this._containerWidth=this._container.offsetWidth},_animateZoom:function(t){var e=this._map._latLngToNewLayerPoint(this._latlng,t.zoom,t.center),i=this._getAnchor();S(this._container,e.add(i))},_adjustPan:function(){if(this.options.autoPan){if(this._map._panAnim&&this._map._panAnim.stop(),this._autopanning){this._autopanning=!1;return}var t=this._map,e=parseInt(Tt(this._container,"marginBottom"),10)||0,i=this._container.offsetHeight+e,o=this._containerWidth,n=new c(this._containerLeft,-i-this._containerBottom);n._add(ht(this._container));var r=t.layerPointToContainerPoint(n),s=d(this.options.autoPanPadding),a=d(this.options.autoPanPaddingTopLeft||s),h=d(this.options.autoPanPaddingBottomRight||s),l=t.getSize(),m=0,v=0;r.x+o+h.x>l.x&&(m=r.x+o-l.x+h.x),r.x-m-a.x<0&&(m=r.x-a.x),r.y+i+h.y>l.y&&(v=r.y+i-l.y+h.y),r.y-v-a.y<0&&(v=r.y-a.y),(m||v)&&(this.options.keepInView&&(this._autopanning=!0),t.fire("autopanstart").panBy([m,v]))}},_getAnchor:function(){return d(this._source&&this._source._getPopupAnchor?this._source._getPopupAnchor():[0,0])}}),Go=function(t,e){return new de(t,e)};_.mergeOptions({closePopupOnClick:!0});_.include({openPopup:function(t,e,i){return this._initOverlay(de,t,e,i).openOn(this),this},closePopup:function(t){return t=arguments.length?t:this._popup,t&&t.close(),this}});F.include({bindPopup:function(t,e){return this._popup=this._initOverlay(de,this._popup,t,e),this._popupHandlersAdded||(this.on({click:this._openPopup,keypress:this._onKeyPress,remove:this.closePopup,move:this._movePopup}),this._popupHandlersAdded=!0),this},unbindPopup:function(){return this._popup&&(this.off({click:this._openPopup,keypress:this._onKeyPress,remove:this.closePopup,move:this._movePopup}),this._popupHandlersAdded=!1,this._popup=null),this},openPopup:function(t){return this._popup&&(this instanceof et||(this._popup._source=this),this._popup._prepareOpen(t||this._latlng)&&this._popup.openOn(this._map)),this},closePopup:function(){return this._popup&&this._popup.close(),this},togglePopup:function(){return this._popup&&this._popup.toggle(this),this},isPopupOpen:function(){return this._popup?this._popup.isOpen():!1},setPopupContent:function(t){return this._popup&&this._popup.setContent(t),this},getPopup:function(){return this._popup},_openPopup:function(t){if(!(!this._popup||!this._map)){lt(t);var e=t.layer||t.target;if(this._popup._source===e&&!(e instanceof ot)){this._map.hasLayer(this._popup)?this.closePopup():this.openPopup(t.latlng);return}this._popup._source=e,this.openPopup(t.latlng)}},_movePopup:function(t){this._popup.setLatLng(t.latlng)},_onKeyPress:function(t){t.originalEvent.keyCode===13&&this._openPopup(t)}});var pe=nt.extend({options:{pane:"tooltipPane",offset:[0,0],direction:"auto",permanent:!1,sticky:!1,opacity:.9},onAdd:function(t){nt.prototype.onAdd.call(this,t),this.setOpacity(this.options.opacity),t.fire("tooltipopen",{tooltip:this}),this._source&&(this.addEventParent(this._source),this._source.fire("tooltipopen",{tooltip:this},!0))},onRemove:function(t){nt.prototype.onRemove.call(this,t),t.fire("tooltipclose",{tooltip:this}),this._source&&(this.removeEventParent(this._source),this._source.fire("tooltipclose",{tooltip:this},!0))},getEvents:function(){var t=nt.prototype.getEvents.call(this);return this.options.permanent||(t.preclick=this.close),t},_initLayout:function(){var t="leaflet-tooltip",e=t+" "+(this.options.className||"")+" leaflet-zoom-"+(this._zoomAnimated?"animated":"hide");this._contentNode=this._container=y("div",e),this._container.setAttribute("role","tooltip"),this._container.setAttribute("id","leaflet-tooltip-"+P(this))},_updateLayout:function(){},_adjustPan:function(){},_setPosition:function(t){var e,i,o=this._map,n=this._container,r=o.latLngToContainerPoint(o.getCenter()),s=o.layerPointToContainerPoint(t),a=this.options.direction,h=n.offsetWidth,l=n.offsetHeight,m=d(this.options.offset),v=this._getAnchor();a==="top"?(e=h/2,i=l):a==="bottom"?(e=h/2,i=0):a==="center"?(e=h/2,i=l/2):a==="right"?(e=0,i=l/2):a==="left"?(e=h,i=l/2):s.x<r.x?(a="right",e=0,i=l/2):(a="left",e=h+(m.x+v.x)*2,i=l/2),t=t.subtract(d(e,i,!0)).add(m).add(v),k(n,"leaflet-tooltip-right"),k(n,"leaflet-tooltip-left"),k(n,"leaflet-tooltip-top"),k(n,"leaflet-tooltip-bottom"),f(n,"leaflet-tooltip-"+a),S(n,t)},_updatePosition:function(){var t=this._map.latLngToLayerPoint(this._latlng);this._setPosition(t)},setOpacity:function(t){this.options.opacity=t,this._container&&V(this._container,t)},_animateZoom:function(t){var e=this._map._latLngToNewLayerPoint(this._latlng,t.zoom,t.center);this._setPosition(e)},_getAnchor:function(){return d(this._source&&this._source._getTooltipAnchor&&!this.options.sticky?this._source._getTooltipAnchor():[0,0])}}),qo=function(t,e){return new pe(t,e)};_.include({openTooltip:function(t,e,i){return this._initOverlay(pe,t,e,i).openOn(this),this},closeTooltip:function(t){return t.close(),this}});F.include({bindTooltip:function(t,e){return this._tooltip&&this.isTooltipOpen()&&this.unbindTooltip(),this._tooltip=this._initOverlay(pe,this._tooltip,t,e),this._initTooltipInteractions(),this._tooltip.options.permanent&&this._map&&this._map.hasLayer(this)&&this.openTooltip(),this},unbindTooltip:function(){return this._tooltip&&(this._initTooltipInteractions(!0),this.closeTooltip(),this._tooltip=null),this},_initTooltipInteractions:function(t){if(!(!t&&this._tooltipHandlersAdded)){var e=t?"off":"on",i={remove:this.closeTooltip,move:this._moveTooltip};this._tooltip.options.permanent?i.add=this._openTooltip:(i.mouseover=this._openTooltip,i.mouseout=this.closeTooltip,i.click=this._openTooltip,this._map?this._addFocusListeners():i.add=this._addFocusListeners),this._tooltip.options.sticky&&(i.mousemove=this._moveTooltip),this[e](i),this._tooltipHandlersAdded=!t}},openTooltip:function(t){return this._tooltip&&(this instanceof et||(this._tooltip._source=this),this._tooltip._prepareOpen(t)&&(this._tooltip.openOn(this._map),this.getElement?this._setAriaDescribedByOnLayer(this):this.eachLayer&&this.eachLayer(this._setAriaDescribedByOnLayer,this))),this},closeTooltip:function(){if(this._tooltip)return this._tooltip.close()},toggleTooltip:function(){return this._tooltip&&this._tooltip.toggle(this),this},isTooltipOpen:function(){return this._tooltip.isOpen()},setTooltipContent:function(t){return this._tooltip&&this._tooltip.setContent(t),this},getTooltip:function(){return this._tooltip},_addFocusListeners:function(){this.getElement?this._addFocusListenersOnLayer(this):this.eachLayer&&this.eachLayer(this._addFocusListenersOnLayer,this)},_addFocusListenersOnLayer:function(t){var e=typeof t.getElement=="function"&&t.getElement();e&&(p(e,"focus",function(){this._tooltip._source=t,this.openTooltip()},this),p(e,"blur",this.closeTooltip,this))},_setAriaDescribedByOnLayer:function(t){var e=typeof t.getElement=="function"&&t.getElement();e&&e.setAttribute("aria-describedby",this._tooltip._container.id)},_openTooltip:function(t){if(!(!this._tooltip||!this._map)){if(this._map.dragging&&this._map.dragging.moving()&&!this._openOnceFlag){this._openOnceFlag=!0;var e=this;this._map.once("moveend",function(){e._openOnceFlag=!1,e._openTooltip(t)});return}this._tooltip._source=t.layer||t.target,this.openTooltip(this._tooltip.options.sticky?t.latlng:void 0)}},_moveTooltip:function(t){var e=t.latlng,i,o;this._tooltip.options.sticky&&t.originalEvent&&(i=this._map.mouseEventToContainerPoint(t.originalEvent),o=this._map.containerPointToLayerPoint(i),e=this._map.layerPointToLatLng(o)),this._tooltip.setLatLng(e)}});var Ei=Pt.extend({options:{iconSize:[12,12],html:!1,bgPos:null,className:"leaflet-div-icon"},createIcon:function(t){var e=t&&t.tagName==="DIV"?t:document.createElement("div"),i=this.options;if(i.html instanceof Element?(Wt(e),e.appendChild(i.html)):e.innerHTML=i.html!==!1?i.html:"",i.bgPos){var o=d(i.bgPos);e.style.backgroundPosition=-o.x+"px "+-o.y+"px"}return this._setIconStyles(e,"icon"),e},createShadow:function(){return null}});function jo(t){return new Ei(t)}Pt.Default=Ot;var It=F.extend({options:{tileSize:256,opacity:1,updateWhenIdle:u.mobile,updateWhenZooming:!0,updateInterval:200,zIndex:1,bounds:null,minZoom:0,maxZoom:void 0,maxNativeZoom:void 0,minNativeZoom:void 0,noWrap:!1,pane:"tilePane",className:"",keepBuffer:2},initialize:function(t){x(this,t)},onAdd:function(){this._initContainer(),this._levels={},this._tiles={},this._resetView()},beforeAdd:function(t){t._addZoomLimit(this)},onRemove:function(t){this._removeAllTiles(),M(this._container),t._removeZoomLimit(this),this._container=null,this._tileZoom=void 0},bringToFront:function(){return this._map&&(mt(this._container),this._setAutoZIndex(Math.max)),this},bringToBack:function(){return this._map&&(ft(this._container),this._setAutoZIndex(Math.min)),this},getContainer:function(){return this._container},setOpacity:function(t){return this.options.opacity=t,this._updateOpacity(),this},setZIndex:function(t){return this.options.zIndex=t,this._updateZIndex(),this},isLoading:function(){return this._loading},redraw:function(){if(this._map){this._removeAllTiles();var t=this._clampZoom(this._map.getZoom());t!==this._tileZoom&&(this._tileZoom=t,this._updateLevels()),this._update()}return this},getEvents:function(){var t={viewprereset:this._invalidateAll,viewreset:this._resetView,zoom:this._resetView,moveend:this._onMoveEnd};return this.options.updateWhenIdle||(this._onMove||(this._onMove=qe(this._onMoveEnd,this.options.updateInterval,this)),t.move=this._onMove),this._zoomAnimated&&(t.zoomanim=this._animateZoom),t},createTile:function(){return document.createElement("div")},getTileSize:function(){var t=this.options.tileSize;return t instanceof c?t:new c(t,t)},_updateZIndex:function(){this._container&&this.options.zIndex!==void 0&&this.options.zIndex!==null&&(this._container.style.zIndex=this.options.zIndex)},_setAutoZIndex:function(t){for(var e=this.getPane().children,i=-t(-1/0,1/0),o=0,n=e.length,r;o<n;o++)r=e[o].style.zIndex,e[o]!==this._container&&r&&(i=t(i,+r));isFinite(i)&&(this.options.zIndex=i+t(-1,1),this._updateZIndex())},_updateOpacity:function(){if(this._map&&!u.ielt9){V(this._container,this.options.opacity);var t=+new Date,e=!1,i=!1;for(var o in this._tiles){var n=this._tiles[o];if(!(!n.current||!n.loaded)){var r=Math.min(1,(t-n.loaded)/200);V(n.el,r),r<1?e=!0:(n.active?i=!0:this._onOpaqueTile(n),n.active=!0)}}i&&!this._noPrune&&this._pruneTiles(),e&&(j(this._fadeFrame),this._fadeFrame=N(this._updateOpacity,this))}},_onOpaqueTile:z,_initContainer:function(){this._container||(this._container=y("div","leaflet-layer "+(this.options.className||"")),this._updateZIndex(),this.options.opacity<1&&this._updateOpacity(),this.getPane().appendChild(this._container))},_updateLevels:function(){var t=this._tileZoom,e=this.options.maxZoom;if(t!==void 0){for(var i in this._levels)i=Number(i),this._levels[i].el.children.length||i===t?(this._levels[i].el.style.zIndex=e-Math.abs(t-i),this._onUpdateLevel(i)):(M(this._levels[i].el),this._removeTilesAtZoom(i),this._onRemoveLevel(i),delete this._levels[i]);var o=this._levels[t],n=this._map;return o||(o=this._levels[t]={},o.el=y("div","leaflet-tile-container leaflet-zoom-animated",this._container),o.el.style.zIndex=e,o.origin=n.project(n.unproject(n.getPixelOrigin()),t).round(),o.zoom=t,this._setZoomTransform(o,n.getCenter(),n.getZoom()),z(o.el.offsetWidth),this._onCreateLevel(o)),this._level=o,o}},_onUpdateLevel:z,_onRemoveLevel:z,_onCreateLevel:z,_pruneTiles:function(){if(this._map){var t,e,i=this._map.getZoom();if(i>this.options.maxZoom||i<this.options.minZoom){this._removeAllTiles();return}for(t in this._tiles)e=this._tiles[t],e.retain=e.current;for(t in this._tiles)if(e=this._tiles[t],e.current&&!e.active){var o=e.coords;this._retainParent(o.x,o.y,o.z,o.z-5)||this._retainChildren(o.x,o.y,o.z,o.z+2)}for(t in this._tiles)this._tiles[t].retain||this._removeTile(t)}},_removeTilesAtZoom:function(t){for(var e in this._tiles)this._tiles[e].coords.z===t&&this._removeTile(e)},_removeAllTiles:function(){for(var t in this._tiles)this._removeTile(t)},_invalidateAll:function(){for(var t in this._levels)M(this._levels[t].el),this._onRemoveLevel(Number(t)),delete this._levels[t];this._removeAllTiles(),this._tileZoom=void 0},_retainParent:function(t,e,i,o){var n=Math.floor(t/2),r=Math.floor(e/2),s=i-1,a=new c(+n,+r);a.z=+s;var h=this._tileCoordsToKey(a),l=this._tiles[h];return l&&l.active?(l.retain=!0,!0):(l&&l.loaded&&(l.retain=!0),s>o?this._retainParent(n,r,s,o):!1)},_retainChildren:function(t,e,i,o){for(var n=2*t;n<2*t+2;n++)for(var r=2*e;r<2*e+2;r++){var s=new c(n,r);s.z=i+1;var a=this._tileCoordsToKey(s),h=this._tiles[a];if(h&&h.active){h.retain=!0;continue}else h&&h.loaded&&(h.retain=!0);i+1<o&&this._retainChildren(n,r,i+1,o)}},_resetView:function(t){var e=t&&(t.pinch||t.flyTo);this._setView(this._map.getCenter(),this._map.getZoom(),e,e)},_animateZoom:function(t){this._setView(t.center,t.zoom,!0,t.noUpdate)},_clampZoom:function(t){var e=this.options;return e.minNativeZoom!==void 0&&t<e.minNativeZoom?e.minNativeZoom:e.maxNativeZoom!==void 0&&e.maxNativeZoom<t?e.maxNativeZoom:t},_setView:function(t,e,i,o){var n=Math.round(e);this.options.maxZoom!==void 0&&n>this.options.maxZoom||this.options.minZoom!==void 0&&n<this.options.minZoom?n=void 0:n=this._clampZoom(n);var r=this.options.updateWhenZooming&&n!==this._tileZoom;(!o||r)&&(this._tileZoom=n,this._abortLoading&&this._abortLoading(),this._updateLevels(),this._resetGrid(),n!==void 0&&this._update(t),i||this._pruneTiles(),this._noPrune=!!i),this._setZoomTransforms(t,e)},_setZoomTransforms:function(t,e){for(var i in this._levels)this._setZoomTransform(this._levels[i],t,e)},_setZoomTransform:function(t,e,i){var o=this._map.getZoomScale(i,t.zoom),n=t.origin.multiplyBy(o).subtract(this._map._getNewPixelOrigin(e,i)).round();u.any3d?ct(t.el,n,o):S(t.el,n)},_resetGrid:function(){var t=this._map,e=t.options.crs,i=this._tileSize=this.getTileSize(),o=this._tileZoom,n=this._map.getPixelWorldBounds(this._tileZoom);n&&(this._globalTileRange=this._pxBoundsToTileRange(n)),this._wrapX=e.wrapLng&&!this.options.noWrap&&[Math.floor(t.project([0,e.wrapLng[0]],o).x/i.x),Math.ceil(t.project([0,e.wrapLng[1]],o).x/i.y)],this._wrapY=e.wrapLat&&!this.options.noWrap&&[Math.floor(t.project([e.wrapLat[0],0],o).y/i.x),Math.ceil(t.project([e.wrapLat[1],0],o).y/i.y)]},_onMoveEnd:function(){!this._map||this._map._animatingZoom||this._update()},_getTiledPixelBounds:function(t){var e=this._map,i=e._animatingZoom?Math.max(e._animateToZoom,e.getZoom()):e.getZoom(),o=e.getZoomScale(i,this._tileZoom),n=e.project(t,this._tileZoom).floor(),r=e.getSize().divideBy(o*2);return new E(n.subtract(r),n.add(r))},_update:function(t){var e=this._map;if(e){var i=this._clampZoom(e.getZoom());if(t===void 0&&(t=e.getCenter()),this._tileZoom!==void 0){var o=this._getTiledPixelBounds(t),n=this._pxBoundsToTileRange(o),r=n.getCenter(),s=[],a=this.options.keepBuffer,h=new E(n.getBottomLeft().subtract([a,-a]),n.getTopRight().add([a,-a]));if(!(isFinite(n.min.x)&&isFinite(n.min.y)&&isFinite(n.max.x)&&isFinite(n.max.y)))throw new Error("Attempted to load an infinite number of tiles");for(var l in this._tiles){var m=this._tiles[l].coords;(m.z!==this._tileZoom||!h.contains(new c(m.x,m.y)))&&(this._tiles[l].current=!1)}if(Math.abs(i-this._tileZoom)>1){this._setView(t,i);return}for(var v=n.min.y;v<=n.max.y;v++)for(var D=n.min.x;D<=n.max.x;D++){var X=new c(D,v);if(X.z=this._tileZoom,!!this._isValidTile(X)){var q=this._tiles[this._tileCoordsToKey(X)];q?q.current=!0:s.push(X)}}if(s.sort(function(J,Nt){return J.distanceTo(r)-Nt.distanceTo(r)}),s.length!==0){this._loading||(this._loading=!0,this.fire("loading"));var st=document.createDocumentFragment();for(D=0;D<s.length;D++)this._addTile(s[D],st);this._level.el.appendChild(st)}}}},_isValidTile:function(t){var e=this._map.options.crs;if(!e.infinite){var i=this._globalTileRange;if(!e.wrapLng&&(t.x<i.min.x||t.x>i.max.x)||!e.wrapLat&&(t.y<i.min.y||t.y>i.max.y))return!1}if(!this.options.bounds)return!0;var o=this._tileCoordsToBounds(t);return O(this.options.bounds).overlaps(o)},_keyToBounds:function(t){return this._tileCoordsToBounds(this._keyToTileCoords(t))},_tileCoordsToNwSe:function(t){var e=this._map,i=this.getTileSize(),o=t.scaleBy(i),n=o.add(i),r=e.unproject(o,t.z),s=e.unproject(n,t.z);return[r,s]},_tileCoordsToBounds:function(t){var e=this._tileCoordsToNwSe(t),i=new A(e[0],e[1]);return this.options.noWrap||(i=this._map.wrapLatLngBounds(i)),i},_tileCoordsToKey:function(t){return t.x+":"+t.y+":"+t.z},_keyToTileCoords:function(t){var e=t.split(":"),i=new c(+e[0],+e[1]);return i.z=+e[2],i},_removeTile:function(t){var e=this._tiles[t];e&&(M(e.el),delete this._tiles[t],this.fire("tileunload",{tile:e.el,coords:this._keyToTileCoords(t)}))},_initTile:function(t){f(t,"leaflet-tile");var e=this.getTileSize();t.style.width=e.x+"px",t.style.height=e.y+"px",t.onselectstart=z,t.onmousemove=z,u.ielt9&&this.options.opacity<1&&V(t,this.options.opacity)},_addTile:function(t,e){var i=this._getTilePos(t),o=this._tileCoordsToKey(t),n=this.createTile(this._wrapCoords(t),U(this._tileReady,this,t));this._initTile(n),this.createTile.length<2&&N(U(this._tileReady,this,t,null,n)),S(n,i),this._tiles[o]={el:n,coords:t,current:!0},e.appendChild(n),this.fire("tileloadstart",{tile:n,coords:t})},_tileReady:function(t,e,i){e&&this.fire("tileerror",{error:e,tile:i,coords:t});var o=this._tileCoordsToKey(t);i=this._tiles[o],i&&(i.loaded=+new Date,this._map._fadeAnimated?(V(i.el,0),j(this._fadeFrame),this._fadeFrame=N(this._updateOpacity,this)):(i.active=!0,this._pruneTiles()),e||(f(i.el,"leaflet-tile-loaded"),this.fire("tileload",{tile:i.el,coords:t})),this._noTilesToLoad()&&(this._loading=!1,this.fire("load"),u.ielt9||!this._map._fadeAnimated?N(this._pruneTiles,this):setTimeout(U(this._pruneTiles,this),250)))},_getTilePos:function(t){return t.scaleBy(this.getTileSize()).subtract(this._level.origin)},_wrapCoords:function(t){var e=new c(this._wrapX?Mt(t.x,this._wrapX):t.x,this._wrapY?Mt(t.y,this._wrapY):t.y);return e.z=t.z,e},_pxBoundsToTileRange:function(t){var e=this.getTileSize();return new E(t.min.unscaleBy(e).floor(),t.max.unscaleBy(e).ceil().subtract([1,1]))},_noTilesToLoad:function(){for(var t in this._tiles)if(!this._tiles[t].loaded)return!1;return!0}});function Vo(t){return new It(t)}var Et=It.extend({options:{minZoom:0,maxZoom:18,subdomains:"abc",errorTileUrl:"",zoomOffset:0,tms:!1,zoomReverse:!1,detectRetina:!1,crossOrigin:!1,referrerPolicy:!1},initialize:function(t,e){this._url=t,e=x(this,e),e.detectRetina&&u.retina&&e.maxZoom>0?(e.tileSize=Math.floor(e.tileSize/2),e.zoomReverse?(e.zoomOffset--,e.minZoom=Math.min(e.maxZoom,e.minZoom+1)):(e.zoomOffset++,e.maxZoom=Math.max(e.minZoom,e.maxZoom-1)),e.minZoom=Math.max(0,e.minZoom)):e.zoomReverse?e.minZoom=Math.min(e.maxZoom,e.minZoom):e.maxZoom=Math.max(e.minZoom,e.maxZoom),typeof e.subdomains=="string"&&(e.subdomains=e.subdomains.split("")),this.on("tileunload",this._onTileRemove)},setUrl:function(t,e){return this._url===t&&e===void 0&&(e=!0),this._url=t,e||this.redraw(),this},createTile:function(t,e){var i=document.createElement("img");return p(i,"load",U(this._tileOnLoad,this,e,i)),p(i,"error",U(this._tileOnError,this,e,i)),(this.options.crossOrigin||this.options.crossOrigin==="")&&(i.crossOrigin=this.options.crossOrigin===!0?"":this.options.crossOrigin),typeof this.options.referrerPolicy=="string"&&(i.referrerPolicy=this.options.referrerPolicy),i.alt="",i.src=this.getTileUrl(t),i},getTileUrl:function(t){var e={r:u.retina?"@2x":"",s:this._getSubdomain(t),x:t.x,y:t.y,z:this._getZoomForUrl()};if(this._map&&!this._map.options.crs.infinite){var i=this._globalTileRange.max.y-t.y;this.options.tms&&(e.y=i),e["-y"]=i}return Ve(this._url,b(e,this.options))},_tileOnLoad:function(t,e){u.ielt9?setTimeout(U(t,this,null,e),0):t(null,e)},_tileOnError:function(t,e,i){var o=this.options.errorTileUrl;o&&e.getAttribute("src")!==o&&(e.src=o),t(i,e)},_onTileRemove:function(t){t.tile.onload=null},_getZoomForUrl:function(){var t=this._tileZoom,e=this.options.maxZoom,i=this.options.zoomReverse,o=this.options.zoomOffset;return i&&(t=e-t),t+o},_getSubdomain:function(t){var e=Math.abs(t.x+t.y)%this.options.subdomains.length;return this.options.subdomains[e]},_abortLoading:function(){var t,e;for(t in this._tiles)if(this._tiles[t].coords.z!==this._tileZoom&&(e=this._tiles[t].el,e.onload=z,e.onerror=z,!e.complete)){e.src=$t;var i=this._tiles[t].coords;M(e),delete this._tiles[t],this.fire("tileabort",{tile:e,coords:i})}},_removeTile:function(t){var e=this._tiles[t];if(e)return e.el.setAttribute("src",$t),It.prototype._removeTile.call(this,t)},_tileReady:function(t,e,i){if(!(!this._map||i&&i.getAttribute("src")===$t))return It.prototype._tileReady.call(this,t,e,i)}});function Ci(t,e){return new Et(t,e)}var Mi=Et.extend({defaultWmsParams:{service:"WMS",request:"GetMap",layers:"",styles:"",format:"image/jpeg",transparent:!1,version:"1.1.1"},options:{crs:null,uppercase:!1},initialize:function(t,e){this._url=t;var i=b({},this.defaultWmsParams);for(var o in e)o in this.options||(i[o]=e[o]);e=x(this,e);var n=e.detectRetina&&u.retina?2:1,r=this.getTileSize();i.width=r.x*n,i.height=r.y*n,this.wmsParams=i},onAdd:function(t){this._crs=this.options.crs||t.options.crs,this._wmsVersion=parseFloat(this.wmsParams.version);var e=this._wmsVersion>=1.3?"crs":"srs";this.wmsParams[e]=this._crs.code,Et.prototype.onAdd.call(this,t)},getTileUrl:function(t){var e=this._tileCoordsToNwSe(t),i=this._crs,o=W(i.project(e[0]),i.project(e[1])),n=o.min,r=o.max,s=(this._wmsVersion>=1.3&&this._crs===Ze?[n.y,n.x,r.y,r.x]:[n.x,n.y,r.x,r.y]).join(","),a=Et.prototype.getTileUrl.call(this,t);return a+je(this.wmsParams,a,this.options.uppercase)+(this.options.uppercase?"&BBOX=":"&bbox=")+s},setParams:function(t,e){return b(this.wmsParams,t),e||this.redraw(),this}});function Ko(t,e){return new Mi(t,e)}Et.WMS=Mi;Ci.wms=Ko;var rt=F.extend({options:{padding:.1},initialize:function(t){x(this,t),P(this),this._layers=this._layers||{}},onAdd:function(){this._container||(this._initContainer(),f(this._container,"leaflet-zoom-animated")),this.getPane().appendChild(this._container),this._update(),this.on("update",this._updatePaths,this)},onRemove:function(){this.off("update",this._updatePaths,this),this._destroyContainer()},getEvents:function(){var t={viewreset:this._reset,zoom:this._onZoom,moveend:this._update,zoomend:this._onZoomEnd};return this._zoomAnimated&&(t.zoomanim=this._onAnimZoom),t},_onAnimZoom:function(t){this._updateTransform(t.center,t.zoom)},_onZoom:function(){this._updateTransform(this._map.getCenter(),this._map.getZoom())},_updateTransform:function(t,e){var i=this._map.getZoomScale(e,this._zoom),o=this._map.getSize().multiplyBy(.5+this.options.padding),n=this._map.project(this._center,e),r=o.multiplyBy(-i).add(n).subtract(this._map._getNewPixelOrigin(t,e));u.any3d?ct(this._container,r,i):S(this._container,r)},_reset:function(){this._update(),this._updateTransform(this._center,this._zoom);for(var t in this._layers)this._layers[t]._reset()},_onZoomEnd:function(){for(var t in this._layers)this._layers[t]._project()},_updatePaths:function(){for(var t in this._layers)this._layers[t]._update()},_update:function(){var t=this.options.padding,e=this._map.getSize(),i=this._map.containerPointToLayerPoint(e.multiplyBy(-t)).round();this._bounds=new E(i,i.add(e.multiplyBy(1+t*2)).round()),this._center=this._map.getCenter(),this._zoom=this._map.getZoom()}});var Si=rt.extend({options:{tolerance:0},getEvents:function(){var t=rt.prototype.getEvents.call(this);return t.viewprereset=this._onViewPreReset,t},_onViewPreReset:function(){this._postponeUpdatePaths=!0},onAdd:function(){rt.prototype.onAdd.call(this),this._draw()},_initContainer:function(){var t=this._container=document.createElement("canvas");p(t,"mousemove",this._onMouseMove,this),p(t,"click dblclick mousedown mouseup contextmenu",this._onClick,this),p(t,"mouseout",this._handleMouseOut,this),t._leaflet_disable_events=!0,this._ctx=t.getContext("2d")},_destroyContainer:function(){j(this._redrawRequest),delete this._ctx,M(this._container),C(this._container),delete this._container},_updatePaths:function(){if(!this._postponeUpdatePaths){var t;this._redrawBounds=null;for(var e in this._layers)t=this._layers[e],t._update();this._redraw()}},_update:function(){if(!(this._map._animatingZoom&&this._bounds)){rt.prototype._update.call(this);var t=this._bounds,e=this._container,i=t.getSize(),o=u.retina?2:1;S(e,t.min),e.width=o*i.x,e.height=o*i.y,e.style.width=i.x+"px",e.style.height=i.y+"px",u.retina&&this._ctx.scale(2,2),this._ctx.translate(-t.min.x,-t.min.y),this.fire("update")}},_reset:function(){rt.prototype._reset.call(this),this._postponeUpdatePaths&&(this._postponeUpdatePaths=!1,this._updatePaths())},_initPath:function(t){this._updateDashArray(t),this._layers[P(t)]=t;var e=t._order={layer:t,prev:this._drawLast,next:null};this._drawLast&&(this._drawLast.next=e),this._drawLast=e,this._drawFirst=this._drawFirst||this._drawLast},_addPath:function(t){this._requestRedraw(t)},_removePath:function(t){var e=t._order,i=e.next,o=e.prev;i?i.prev=o:this._drawLast=o,o?o.next=i:this._drawFirst=i,delete t._order,delete this._layers[P(t)],this._requestRedraw(t)},_updatePath:function(t){this._extendRedrawBounds(t),t._project(),t._update(),this._requestRedraw(t)},_updateStyle:function(t){this._updateDashArray(t),this._requestRedraw(t)},_updateDashArray:function(t){if(typeof t.options.dashArray=="string"){var e=t.options.dashArray.split(/[, ]+/),i=[],o,n;for(n=0;n<e.length;n++){if(o=Number(e[n]),isNaN(o))return;i.push(o)}t.options._dashArray=i}else t.options._dashArray=t.options.dashArray},_requestRedraw:function(t){this._map&&(this._extendRedrawBounds(t),this._redrawRequest=this._redrawRequest||N(this._redraw,this))},_extendRedrawBounds:function(t){if(t._pxBounds){var e=(t.options.weight||0)+1;this._redrawBounds=this._redrawBounds||new E,this._redrawBounds.extend(t._pxBounds.min.subtract([e,e])),this._redrawBounds.extend(t._pxBounds.max.add([e,e]))}},_redraw:function(){this._redrawRequest=null,this._redrawBounds&&(this._redrawBounds.min._floor(),this._redrawBounds.max._ceil()),this._clear(),this._draw(),this._redrawBounds=null},_clear:function(){var t=this._redrawBounds;if(t){var e=t.getSize();this._ctx.clearRect(t.min.x,t.min.y,e.x,e.y)}else this._ctx.save(),this._ctx.setTransform(1,0,0,1,0,0),this._ctx.clearRect(0,0,this._container.width,this._container.height),this._ctx.restore()},_draw:function(){var t,e=this._redrawBounds;if(this._ctx.save(),e){var i=e.getSize();this._ctx.beginPath(),this._ctx.rect(e.min.x,e.min.y,i.x,i.y),this._ctx.clip()}this._drawing=!0;for(var o=this._drawFirst;o;o=o.next)t=o.layer,(!e||t._pxBounds&&t._pxBounds.intersects(e))&&t._updatePath();this._drawing=!1,this._ctx.restore()},_updatePoly:function(t,e){if(this._drawing){var i,o,n,r,s=t._parts,a=s.length,h=this._ctx;if(a){for(h.beginPath(),i=0;i<a;i++){for(o=0,n=s[i].length;o<n;o++)r=s[i][o],h[o?"lineTo":"moveTo"](r.x,r.y);e&&h.closePath()}this._fillStroke(h,t)}}},_updateCircle:function(t){if(!(!this._drawing||t._empty())){var e=t._point,i=this._ctx,o=Math.max(Math.round(t._radius),1),n=(Math.max(Math.round(t._radiusY),1)||o)/o;n!==1&&(i.save(),i.scale(1,n)),i.beginPath(),i.arc(e.x,e.y/n,o,0,Math.PI*2,!1),n!==1&&i.restore(),this._fillStroke(i,t)}},_fillStroke:function(t,e){var i=e.options;i.fill&&(t.globalAlpha=i.fillOpacity,t.fillStyle=i.fillColor||i.color,t.fill(i.fillRule||"evenodd")),i.stroke&&i.weight!==0&&(t.setLineDash&&t.setLineDash(e.options&&e.options._dashArray||[]),t.globalAlpha=i.opacity,t.lineWidth=i.weight,t.strokeStyle=i.color,t.lineCap=i.lineCap,t.lineJoin=i.lineJoin,t.stroke())},_onClick:function(t){for(var e=this._map.mouseEventToLayerPoint(t),i,o,n=this._drawFirst;n;n=n.next)i=n.layer,i.options.interactive&&i._containsPoint(e)&&(!(t.type==="click"||t.type==="preclick")||!this._map._draggableMoved(i))&&(o=i);this._fireEvent(o?[o]:!1,t)},_onMouseMove:function(t){if(!(!this._map||this._map.dragging.moving()||this._map._animatingZoom)){var e=this._map.mouseEventToLayerPoint(t);this._handleMouseHover(t,e)}},_handleMouseOut:function(t){var e=this._hoveredLayer;e&&(k(this._container,"leaflet-interactive"),this._fireEvent([e],t,"mouseout"),this._hoveredLayer=null,this._mouseHoverThrottled=!1)},_handleMouseHover:function(t,e){if(!this._mouseHoverThrottled){for(var i,o,n=this._drawFirst;n;n=n.next)i=n.layer,i.options.interactive&&i._containsPoint(e)&&(o=i);o!==this._hoveredLayer&&(this._handleMouseOut(t),o&&(f(this._container,"leaflet-interactive"),this._fireEvent([o],t,"mouseover"),this._hoveredLayer=o)),this._fireEvent(this._hoveredLayer?[this._hoveredLayer]:!1,t),this._mouseHoverThrottled=!0,setTimeout(U(function(){this._mouseHoverThrottled=!1},this),32)}},_fireEvent:function(t,e,i){this._map._fireDOMEvent(e,i||e.type,t)},_bringToFront:function(t){var e=t._order;if(e){var i=e.next,o=e.prev;if(i)i.prev=o;else return;o?o.next=i:i&&(this._drawFirst=i),e.prev=this._drawLast,this._drawLast.next=e,e.next=null,this._drawLast=e,this._requestRedraw(t)}},_bringToBack:function(t){var e=t._order;if(e){var i=e.next,o=e.prev;if(o)o.next=i;else return;i?i.prev=o:o&&(this._drawLast=o),e.prev=null,e.next=this._drawFirst,this._drawFirst.prev=e,this._drawFirst=e,this._requestRedraw(t)}}});function Ne(t){return u.canvas?new Si(t):null}var Kt=function(){try{return document.namespaces.add("lvml","urn:schemas-microsoft-com:vml"),function(t){return document.createElement("<lvml:"+t+' class="lvml">')}}catch(t){}return function(t){return document.createElement("<"+t+' xmlns="urn:schemas-microsoft.com:vml" class="lvml">')}}(),Yo={_initContainer:function(){this._container=y("div","leaflet-vml-container")},_update:function(){this._map._animatingZoom||(rt.prototype._update.call(this),this.fire("update"))},_initPath:function(t){var e=t._container=Kt("shape");f(e,"leaflet-vml-shape "+(this.options.className||"")),e.coordsize="1 1",t._path=Kt("path"),e.appendChild(t._path),this._updateStyle(t),this._layers[P(t)]=t},_addPath:function(t){var e=t._container;this._container.appendChild(e),t.options.interactive&&t.addInteractiveTarget(e)},_removePath:function(t){var e=t._container;M(e),t.removeInteractiveTarget(e),delete this._layers[P(t)]},_updateStyle:function(t){var e=t._stroke,i=t._fill,o=t.options,n=t._container;n.stroked=!!o.stroke,n.filled=!!o.fill,o.stroke?(e||(e=t._stroke=Kt("stroke")),n.appendChild(e),e.weight=o.weight+"px",e.color=o.color,e.opacity=o.opacity,o.dashArray?e.dashStyle=I(o.dashArray)?o.dashArray.join(" "):o.dashArray.replace(/( *, *)/g," "):e.dashStyle="",e.endcap=o.lineCap.replace("butt","flat"),e.joinstyle=o.lineJoin):e&&(n.removeChild(e),t._stroke=null),o.fill?(i||(i=t._fill=Kt("fill")),n.appendChild(i),i.color=o.fillColor||o.color,i.opacity=o.fillOpacity):i&&(n.removeChild(i),t._fill=null)},_updateCircle:function(t){var e=t._point.round(),i=Math.round(t._radius),o=Math.round(t._radiusY||i);this._setPath(t,t._empty()?"M0 0":"AL "+e.x+","+e.y+" "+i+","+o+" 0,"+65535*360)},_setPath:function(t,e){t._path.v=e},_bringToFront:function(t){mt(t._container)},_bringToBack:function(t){ft(t._container)}};var _e=u.vml?Kt:xe,Yt=rt.extend({_initContainer:function(){this._container=_e("svg"),this._container.setAttribute("pointer-events","none"),this._rootGroup=_e("g"),this._container.appendChild(this._rootGroup)},_destroyContainer:function(){M(this._container),C(this._container),delete this._container,delete this._rootGroup,delete this._svgSize},_update:function(){if(!(this._map._animatingZoom&&this._bounds)){rt.prototype._update.call(this);var t=this._bounds,e=t.getSize(),i=this._container;(!this._svgSize||!this._svgSize.equals(e))&&(this._svgSize=e,i.setAttribute("width",e.x),i.setAttribute("height",e.y)),S(i,t.min),i.setAttribute("viewBox",[t.min.x,t.min.y,e.x,e.y].join(" ")),this.fire("update")}},_initPath:function(t){var e=t._path=_e("path");t.options.className&&f(e,t.options.className),t.options.interactive&&f(e,"leaflet-interactive"),this._updateStyle(t),this._layers[P(t)]=t},_addPath:function(t){this._rootGroup||this._initContainer(),this._rootGroup.appendChild(t._path),t.addInteractiveTarget(t._path)},_removePath:function(t){M(t._path),t.removeInteractiveTarget(t._path),delete this._layers[P(t)]},_updatePath:function(t){t._project(),t._update()},_updateStyle:function(t){var e=t._path,i=t.options;e&&(i.stroke?(e.setAttribute("stroke",i.color),e.setAttribute("stroke-opacity",i.opacity),e.setAttribute("stroke-width",i.weight),e.setAttribute("stroke-linecap",i.lineCap),e.setAttribute("stroke-linejoin",i.lineJoin),i.dashArray?e.setAttribute("stroke-dasharray",i.dashArray):e.removeAttribute("stroke-dasharray"),i.dashOffset?e.setAttribute("stroke-dashoffset",i.dashOffset):e.removeAttribute("stroke-dashoffset")):e.setAttribute("stroke","none"),i.fill?(e.setAttribute("fill",i.fillColor||i.color),e.setAttribute("fill-opacity",i.fillOpacity),e.setAttribute("fill-rule",i.fillRule||"evenodd")):e.setAttribute("fill","none"))},_updatePoly:function(t,e){this._setPath(t,we(t._parts,e))},_updateCircle:function(t){var e=t._point,i=Math.max(Math.round(t._radius),1),o=Math.max(Math.round(t._radiusY),1)||i,n="a"+i+","+o+" 0 1,0 ",r=t._empty()?"M0 0":"M"+(e.x-i)+","+e.y+n+i*2+",0 "+n+-i*2+",0 ";this._setPath(t,r)},_setPath:function(t,e){t._path.setAttribute("d",e)},_bringToFront:function(t){mt(t._path)},_bringToBack:function(t){ft(t._path)}});u.vml&&Yt.include(Yo);function Re(t){return u.svg||u.vml?new Yt(t):null}_.include({getRenderer:function(t){var e=t.options.renderer||this._getPaneRenderer(t.options.pane)||this.options.renderer||this._renderer;return e||(e=this._renderer=this._createRenderer()),this.hasLayer(e)||this.addLayer(e),e},_getPaneRenderer:function(t){if(t==="overlayPane"||t===void 0)return!1;var e=this._paneRenderers[t];return e===void 0&&(e=this._createRenderer({pane:t}),this._paneRenderers[t]=e),e},_createRenderer:function(t){return this.options.preferCanvas&&Ne(t)||Re(t)}});var ki=bt.extend({initialize:function(t,e){bt.prototype.initialize.call(this,this._boundsToLatLngs(t),e)},setBounds:function(t){return this.setLatLngs(this._boundsToLatLngs(t))},_boundsToLatLngs:function(t){return t=O(t),[t.getSouthWest(),t.getNorthWest(),t.getNorthEast(),t.getSouthEast()]}});function Xo(t,e){return new ki(t,e)}Yt.create=_e;Yt.pointsToPath=we;xt.geometryToLayer=ue;xt.coordsToLatLng=Ae;xt.coordsToLatLngs=me;xt.latLngToCoords=Ie;xt.latLngsToCoords=fe;xt.getFeature=Zt;xt.asFeature=ce;_.mergeOptions({boxZoom:!0});var zi=G.extend({initialize:function(t){this._map=t,this._container=t._container,this._pane=t._panes.overlayPane,this._resetStateTimeout=0,t.on("unload",this._destroy,this)},addHooks:function(){p(this._container,"mousedown",this._onMouseDown,this)},removeHooks:function(){C(this._container,"mousedown",this._onMouseDown,this)},moved:function(){return this._moved},_destroy:function(){M(this._pane),delete this._pane},_resetState:function(){this._resetStateTimeout=0,this._moved=!1},_clearDeferredResetState:function(){this._resetStateTimeout!==0&&(clearTimeout(this._resetStateTimeout),this._resetStateTimeout=0)},_onMouseDown:function(t){if(!t.shiftKey||t.which!==1&&t.button!==1)return!1;this._clearDeferredResetState(),this._resetState(),St(),ie(),this._startPoint=this._map.mouseEventToContainerPoint(t),p(document,{contextmenu:lt,mousemove:this._onMouseMove,mouseup:this._onMouseUp,keydown:this._onKeyDown},this)},_onMouseMove:function(t){this._moved||(this._moved=!0,this._box=y("div","leaflet-zoom-box",this._container),f(this._container,"leaflet-crosshair"),this._map.fire("boxzoomstart")),this._point=this._map.mouseEventToContainerPoint(t);var e=new E(this._point,this._startPoint),i=e.getSize();S(this._box,e.min),this._box.style.width=i.x+"px",this._box.style.height=i.y+"px"},_finish:function(){this._moved&&(M(this._box),k(this._container,"leaflet-crosshair")),kt(),oe(),C(document,{contextmenu:lt,mousemove:this._onMouseMove,mouseup:this._onMouseUp,keydown:this._onKeyDown},this)},_onMouseUp:function(t){if(!(t.which!==1&&t.button!==1)&&(this._finish(),!!this._moved)){this._clearDeferredResetState(),this._resetStateTimeout=setTimeout(U(this._resetState,this),0);var e=new A(this._map.containerPointToLatLng(this._startPoint),this._map.containerPointToLatLng(this._point));this._map.fitBounds(e).fire("boxzoomend",{boxZoomBounds:e})}},_onKeyDown:function(t){t.keyCode===27&&(this._finish(),this._clearDeferredResetState(),this._resetState())}});_.addInitHook("addHandler","boxZoom",zi);_.mergeOptions({doubleClickZoom:!0});var Oi=G.extend({addHooks:function(){this._map.on("dblclick",this._onDoubleClick,this)},removeHooks:function(){this._map.off("dblclick",this._onDoubleClick,this)},_onDoubleClick:function(t){var e=this._map,i=e.getZoom(),o=e.options.zoomDelta,n=t.originalEvent.shiftKey?i-o:i+o;e.options.doubleClickZoom==="center"?e.setZoom(n):e.setZoomAround(t.containerPoint,n)}});_.addInitHook("addHandler","doubleClickZoom",Oi);_.mergeOptions({dragging:!0,inertia:!0,inertiaDeceleration:3400,inertiaMaxSpeed:1/0,easeLinearity:.2,worldCopyJump:!1,maxBoundsViscosity:0});var Bi=G.extend({addHooks:function(){if(!this._draggable){var t=this._map;this._draggable=new dt(t._mapPane,t._container),this._draggable.on({dragstart:this._onDragStart,drag:this._onDrag,dragend:this._onDragEnd},this),this._draggable.on("predrag",this._onPreDragLimit,this),t.options.worldCopyJump&&(this._draggable.on("predrag",this._onPreDragWrap,this),t.on("zoomend",this._onZoomEnd,this),t.whenReady(this._onZoomEnd,this))}f(this._map._container,"leaflet-grab leaflet-touch-drag"),this._draggable.enable(),this._positions=[],this._times=[]},removeHooks:function(){k(this._map._container,"leaflet-grab"),k(this._map._container,"leaflet-touch-drag"),this._draggable.disable()},moved:function(){return this._draggable&&this._draggable._moved},moving:function(){return this._draggable&&this._draggable._moving},_onDragStart:function(){var t=this._map;if(t._stop(),this._map.options.maxBounds&&this._map.options.maxBoundsViscosity){var e=O(this._map.options.maxBounds);this._offsetLimit=W(this._map.latLngToContainerPoint(e.getNorthWest()).multiplyBy(-1),this._map.latLngToContainerPoint(e.getSouthEast()).multiplyBy(-1).add(this._map.getSize())),this._viscosity=Math.min(1,Math.max(0,this._map.options.maxBoundsViscosity))}else this._offsetLimit=null;t.fire("movestart").fire("dragstart"),t.options.inertia&&(this._positions=[],this._times=[])},_onDrag:function(t){if(this._map.options.inertia){var e=this._lastTime=+new Date,i=this._lastPos=this._draggable._absPos||this._draggable._newPos;this._positions.push(i),this._times.push(e),this._prunePositions(e)}this._map.fire("move",t).fire("drag",t)},_prunePositions:function(t){for(;this._positions.length>1&&t-this._times[0]>50;)this._positions.shift(),this._times.shift()},_onZoomEnd:function(){var t=this._map.getSize().divideBy(2),e=this._map.latLngToLayerPoint([0,0]);this._initialWorldOffset=e.subtract(t).x,this._worldWidth=this._map.getPixelWorldBounds().getSize().x},_viscousLimit:function(t,e){return t-(t-e)*this._viscosity},_onPreDragLimit:function(){if(!(!this._viscosity||!this._offsetLimit)){var t=this._draggable._newPos.subtract(this._draggable._startPos),e=this._offsetLimit;t.x<e.min.x&&(t.x=this._viscousLimit(t.x,e.min.x)),t.y<e.min.y&&(t.y=this._viscousLimit(t.y,e.min.y)),t.x>e.max.x&&(t.x=this._viscousLimit(t.x,e.max.x)),t.y>e.max.y&&(t.y=this._viscousLimit(t.y,e.max.y)),this._draggable._newPos=this._draggable._startPos.add(t)}},_onPreDragWrap:function(){var t=this._worldWidth,e=Math.round(t/2),i=this._initialWorldOffset,o=this._draggable._newPos.x,n=(o-e+i)%t+e-i,r=(o+e+i)%t-e-i,s=Math.abs(n+i)<Math.abs(r+i)?n:r;this._draggable._absPos=this._draggable._newPos.clone(),this._draggable._newPos.x=s},_onDragEnd:function(t){var e=this._map,i=e.options,o=!i.inertia||t.noInertia||this._times.length<2;if(e.fire("dragend",t),o)e.fire("moveend");else{this._prunePositions(+new Date);var n=this._lastPos.subtract(this._positions[0]),r=(this._lastTime-this._times[0])/1e3,s=i.easeLinearity,a=n.multiplyBy(s/r),h=a.distanceTo([0,0]),l=Math.min(i.inertiaMaxSpeed,h),m=a.multiplyBy(l/h),v=l/(i.inertiaDeceleration*s),D=m.multiplyBy(-v/2).round();!D.x&&!D.y?e.fire("moveend"):(D=e._limitOffset(D,e.options.maxBounds),N(function(){e.panBy(D,{duration:v,easeLinearity:s,noMoveStart:!0,animate:!0})}))}}});_.addInitHook("addHandler","dragging",Bi);_.mergeOptions({keyboard:!0,keyboardPanDelta:80});var Zi=G.extend({keyCodes:{left:[37],right:[39],down:[40],up:[38],zoomIn:[187,107,61,171],zoomOut:[189,109,54,173]},initialize:function(t){this._map=t,this._setPanDelta(t.options.keyboardPanDelta),this._setZoomDelta(t.options.zoomDelta)},addHooks:function(){var t=this._map._container;t.tabIndex<=0&&(t.tabIndex="0"),p(t,{focus:this._onFocus,blur:this._onBlur,mousedown:this._onMouseDown},this),this._map.on({focus:this._addHooks,blur:this._removeHooks},this)},removeHooks:function(){this._removeHooks(),C(this._map._container,{focus:this._onFocus,blur:this._onBlur,mousedown:this._onMouseDown},this),this._map.off({focus:this._addHooks,blur:this._removeHooks},this)},_onMouseDown:function(){if(!this._focused){var t=document.body,e=document.documentElement,i=t.scrollTop||e.scrollTop,o=t.scrollLeft||e.scrollLeft;this._map._container.focus(),window.scrollTo(o,i)}},_onFocus:function(){this._focused=!0,this._map.fire("focus")},_onBlur:function(){this._focused=!1,this._map.fire("blur")},_setPanDelta:function(t){var e=this._panKeys={},i=this.keyCodes,o,n;for(o=0,n=i.left.length;o<n;o++)e[i.left[o]]=[-1*t,0];for(o=0,n=i.right.length;o<n;o++)e[i.right[o]]=[t,0];for(o=0,n=i.down.length;o<n;o++)e[i.down[o]]=[0,t];for(o=0,n=i.up.length;o<n;o++)e[i.up[o]]=[0,-1*t]},_setZoomDelta:function(t){var e=this._zoomKeys={},i=this.keyCodes,o,n;for(o=0,n=i.zoomIn.length;o<n;o++)e[i.zoomIn[o]]=t;for(o=0,n=i.zoomOut.length;o<n;o++)e[i.zoomOut[o]]=-t},_addHooks:function(){p(document,"keydown",this._onKeyDown,this)},_removeHooks:function(){C(document,"keydown",this._onKeyDown,this)},_onKeyDown:function(t){if(!(t.altKey||t.ctrlKey||t.metaKey)){var e=t.keyCode,i=this._map,o;if(e in this._panKeys){if(!i._panAnim||!i._panAnim._inProgress)if(o=this._panKeys[e],t.shiftKey&&(o=d(o).multiplyBy(3)),i.options.maxBounds&&(o=i._limitOffset(d(o),i.options.maxBounds)),i.options.worldCopyJump){var n=i.wrapLatLng(i.unproject(i.project(i.getCenter()).add(o)));i.panTo(n)}else i.panBy(o)}else if(e in this._zoomKeys)i.setZoom(i.getZoom()+(t.shiftKey?3:1)*this._zoomKeys[e]);else if(e===27&&i._popup&&i._popup.options.closeOnEscapeKey)i.closePopup();else return;lt(t)}}});_.addInitHook("addHandler","keyboard",Zi);_.mergeOptions({scrollWheelZoom:!0,wheelDebounceTime:40,wheelPxPerZoomLevel:60});var Ai=G.extend({addHooks:function(){p(this._map._container,"wheel",this._onWheelScroll,this),this._delta=0},removeHooks:function(){C(this._map._container,"wheel",this._onWheelScroll,this)},_onWheelScroll:function(t){var e=mi(t),i=this._map.options.wheelDebounceTime;this._delta+=e,this._lastMousePos=this._map.mouseEventToContainerPoint(t),this._startTime||(this._startTime=+new Date);var o=Math.max(i-(+new Date-this._startTime),0);clearTimeout(this._timer),this._timer=setTimeout(U(this._performZoom,this),o),lt(t)},_performZoom:function(){var t=this._map,e=t.getZoom(),i=this._map.options.zoomSnap||0;t._stop();var o=this._delta/(this._map.options.wheelPxPerZoomLevel*4),n=4*Math.log(2/(1+Math.exp(-Math.abs(o))))/Math.LN2,r=i?Math.ceil(n/i)*i:n,s=t._limitZoom(e+(this._delta>0?r:-r))-e;this._delta=0,this._startTime=null,s&&(t.options.scrollWheelZoom==="center"?t.setZoom(e+s):t.setZoomAround(this._lastMousePos,e+s))}});_.addInitHook("addHandler","scrollWheelZoom",Ai);var jn=600;_.mergeOptions({tapHold:u.touchNative&&u.safari&&u.mobile,tapTolerance:15});var Ii=G.extend({addHooks:function(){p(this._map._container,"touchstart",this._onDown,this)},removeHooks:function(){C(this._map._container,"touchstart",this._onDown,this)},_onDown:function(t){if(clearTimeout(this._holdTimeout),t.touches.length===1){var e=t.touches[0];this._startPos=this._newPos=new c(e.clientX,e.clientY),this._holdTimeout=setTimeout(U(function(){this._cancel(),this._isTapValid()&&(p(document,"touchend",Z),p(document,"touchend touchcancel",this._cancelClickPrevent),this._simulateEvent("contextmenu",e))},this),jn),p(document,"touchend touchcancel contextmenu",this._cancel,this),p(document,"touchmove",this._onMove,this)}},_cancelClickPrevent:function t(){C(document,"touchend",Z),C(document,"touchend touchcancel",t)},_cancel:function(){clearTimeout(this._holdTimeout),C(document,"touchend touchcancel contextmenu",this._cancel,this),C(document,"touchmove",this._onMove,this)},_onMove:function(t){var e=t.touches[0];this._newPos=new c(e.clientX,e.clientY)},_isTapValid:function(){return this._newPos.distanceTo(this._startPos)<=this._map.options.tapTolerance},_simulateEvent:function(t,e){var i=new MouseEvent(t,{bubbles:!0,cancelable:!0,view:window,screenX:e.screenX,screenY:e.screenY,clientX:e.clientX,clientY:e.clientY});i._simulated=!0,e.target.dispatchEvent(i)}});_.addInitHook("addHandler","tapHold",Ii);_.mergeOptions({touchZoom:u.touch,bounceAtZoomLimits:!0});var Ni=G.extend({addHooks:function(){f(this._map._container,"leaflet-touch-zoom"),p(this._map._container,"touchstart",this._onTouchStart,this)},removeHooks:function(){k(this._map._container,"leaflet-touch-zoom"),C(this._map._container,"touchstart",this._onTouchStart,this)},_onTouchStart:function(t){var e=this._map;if(!(!t.touches||t.touches.length!==2||e._animatingZoom||this._zooming)){var i=e.mouseEventToContainerPoint(t.touches[0]),o=e.mouseEventToContainerPoint(t.touches[1]);this._centerPoint=e.getSize()._divideBy(2),this._startLatLng=e.containerPointToLatLng(this._centerPoint),e.options.touchZoom!=="center"&&(this._pinchStartLatLng=e.containerPointToLatLng(i.add(o)._divideBy(2))),this._startDist=i.distanceTo(o),this._startZoom=e.getZoom(),this._moved=!1,this._zooming=!0,e._stop(),p(document,"touchmove",this._onTouchMove,this),p(document,"touchend touchcancel",this._onTouchEnd,this),Z(t)}},_onTouchMove:function(t){if(!(!t.touches||t.touches.length!==2||!this._zooming)){var e=this._map,i=e.mouseEventToContainerPoint(t.touches[0]),o=e.mouseEventToContainerPoint(t.touches[1]),n=i.distanceTo(o)/this._startDist;if(this._zoom=e.getScaleZoom(n,this._startZoom),!e.options.bounceAtZoomLimits&&(this._zoom<e.getMinZoom()&&n<1||this._zoom>e.getMaxZoom()&&n>1)&&(this._zoom=e._limitZoom(this._zoom)),e.options.touchZoom==="center"){if(this._center=this._startLatLng,n===1)return}else{var r=i._add(o)._divideBy(2)._subtract(this._centerPoint);if(n===1&&r.x===0&&r.y===0)return;this._center=e.unproject(e.project(this._pinchStartLatLng,this._zoom).subtract(r),this._zoom)}this._moved||(e._moveStart(!0,!1),this._moved=!0),j(this._animRequest);var s=U(e._move,e,this._center,this._zoom,{pinch:!0,round:!1},void 0);this._animRequest=N(s,this,!0),Z(t)}},_onTouchEnd:function(){if(!this._moved||!this._zooming){this._zooming=!1;return}this._zooming=!1,j(this._animRequest),C(document,"touchmove",this._onTouchMove,this),C(document,"touchend touchcancel",this._onTouchEnd,this),this._map.options.zoomAnimation?this._map._animateZoom(this._center,this._map._limitZoom(this._zoom),!0,this._map.options.zoomSnap):this._map._resetView(this._center,this._map._limitZoom(this._zoom))}});_.addInitHook("addHandler","touchZoom",Ni);_.BoxZoom=zi;_.DoubleClickZoom=Oi;_.Drag=Bi;_.Keyboard=Zi;_.ScrollWheelZoom=Ai;_.TapHold=Ii;_.TouchZoom=Ni;return un(Vn);})();
@@ -0,0 +1,68 @@
+// Package web serves the map UI of the startHttp service, embedded in the binary
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the UI files, index.html on /
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		//the static folder is embedded at build time
+		panic(err)
+	}
+	return http.FileServer(http.FS(files))
+}
+
+// Area - the monitored area drawn by the UI as a GeoJSON FeatureCollection
+type Area struct {
+	Type     string    `json:"type"`
+	Bbox     []float64 `json:"bbox"` //Lon/Lat of the SW and NE corners
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string            `json:"type"`
+	Geometry   geometry          `json:"geometry"`
+	Properties map[string]string `json:"properties"`
+}
+
+type geometry struct {
+	Type        string           `json:"type"`
+	Coordinates [][][][2]float64 `json:"coordinates"`
+}
+
+// NewArea - the bbox polygon, or the zones polygons when zones are configured (kind property bbox or zone)
+func NewArea(bbox tools.Bbox, monitoredZones []zones.Zone) Area {
+	area := Area{
+		Type:     "FeatureCollection",
+		Bbox:     []float64{bbox.LonSW, bbox.LatSW, bbox.LonNE, bbox.LatNE},
+		Features: []feature{},
+	}
+	kind := "zone"
+	if len(monitoredZones) == 0 {
+		kind = "bbox"
+		monitoredZones = []zones.Zone{{Name: "bbox", Polygons: []tools.Polygon{tools.BboxToPolygon(bbox)}}}
+	}
+	for _, zone := range monitoredZones {
+		var coordinates [][][][2]float64
+		for _, polygon := range zone.Polygons {
+			coordinates = append(coordinates, polygon.Rings)
+		}
+		area.Features = append(area.Features, feature{
+			Type:       "Feature",
+			Geometry:   geometry{Type: "MultiPolygon", Coordinates: coordinates},
+			Properties: map[string]string{"name": zone.Name, "kind": kind},
+		})
+	}
+	return area
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
+)
+
+func TestHandler(t *testing.T) {
+	for _, path := range []string{"/", "/app.js", "/style.css", "/leaflet/leaflet.js", "/leaflet/leaflet.css", "/leaflet/images/marker-icon.png"} {
+		recorder := httptest.NewRecorder()
+		Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", path, recorder.Code)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(recorder.Body.String(), `<script src="app.js">`) {
+		t.Error("index.html expected on /")
+	}
+	if strings.Contains(recorder.Body.String(), "https://unpkg.com") {
+		t.Error("Leaflet expected from the embedded files")
+	}
+}
+
+func TestNewArea(t *testing.T) {
+	bbox := tools.Bbox{LatSW: 43.52, LonSW: 1.32, LatNE: 43.70, LonNE: 1.69}
+
+	area := NewArea(bbox, nil)
+	if len(area.Features) != 1 || area.Features[0].Properties["kind"] != "bbox" {
+		t.Fatalf("expected the bbox feature, got %+v", area.Features)
+	}
+	if ring := area.Features[0].Geometry.Coordinates[0][0]; len(ring) != 5 || ring[0] != [2]float64{1.32, 43.52} {
+		t.Errorf("expected the closed bbox ring in Lon/Lat, got %v", ring)
+	}
+
+	village := zones.Zone{Name: "village", Polygons: []tools.Polygon{tools.BboxToPolygon(bbox)}}
+	area = NewArea(bbox, []zones.Zone{village})
+	if len(area.Features) != 1 || area.Features[0].Properties["kind"] != "zone" || area.Features[0].Properties["name"] != "village" {
+		t.Errorf("expected the village zone feature, got %+v", area.Features)
+	}
+}
@@ -0,0 +1,7 @@
+package zones
+
+// Configuration settings for a named monitoring zone
+type Configuration struct {
+	Name string `toml:"name" comment:"zone name, used to tag the flights and in the rules"`
+	File string `toml:"file" comment:"WKT (POLYGON or MULTIPOLYGON) or GeoJSON (.geojson or .json) file of the zone"`
+}
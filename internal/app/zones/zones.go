@@ -0,0 +1,125 @@
+package zones
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+//Zone - a named monitoring area made of polygons
+type Zone struct {
+	Name     string
+	Polygons []tools.Polygon
+}
+
+func (z Zone) Contains(lat, lon float64) bool {
+	for _, polygon := range z.Polygons {
+		if polygon.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+// WKT - zone as a MULTIPOLYGON Well Known Text
+func (z Zone) WKT() string {
+	return tools.PolygonsToWKT(z.Polygons)
+}
+
+// Load reads the zone files
+func Load(confs []Configuration) ([]Zone, error) {
+	var result []Zone
+	names := map[string]bool{}
+	for _, conf := range confs {
+		if conf.Name == "" {
+			return nil, fmt.Errorf("zone %s - need a name", conf.File)
+		}
+		if names[conf.Name] {
+			return nil, fmt.Errorf("zone %s - declared twice", conf.Name)
+		}
+		names[conf.Name] = true
+
+		polygons, err := loadFile(conf.File)
+		if err != nil {
+			return nil, fmt.Errorf("zone %s - %v", conf.Name, err)
+		}
+		result = append(result, Zone{Name: conf.Name, Polygons: polygons})
+	}
+	return result, nil
+}
+
+func loadFile(path string) ([]tools.Polygon, error) {
+	byt, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".geojson" || ext == ".json" {
+		features, err := tools.ParseGeoJSON(byt)
+		if err != nil {
+			return nil, err
+		}
+		var result []tools.Polygon
+		for _, feature := range features {
+			result = append(result, feature.Polygons...)
+		}
+		return result, nil
+	}
+
+	return tools.ParseWKT(string(byt))
+}
+
+// Envelope - bbox of the union of the zones, used to fetch the provider data
+func Envelope(zones []Zone) tools.Bbox {
+	result := tools.Bbox{LatSW: 90, LonSW: 180, LatNE: -90, LonNE: -180}
+	for _, zone := range zones {
+		for _, polygon := range zone.Polygons {
+			envelope := polygon.Envelope()
+			if envelope.LatSW < result.LatSW {
+				result.LatSW = envelope.LatSW
+			}
+			if envelope.LonSW < result.LonSW {
+				result.LonSW = envelope.LonSW
+			}
+			if envelope.LatNE > result.LatNE {
+				result.LatNE = envelope.LatNE
+			}
+			if envelope.LonNE > result.LonNE {
+				result.LonNE = envelope.LonNE
+			}
+		}
+	}
+	return result
+}
+
+// Filter keeps the flights inside at least one zone, tagged with the names of the zones they are in
+func Filter(data []app.FlightData, zones []Zone) []app.FlightData {
+	var result []app.FlightData
+	for _, flight := range data {
+		flight.Zones = nil
+		for _, zone := range zones {
+			if zone.Contains(flight.Lat, flight.Lon) {
+				flight.Zones = append(flight.Zones, zone.Name)
+			}
+		}
+		if len(flight.Zones) > 0 {
+			result = append(result, flight)
+		}
+	}
+	return result
+}
+
+// Find returns the zone with the name
+func Find(zones []Zone, name string) (Zone, bool) {
+	for _, zone := range zones {
+		if zone.Name == name {
+			return zone, true
+		}
+	}
+	return Zone{}, false
+}
@@ -0,0 +1,133 @@
+package zones
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+)
+
+// zoneFiles - the village (WKT) inside the valley (GeoJSON), and the airport (WKT)
+func zoneFiles(t *testing.T) []Configuration {
+	dir := t.TempDir()
+	files := map[string]string{
+		"village.wkt":     "POLYGON((1.40 43.60, 1.42 43.60, 1.42 43.62, 1.40 43.62, 1.40 43.60))",
+		"valley.geojson":  `{"type": "Feature", "properties": {}, "geometry": {"type": "Polygon", "coordinates": [[[1.3, 43.5], [1.5, 43.5], [1.5, 43.7], [1.3, 43.7], [1.3, 43.5]]]}}`,
+		"airport.wkt":     "MULTIPOLYGON(((1.35 43.62, 1.38 43.62, 1.38 43.65, 1.35 43.65, 1.35 43.62)), ((1.6 43.8, 1.7 43.8, 1.7 43.9, 1.6 43.9, 1.6 43.8)))",
+		"empty.wkt":       "MULTIPOLYGON()",
+		"points.geojson":  `{"type": "Feature", "properties": {}, "geometry": {"type": "Point", "coordinates": [1.4, 43.6]}}`,
+		"malformed.wkt":   "POLYGON((1.40 43.60, 1.42",
+		"malformed.json":  `{"type": "Polygon"`,
+		"not-a-zone.toml": "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return []Configuration{
+		{Name: "village", File: filepath.Join(dir, "village.wkt")},
+		{Name: "valley", File: filepath.Join(dir, "valley.geojson")},
+		{Name: "airport", File: filepath.Join(dir, "airport.wkt")},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	confs := zoneFiles(t)
+	zones, err := Load(confs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 3 || zones[0].Name != "village" || len(zones[2].Polygons) != 2 {
+		t.Fatalf("unexpected zones %+v", zones)
+	}
+
+	dir := filepath.Dir(confs[0].File)
+	for name, conf := range map[string]Configuration{
+		"without name":            {File: confs[0].File},
+		"missing file":            {Name: "missing", File: filepath.Join(dir, "missing.wkt")},
+		"empty multipolygon":      {Name: "empty", File: filepath.Join(dir, "empty.wkt")},
+		"GeoJSON without polygon": {Name: "points", File: filepath.Join(dir, "points.geojson")},
+		"malformed WKT":           {Name: "malformed", File: filepath.Join(dir, "malformed.wkt")},
+		"malformed GeoJSON":       {Name: "malformed", File: filepath.Join(dir, "malformed.json")},
+		"neither WKT nor GeoJSON": {Name: "toml", File: filepath.Join(dir, "not-a-zone.toml")},
+	} {
+		if _, err := Load([]Configuration{confs[1], conf}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := Load([]Configuration{confs[0], {Name: "village", File: confs[1].File}}); err == nil {
+		t.Error("expected an error for a zone declared twice")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	zones, err := Load(zoneFiles(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []app.FlightData{
+		{FlightID: "village", Lat: 43.61, Lon: 1.41, Zones: []string{"previous"}},
+		{FlightID: "valley", Lat: 43.55, Lon: 1.45},
+		{FlightID: "airport", Lat: 43.85, Lon: 1.65},
+		{FlightID: "outside", Lat: 48.85, Lon: 2.35},
+	}
+	expected := map[string][]string{
+		"village": {"village", "valley"},
+		"valley":  {"valley"},
+		"airport": {"airport"},
+	}
+
+	result := Filter(data, zones)
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d flights, got %+v", len(expected), result)
+	}
+	for _, flight := range result {
+		if !reflect.DeepEqual(flight.Zones, expected[flight.FlightID]) {
+			t.Errorf("%s: expected zones %v, got %v", flight.FlightID, expected[flight.FlightID], flight.Zones)
+		}
+	}
+	if !reflect.DeepEqual(data[0].Zones, []string{"previous"}) {
+		t.Errorf("the input flights should not be modified, got %v", data[0].Zones)
+	}
+	if Filter(data, nil) != nil {
+		t.Error("expected no flight without zone")
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	zones, err := Load(zoneFiles(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := tools.Bbox{LatSW: 43.5, LonSW: 1.3, LatNE: 43.9, LonNE: 1.7}
+	if got := Envelope(zones); got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	expected = tools.Bbox{LatSW: 43.6, LonSW: 1.4, LatNE: 43.62, LonNE: 1.42}
+	if got := Envelope(zones[:1]); got != expected {
+		t.Errorf("expected the village envelope %+v, got %+v", expected, got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	zones, err := Load(zoneFiles(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if zone, ok := Find(zones, "airport"); !ok || zone.Name != "airport" || len(zone.Polygons) != 2 {
+		t.Errorf("expected the airport zone, got %+v", zone)
+	}
+	if _, ok := Find(zones, "Airport"); ok {
+		t.Error("the zone names are case sensitive")
+	}
+	if _, ok := Find(nil, "airport"); ok {
+		t.Error("expected no zone")
+	}
+}
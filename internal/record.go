@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/config"
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/archive"
+	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
+	"github.com/sirupsen/logrus"
+)
+
+//recordingProvider - save each raw response body before unmarshalling it
+type recordingProvider struct {
+	app.RawProvider
+	name   string
+	bbox   string
+	writer *archive.Writer
+}
+
+func (p *recordingProvider) Fetch(ctx context.Context, bbox tools.Bbox) ([]app.FlightData, error) {
+	fetchTime := time.Now()
+	body, errRaw := p.GetRawData(ctx, bbox)
+	if errRaw != nil {
+		return nil, errRaw
+	}
+
+	errWrite := p.writer.Write(archive.Record{
+		Timestamp: fetchTime,
+		Provider:  p.name,
+		Bbox:      p.bbox,
+		Body:      body,
+	})
+	if errWrite != nil {
+		return nil, errWrite
+	}
+
+	return p.UnMarshalByte(ctx, body, bbox)
+}
+
+//Record - start the worker and save every raw provider response in the archive
+func Record(ctx context.Context,
+	log *logrus.Logger,
+	conf config.Configuration,
+	output string) error {
+
+	log.WithContext(ctx).WithFields(logrus.Fields{
+		"bbox":       conf.Flighttracker.Bbox,
+		"provider":   conf.Flighttracker.Provider,
+		"sinkerType": conf.Flighttracker.Sinkertype,
+		"output":     output,
+	}).Info("RECORD with Configuration params: ")
+
+	bboxStruct, monitoredZones, errArea := monitoredArea(conf)
+	if errArea != nil {
+		log.WithContext(ctx).WithFields(logrus.Fields{
+			"Error": errArea,
+		}).Error("Unable to interpret parameter bbox or zones")
+		return errArea
+	}
+
+	provider, errProvider := newProvider(ctx, log, conf)
+	if errProvider != nil {
+		log.WithContext(ctx).Error(errProvider)
+		return errProvider
+	}
+	rawProvider, ok := provider.(app.RawProvider)
+	if !ok {
+		return errors.New("Provider " + conf.Flighttracker.Provider + " doesn't expose raw responses, it can't be recorded")
+	}
+
+	writer, errCreate := archive.Create(output)
+	if errCreate != nil {
+		log.WithContext(ctx).Error(errCreate)
+		return errCreate
+	}
+	defer writer.Close()
+
+	recorder := &recordingProvider{
+		RawProvider: rawProvider,
+		name:        conf.Flighttracker.Provider,
+		bbox:        fmt.Sprintf("%f,%f^%f,%f", bboxStruct.LatSW, bboxStruct.LonSW, bboxStruct.LatNE, bboxStruct.LonNE),
+		writer:      writer,
+	}
+
+	return run(ctx, log, conf, bboxStruct, monitoredZones, recorder)
+}
+
+//Replay - feed the recorded raw responses to the configured sinker
+// speed accelerate the original timing (1 = original timing, 0 = without waiting)
+func Replay(ctx context.Context,
+	log *logrus.Logger,
+	conf config.Configuration,
+	from string,
+	speed float64) error {
+
+	log.WithContext(ctx).WithFields(logrus.Fields{
+		"from":       from,
+		"speed":      speed,
+		"sinkerType": conf.Flighttracker.Sinkertype,
+	}).Info("REPLAY with Configuration params: ")
+
+	//the recorded bbox is used, only the zones are needed
+	monitoredZones, errZones := zones.Load(conf.Flighttracker.Zones)
+	if errZones != nil {
+		log.WithContext(ctx).WithFields(logrus.Fields{
+			"Error": errZones,
+		}).Error("Unable to interpret parameter zones")
+		return errZones
+	}
+
+	reader, errOpen := archive.Open(from)
+	if errOpen != nil {
+		log.WithContext(ctx).Error(errOpen)
+		return errOpen
+	}
+	defer reader.Close()
+
+	sinker, errSinker := newSinker(ctx, log, conf)
+	if errSinker != nil {
+		log.WithContext(ctx).Error(errSinker)
+		return errSinker
+	}
+	defer closeSinker(sinker)
+
+	tracker := newTracker(conf)
+	providers := map[string]app.RawProvider{}
+	var previous time.Time
+	nbRecord := 0
+
+	for {
+		record, errNext := reader.Next()
+		if errNext == io.EOF {
+			break
+		}
+		if errNext != nil {
+			log.WithContext(ctx).Error(errNext)
+			return errNext
+		}
+
+		if speed > 0 && !previous.IsZero() {
+			select {
+			case <-time.After(time.Duration(float64(record.Timestamp.Sub(previous)) / speed)):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		previous = record.Timestamp
+
+		//the recorded provider is used for unmarshalling, whatever the configured one
+		provider, ok := providers[record.Provider]
+		if !ok {
+			recordConf := conf
+			recordConf.Flighttracker.Provider = record.Provider
+			newOne, errProvider := newProvider(ctx, log, recordConf)
+			if errProvider != nil {
+				return errProvider
+			}
+			if provider, ok = newOne.(app.RawProvider); !ok {
+				return errors.New("Provider " + record.Provider + " can't unmarshal raw responses")
+			}
+			providers[record.Provider] = provider
+		}
+
+		bbox, errBbox := tools.GetBbox(record.Bbox)
+		if errBbox != nil {
+			return errBbox
+		}
+
+		data, errUnmarshal := provider.UnMarshalByte(ctx, record.Body, bbox)
+		if errUnmarshal != nil {
+			log.WithContext(ctx).WithFields(logrus.Fields{
+				"Warning":   errUnmarshal,
+				"timestamp": record.Timestamp,
+			}).Warning("Unable to unmarshal recorded data")
+			continue
+		}
+
+		if len(monitoredZones) > 0 {
+			data = zones.Filter(data, monitoredZones)
+		}
+
+		errSink := sinker.Sink(ctx, record.Timestamp, data)
+		if errSink != nil {
+			log.WithContext(ctx).Error(errSink)
+		}
+		if tracker != nil {
+			sinkTracks(ctx, log, sinker, record.Timestamp, tracker.Update(record.Timestamp, data))
+		}
+		nbRecord++
+	}
+
+	if tracker != nil {
+		flushTracks(ctx, log, sinker, previous, tracker)
+	}
+
+	log.WithContext(ctx).WithFields(logrus.Fields{
+		"records": nbRecord,
+	}).Info("Replay done")
+
+	return nil
+}
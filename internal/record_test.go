@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/config"
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+//captured - the ticks sunk by the CAPTURE sinker
+var captured struct {
+	sync.Mutex
+	ticks [][]app.FlightData
+}
+
+type captureSinker struct{}
+
+func (s *captureSinker) Init(ctx context.Context, conf struct{}) error {
+	return nil
+}
+
+func (s *captureSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	captured.Lock()
+	defer captured.Unlock()
+	captured.ticks = append(captured.ticks, data)
+	return nil
+}
+
+func capturedTicks() [][]app.FlightData {
+	captured.Lock()
+	defer captured.Unlock()
+	ticks := captured.ticks
+	captured.ticks = nil
+	return ticks
+}
+
+func TestRecordReplay(t *testing.T) {
+	var conf config.Configuration
+	conf.Flighttracker.Bbox = "43.52,1.32^43.70,1.69"
+	conf.Flighttracker.Refresh = 1
+	conf.Flighttracker.Provider = "ADSBX"
+	conf.Flighttracker.Adsbx.Url = "app/providers/adsbx/testdata/aircraft.json"
+	conf.Flighttracker.Sinkertype = "CAPTURE"
+	output := filepath.Join(t.TempDir(), "record.gz")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	if errRecord := Record(ctx, log, conf, output); errRecord != nil {
+		t.Fatal(errRecord)
+	}
+	recorded := capturedTicks()
+	if len(recorded) != 2 {
+		t.Fatalf("expected 2 recorded ticks, got %d", len(recorded))
+	}
+
+	//the replay unmarshals the recorded bodies with the recorded provider, whatever the configured one
+	conf.Flighttracker.Provider = "FR24"
+	if errReplay := Replay(context.Background(), log, conf, output, 0); errReplay != nil {
+		t.Fatal(errReplay)
+	}
+	replayed := capturedTicks()
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("expected the recorded ticks to be replayed, got %+v", replayed)
+	}
+	if len(replayed[0]) != 3 {
+		t.Errorf("expected 3 flights by tick, got %d", len(replayed[0]))
+	}
+}
+
+func init() {
+	//log handling
+	log = logrus.New()
+	log.Formatter = new(logrus.TextFormatter)                     //default
+	log.Formatter.(*logrus.TextFormatter).DisableColors = true    // remove colors
+	log.Formatter.(*logrus.TextFormatter).DisableTimestamp = true // remove timestamp from test output
+	log.Level = logrus.TraceLevel
+	log.Out = os.Stdout
+
+	sinkers.Register("CAPTURE", "", func(deps sinkers.Dependencies) sinkers.Sinker[struct{}] {
+		return &captureSinker{}
+	})
+}
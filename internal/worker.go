@@ -2,32 +2,44 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"reflect"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/francois-poidevin/flighttracker/config"
 	"github.com/francois-poidevin/flighttracker/internal/app"
-	pgSinker "github.com/francois-poidevin/flighttracker/internal/app/sinkers/db"
-	fileSinker "github.com/francois-poidevin/flighttracker/internal/app/sinkers/file"
-	stdoutSinker "github.com/francois-poidevin/flighttracker/internal/app/sinkers/stdout"
+	"github.com/francois-poidevin/flighttracker/internal/app/dedup"
+	adsbxProvider "github.com/francois-poidevin/flighttracker/internal/app/providers/adsbx"
+	fr24Provider "github.com/francois-poidevin/flighttracker/internal/app/providers/fr24"
+	openskyProvider "github.com/francois-poidevin/flighttracker/internal/app/providers/opensky"
+	sbsProvider "github.com/francois-poidevin/flighttracker/internal/app/providers/sbs"
+	"github.com/francois-poidevin/flighttracker/internal/app/rules"
+	"github.com/francois-poidevin/flighttracker/internal/app/sinkers"
+	_ "github.com/francois-poidevin/flighttracker/internal/app/sinkers/all"
+	fanoutSinker "github.com/francois-poidevin/flighttracker/internal/app/sinkers/fanout"
 	"github.com/francois-poidevin/flighttracker/internal/app/tools"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+	"github.com/francois-poidevin/flighttracker/internal/app/zones"
 	"github.com/sirupsen/logrus"
 )
 
-//Execute - start the worker
+//delay to write the open tracks when the worker stops
+const flushTimeout = 30 * time.Second
+
+//dedupSinkerTypes - the database sinkers storing each position once when dedup is enabled
+var dedupSinkerTypes = map[string]bool{"DB": true, "SQLITE": true}
+
+//Execute - start the worker, the observers (i.e. the live feed) receive each tick in addition to the configured sinkers
 func Execute(ctx context.Context,
 	log *logrus.Logger,
-	conf config.Configuration) error {
+	conf config.Configuration,
+	observers ...app.Sinker) error {
 
 	log.WithContext(ctx).WithFields(logrus.Fields{
 		"bbox":                 conf.Flighttracker.Bbox,
 		"refreshTime (sec)":    conf.Flighttracker.Refresh,
+		"provider":             conf.Flighttracker.Provider,
 		"outputRawFileName":    conf.Flighttracker.File.Outputraw,
 		"outputReportFileName": conf.Flighttracker.File.Outputreport,
 		"sinkerType":           conf.Flighttracker.Sinkertype,
@@ -38,61 +50,230 @@ func Execute(ctx context.Context,
 		"dbName":               conf.Flighttracker.Postgres.Dbname,
 	}).Info("START with Configuration params: ")
 
-	//interprete bbox parameter
-	bboxStruct, errBbox := tools.GetBbox(conf.Flighttracker.Bbox)
-	if errBbox != nil {
+	//interprete bbox and zones parameters
+	bboxStruct, monitoredZones, errArea := monitoredArea(conf)
+	if errArea != nil {
 		log.WithContext(ctx).WithFields(logrus.Fields{
-			"Error": errBbox,
-		}).Error("Unable to interpret parameter bbox")
-		return errBbox
-	}
-
-	if conf.Flighttracker.Sinkertype == "FILE" {
-		log.WithContext(ctx).Info("Initiate File Sinker")
-		sinker := fileSinker.New(log)
-		//init sinker object (files)
-		errInit := sinker.Init(ctx, conf.Flighttracker.File)
-		if errInit != nil {
-			log.WithContext(ctx).Error(errInit)
-			return errInit
-		}
-		//launch the ticking
-		errFileSink := ticking(ctx, conf.Flighttracker.Refresh, bboxStruct, sinker, log)
-		if errFileSink != nil {
-			log.WithContext(ctx).Error(errFileSink)
-			return errFileSink
-		}
-	} else if conf.Flighttracker.Sinkertype == "STDOUT" {
-		log.WithContext(ctx).Info("Initiate stdOut Sinker")
-		sinker := stdoutSinker.New(log)
-		//launch the ticking
-		errStdOutSink := ticking(ctx, conf.Flighttracker.Refresh, bboxStruct, sinker, log)
-		if errStdOutSink != nil {
-			log.WithContext(ctx).Error(errStdOutSink)
-			return errStdOutSink
-		}
-	} else if conf.Flighttracker.Sinkertype == "DB" {
-		log.WithContext(ctx).Info("Initiate DB Sinker")
-		sinker := pgSinker.New(log)
-		//init sinker object (files)
-		errInit := sinker.Init(ctx, conf.Flighttracker.Postgres)
-		if errInit != nil {
-			log.WithContext(ctx).Error(errInit)
-			return errInit
-		}
-		errDBSink := ticking(ctx, conf.Flighttracker.Refresh, bboxStruct, sinker, log)
-		if errDBSink != nil {
-			log.WithContext(ctx).Error(errDBSink)
-			return errDBSink
+			"Error": errArea,
+		}).Error("Unable to interpret parameter bbox or zones")
+		return errArea
+	}
+
+	provider, errProvider := newProvider(ctx, log, conf)
+	if errProvider != nil {
+		log.WithContext(ctx).Error(errProvider)
+		return errProvider
+	}
+
+	return run(ctx, log, conf, bboxStruct, monitoredZones, provider, observers...)
+}
+
+//monitoredArea - the bbox fetched from the provider and the zones filtering the flights
+// when zones are configured the bbox is the envelope of their union
+func monitoredArea(conf config.Configuration) (tools.Bbox, []zones.Zone, error) {
+	if len(conf.Flighttracker.Zones) == 0 {
+		bbox, errBbox := tools.GetBbox(conf.Flighttracker.Bbox)
+		return bbox, nil, errBbox
+	}
+
+	monitoredZones, errZones := zones.Load(conf.Flighttracker.Zones)
+	if errZones != nil {
+		return tools.Bbox{}, nil, errZones
+	}
+	return zones.Envelope(monitoredZones), monitoredZones, nil
+}
+
+//zoneProvider - keep only the flights inside the zones, tagged with the zone names
+type zoneProvider struct {
+	app.Provider
+	zones []zones.Zone
+}
+
+func (p *zoneProvider) Fetch(ctx context.Context, bbox tools.Bbox) ([]app.FlightData, error) {
+	data, err := p.Provider.Fetch(ctx, bbox)
+	if err != nil {
+		return nil, err
+	}
+	return zones.Filter(data, p.zones), nil
+}
+
+//dedupSinker - drop the positions already written by the database sinker, a position is
+// remembered once its tick is written so a failed write is retried with the next tick
+type dedupSinker struct {
+	app.Sinker
+	cache *dedup.Cache
+}
+
+func (s *dedupSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	data = s.cache.Filter(data)
+	if err := s.Sinker.Sink(ctx, t, data); err != nil {
+		return err
+	}
+	s.cache.Add(data)
+	return nil
+}
+
+func (s *dedupSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+	if trackSinker, ok := s.Sinker.(app.TrackSinker); ok {
+		return trackSinker.SinkTracks(ctx, t, tracks)
+	}
+	return nil
+}
+
+func (s *dedupSinker) Close() {
+	closeSinker(s.Sinker)
+}
+
+//run - sink the provider data with the configured sinker until ctx is done
+func run(ctx context.Context, log *logrus.Logger, conf config.Configuration, bbox tools.Bbox, monitoredZones []zones.Zone, provider app.Provider, observers ...app.Sinker) error {
+	sinker, errSinker := newSinker(ctx, log, conf, observers...)
+	if errSinker != nil {
+		log.WithContext(ctx).Error(errSinker)
+		return errSinker
+	}
+	defer closeSinker(sinker)
+
+	if len(monitoredZones) > 0 {
+		provider = &zoneProvider{Provider: provider, zones: monitoredZones}
+	}
+
+	//launch the ticking
+	errSink := ticking(ctx, conf.Flighttracker.Refresh, bbox, provider, sinker, newTracker(conf), log)
+	if errSink != nil {
+		log.WithContext(ctx).Error(errSink)
+		return errSink
+	}
+
+	return nil
+}
+
+//newSinker - the configured sinker, a fan-out when several sinker types or observers are configured
+func newSinker(ctx context.Context, log *logrus.Logger, conf config.Configuration, observers ...app.Sinker) (app.Sinker, error) {
+	rulesEngine, errRules := rules.New(conf.Flighttracker.Rules)
+	if errRules != nil {
+		return nil, errRules
+	}
+
+	sinkerTypes := conf.Sinkertypes()
+	if len(sinkerTypes) == 1 && len(observers) == 0 {
+		return newSinkerOfType(ctx, log, conf, sinkerTypes[0], rulesEngine)
+	}
+	if len(sinkerTypes) == 0 {
+		return nil, errors.New("Wrong sinker specified")
+	}
+
+	log.WithContext(ctx).WithFields(logrus.Fields{
+		"sinkers": sinkerTypes,
+	}).Info("Initiate FanOut Sinker")
+	fanOut := fanoutSinker.New(log)
+	for _, sinkerType := range sinkerTypes {
+		sinker, errSinker := newSinkerOfType(ctx, log, conf, sinkerType, rulesEngine)
+		if errSinker != nil {
+			return nil, errSinker
 		}
+		fanOut.Add(sinkerType, sinker)
+	}
+	for idx, observer := range observers {
+		fanOut.Add(fmt.Sprintf("observer-%d", idx+1), observer)
+	}
+
+	errInit := fanOut.Init(ctx, conf.Flighttracker.Fanout)
+	if errInit != nil {
+		return nil, errInit
+	}
+	return fanOut, nil
+}
+
+func newSinkerOfType(ctx context.Context, log *logrus.Logger, conf config.Configuration, sinkerType string, rulesEngine *rules.Engine) (app.Sinker, error) {
+	log.WithContext(ctx).Info("Initiate " + sinkerType + " Sinker")
+	deps := sinkers.Dependencies{Log: log, Rules: rulesEngine}
+	sinker, errSinker := sinkers.New(ctx, sinkerType, deps, conf.Section)
+	if errSinker != nil {
+		return nil, errSinker
+	}
+
+	//the other sinkers (live feed, alerts...) receive every position
+	if cache := newDedupCache(conf); cache != nil && dedupSinkerTypes[strings.ToUpper(sinkerType)] {
+		sinker = &dedupSinker{Sinker: sinker, cache: cache}
+	}
+	return sinker, nil
+}
+
+//closeSinker - wait for the ticks queued by a fan-out sinker and the pending writes of the sinkers
+func closeSinker(sinker app.Sinker) {
+	if closer, ok := sinker.(app.Closer); ok {
+		closer.Close()
+	}
+}
+
+func newProvider(ctx context.Context, log *logrus.Logger, conf config.Configuration) (app.Provider, error) {
+	var provider app.Provider
+	var params interface{}
+
+	if conf.Flighttracker.Provider == "FR24" || conf.Flighttracker.Provider == "" {
+		log.WithContext(ctx).Info("Initiate FlightRadar24 Provider")
+		provider = fr24Provider.New(log)
+	} else if conf.Flighttracker.Provider == "OPENSKY" {
+		log.WithContext(ctx).Info("Initiate OpenSky Provider")
+		provider = openskyProvider.New(log)
+		params = conf.Flighttracker.Opensky
+	} else if conf.Flighttracker.Provider == "ADSBX" {
+		log.WithContext(ctx).Info("Initiate aircraft.json Provider")
+		provider = adsbxProvider.New(log)
+		params = conf.Flighttracker.Adsbx
+	} else if conf.Flighttracker.Provider == "SBS" {
+		//streaming source: the TCP stream is listened from Init and each tick sinks a snapshot
+		log.WithContext(ctx).Info("Initiate SBS-1 Provider")
+		provider = sbsProvider.New(log)
+		params = conf.Flighttracker.Sbs
 	} else {
-		return errors.New("Wrong sinker specified")
+		return nil, errors.New("Wrong provider specified")
 	}
 
-	return nil
+	errInit := provider.Init(ctx, params)
+	if errInit != nil {
+		return nil, errInit
+	}
+	return provider, nil
 }
 
-func ticking(ctx context.Context, refreshTime int, bbox tools.Bbox, sinker app.Sinker, log *logrus.Logger) error {
+//newTracker - nil when the tracking is disabled
+func newTracker(conf config.Configuration) *tracking.Tracker {
+	if !conf.Flighttracker.Tracking.Enabled {
+		return nil
+	}
+	return tracking.New(time.Duration(conf.Flighttracker.Tracking.Timeout) * time.Second)
+}
+
+//newDedupCache - nil when the deduplication is disabled, a cache by database sinker
+func newDedupCache(conf config.Configuration) *dedup.Cache {
+	if !conf.Flighttracker.Dedup.Enabled {
+		return nil
+	}
+	return dedup.New(conf.Flighttracker.Dedup.Size)
+}
+
+//sinkTracks - send the completed tracks to the sinker if it handles them
+func sinkTracks(ctx context.Context, log *logrus.Logger, sinker app.Sinker, t time.Time, tracks []app.Track) {
+	trackSinker, ok := sinker.(app.TrackSinker)
+	if !ok || len(tracks) == 0 {
+		return
+	}
+	errSink := trackSinker.SinkTracks(ctx, t, tracks)
+	if errSink != nil {
+		log.WithContext(ctx).Error(errSink)
+	}
+}
+
+//flushTracks - send the open tracks to the sinker when the worker stops, ctx is usually cancelled
+// so the tracks are written with a fresh context
+func flushTracks(ctx context.Context, log *logrus.Logger, sinker app.Sinker, t time.Time, tracker *tracking.Tracker) {
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+	defer cancel()
+	sinkTracks(flushCtx, log, sinker, t, tracker.Flush())
+}
+
+func ticking(ctx context.Context, refreshTime int, bbox tools.Bbox, provider app.Provider, sinker app.Sinker, tracker *tracking.Tracker, log *logrus.Logger) error {
 	//Loop each <bbox parameter> secondes for working
 	d := time.Duration(refreshTime) * time.Second
 	ticker := time.NewTicker(d)
@@ -101,129 +282,28 @@ func ticking(ctx context.Context, refreshTime int, bbox tools.Bbox, sinker app.S
 		select {
 		case <-ticker.C:
 			//get Raw datas
-			rawData, errRaw := getRawData(ctx, bbox, log)
+			rawData, errRaw := provider.Fetch(ctx, bbox)
 			if errRaw != nil {
 				log.WithContext(ctx).WithFields(logrus.Fields{
 					"Warning": errRaw,
 				}).Warning("Unable to get Raw data")
 			} else {
-				errSink := sinker.Sink(ctx, time.Now(), rawData)
+				now := time.Now()
+				errSink := sinker.Sink(ctx, now, rawData)
 				if errSink != nil {
 					log.WithContext(ctx).Error(errSink)
 				}
+				if tracker != nil {
+					sinkTracks(ctx, log, sinker, now, tracker.Update(now, rawData))
+				}
 			}
 		case <-ctx.Done():
 			ticker.Stop()
 			log.WithContext(ctx).Info("Stop the ticker")
-			return nil
-		}
-	}
-}
-
-func getRawData(ctx context.Context, bbox tools.Bbox, log *logrus.Logger) ([]app.FlightData, error) {
-	// Made the HTTP request - Test area 43.663712,1.570358,43.710510,1.700735
-	// Toulouse and Airport Area - 43.515693,1.318359,43.702630,1.687775
-	bounds := fmt.Sprintf("%.2f", bbox.LatNE) + "," + fmt.Sprintf("%.2f", bbox.LatSW) + "," + fmt.Sprintf("%.2f", bbox.LonSW) + "," + fmt.Sprintf("%.2f", bbox.LonNE)
-	resp, errHTTPGet := http.Get("https://data-live.flightradar24.com/zones/fcgi/feed.js?bounds=" + bounds + "&faa=1&satellite=1&mlat=1&flarm=1&adsb=1&gnd=1&air=1&vehicles=1&estimated=1&maxage=14400&gliders=1&stats=1")
-	if errHTTPGet != nil {
-		return nil, errHTTPGet
-	}
-	defer func() {
-		resp.Body.Close()
-	}()
-
-	if resp.StatusCode != 200 {
-		return nil, errors.New(fmt.Sprintf("HTTP status code is : %d", resp.StatusCode))
-	}
-
-	body, errRead := ioutil.ReadAll(resp.Body)
-	if errRead != nil {
-		return nil, errRead
-	}
-
-	return unMarshalByte(ctx, body, log)
-}
-
-func unMarshalByte(ctx context.Context, byt []byte, log *logrus.Logger) ([]app.FlightData, error) {
-
-	var data map[string]interface{}
-	var result []app.FlightData
-	if err := json.Unmarshal(byt, &data); err != nil {
-		return nil, err
-	}
-
-	for k, v := range data {
-		if k != "full_count" && k != "version" && k != "stats" {
-			if reflect.TypeOf(v).Kind() == reflect.Slice {
-				s := reflect.ValueOf(v)
-				_lat, err := strconv.ParseFloat(fmt.Sprintf("%v", s.Index(1)), 64)
-				if err != nil {
-					log.WithContext(ctx).WithFields(logrus.Fields{
-						"Error in parsing _lat :": err,
-					}).Error()
-				}
-				_lon, err := strconv.ParseFloat(fmt.Sprintf("%v", s.Index(2)), 64)
-				if err != nil {
-					log.WithContext(ctx).WithFields(logrus.Fields{
-						"Error in parsing _lon :": err,
-					}).Error()
-				}
-				_track, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(3)), 10, 64)
-				if err != nil {
-					log.WithContext(ctx).WithFields(logrus.Fields{
-						"Error in parsing _track :": err,
-					}).Error()
-				}
-				_altitude, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(4)), 10, 64)
-				if err != nil {
-					log.WithContext(ctx).WithFields(logrus.Fields{
-						"Error in parsing _altitude :": err,
-					}).Error()
-				}
-				_groundSpeed, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(5)), 10, 64)
-				if err != nil {
-					log.WithContext(ctx).WithFields(logrus.Fields{
-						"Error in parsing _groundSpeed :": err,
-					}).Error()
-				}
-				_timeStamp, err := strconv.ParseFloat(fmt.Sprintf("%f", s.Index(10)), 64)
-				if err != nil {
-					log.WithContext(ctx).WithFields(logrus.Fields{
-						"Error in parsing _timeStamp :": err,
-					}).Error()
-				}
-				_verticalSpeed, err := strconv.ParseInt(fmt.Sprintf("%v", s.Index(14)), 10, 64)
-				if err != nil {
-					log.WithContext(ctx).WithFields(logrus.Fields{
-						"Error in parsing _verticalSpeed :": err,
-					}).Error()
-				}
-
-				flightData := app.FlightData{
-					FlightID:         k,
-					ICAO24BITADDRESS: fmt.Sprintf("%v", s.Index(0)),
-					Lat:              _lat,
-					Lon:              _lon,
-					Track:            _track,
-					Altitude:         _altitude,
-					GroundSpeed:      _groundSpeed,
-					Unknown1:         fmt.Sprintf("%v", s.Index(6)),
-					TranspondeurType: fmt.Sprintf("%v", s.Index(7)),
-					AircraftType:     fmt.Sprintf("%v", s.Index(8)),
-					Immatriculation1: fmt.Sprintf("%v", s.Index(9)),
-					TimeStamp:        _timeStamp,
-					Origine:          fmt.Sprintf("%v", s.Index(11)),
-					Destination:      fmt.Sprintf("%v", s.Index(12)),
-					Unknown2:         fmt.Sprintf("%v", s.Index(13)),
-					VerticalSpeed:    _verticalSpeed,
-					Immatriculation2: fmt.Sprintf("%v", s.Index(15)),
-					Hint:             fmt.Sprintf("%v", s.Index(16)),
-					Company:          fmt.Sprintf("%v", s.Index(17)),
-				}
-				result = append(result, flightData)
+			if tracker != nil {
+				flushTracks(ctx, log, sinker, time.Now(), tracker)
 			}
+			return nil
 		}
 	}
-
-	return result, nil
 }
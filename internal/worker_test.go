@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/francois-poidevin/flighttracker/internal/app"
+	"github.com/francois-poidevin/flighttracker/internal/app/dedup"
+	"github.com/francois-poidevin/flighttracker/internal/app/tracking"
+)
+
+//trackSinker - record the tracks and the state of the context they are sunk with
+type trackSinker struct {
+	tracks []app.Track
+	errCtx error
+}
+
+func (s *trackSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	return nil
+}
+
+func (s *trackSinker) SinkTracks(ctx context.Context, t time.Time, tracks []app.Track) error {
+	s.tracks = append(s.tracks, tracks...)
+	s.errCtx = ctx.Err()
+	return nil
+}
+
+func TestFlushTracksAfterCancel(t *testing.T) {
+	now := time.Now()
+	tracker := tracking.New(time.Minute)
+	tracker.Update(now, []app.FlightData{{FlightID: "A", TimeStamp: float64(now.Unix())}})
+
+	//the worker context is cancelled when the worker stops
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sinker := &trackSinker{}
+	flushTracks(ctx, log, sinker, now, tracker)
+	if len(sinker.tracks) != 1 || sinker.errCtx != nil {
+		t.Errorf("expected the open track sunk with a live context, got %d tracks (%v)", len(sinker.tracks), sinker.errCtx)
+	}
+}
+
+//failingSinker - fail the first writes, then record the written positions
+type failingSinker struct {
+	failures int
+	written  []app.FlightData
+}
+
+func (s *failingSinker) Sink(ctx context.Context, t time.Time, data []app.FlightData) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("database unavailable")
+	}
+	s.written = append(s.written, data...)
+	return nil
+}
+
+func TestDedupSinker(t *testing.T) {
+	database := &failingSinker{failures: 1}
+	sinker := &dedupSinker{Sinker: database, cache: dedup.New(10)}
+	stale := app.FlightData{FlightID: "A", TimeStamp: 1}
+	fresh := app.FlightData{FlightID: "A", TimeStamp: 2}
+
+	if err := sinker.Sink(context.Background(), time.Now(), []app.FlightData{stale}); err == nil {
+		t.Fatal("expected the error of the database")
+	}
+	//the position of the failed tick is written by the next one, then dropped
+	for _, data := range [][]app.FlightData{{stale}, {stale, fresh}, {stale, fresh}} {
+		if err := sinker.Sink(context.Background(), time.Now(), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(database.written) != 2 || database.written[0].TimeStamp != 1 || database.written[1].TimeStamp != 2 {
+		t.Errorf("expected each position written once, got %+v", database.written)
+	}
+}